@@ -0,0 +1,546 @@
+// Command server runs the tools-3 HTTP API.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/dohasheriff/tools-3/internal/audit"
+	"github.com/dohasheriff/tools-3/internal/auth"
+	"github.com/dohasheriff/tools-3/internal/comments"
+	"github.com/dohasheriff/tools-3/internal/config"
+	"github.com/dohasheriff/tools-3/internal/db"
+	"github.com/dohasheriff/tools-3/internal/db/migrations"
+	"github.com/dohasheriff/tools-3/internal/digest"
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/googlecalendar"
+	"github.com/dohasheriff/tools-3/internal/httpapi"
+	"github.com/dohasheriff/tools-3/internal/invitations"
+	"github.com/dohasheriff/tools-3/internal/msgraphcalendar"
+	"github.com/dohasheriff/tools-3/internal/notifications"
+	"github.com/dohasheriff/tools-3/internal/polls"
+	"github.com/dohasheriff/tools-3/internal/realtime"
+	"github.com/dohasheriff/tools-3/internal/reminders"
+	"github.com/dohasheriff/tools-3/internal/slack"
+	"github.com/dohasheriff/tools-3/internal/storage"
+	"github.com/dohasheriff/tools-3/internal/tickets"
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+// quorumCheckInterval is how often tentative events are checked for expired
+// RSVP deadlines that missed their attendee quorum.
+const quorumCheckInterval = 5 * time.Minute
+
+// trendingRecomputeInterval is how often trending scores are refreshed from
+// recent joins and invitation accepts.
+const trendingRecomputeInterval = 10 * time.Minute
+
+// archiveCheckInterval is how often ended events are swept into the
+// archived state.
+const archiveCheckInterval = 30 * time.Minute
+
+// purgeDeletedCheckInterval is how often soft-deleted events whose restore
+// grace period has expired are purged for good.
+const purgeDeletedCheckInterval = 6 * time.Hour
+
+// invitationExpiryCheckInterval is how often stale pending invitations are
+// swept into the expired state.
+const invitationExpiryCheckInterval = 1 * time.Hour
+
+// invitationReminderCheckInterval is how often unanswered invitations are
+// checked for a due follow-up or RSVP-deadline reminder.
+const invitationReminderCheckInterval = 1 * time.Hour
+
+// eventReminderCheckInterval is how often attendees are checked for a due
+// event reminder.
+const eventReminderCheckInterval = 15 * time.Minute
+
+// digestCheckInterval is how often subscribers are checked for a due
+// weekly digest.
+const digestCheckInterval = 1 * time.Hour
+
+// defaultDigestWeekday is used when DIGEST_WEEKDAY is unset.
+const defaultDigestWeekday = time.Monday
+
+// defaultPasswordMinLength is used when PASSWORD_MIN_LENGTH is unset.
+const defaultPasswordMinLength = 8
+
+// defaultAvatarDir is used when AVATAR_STORAGE_DIR is unset for local-disk
+// avatar storage.
+const defaultAvatarDir = "./uploads/avatars"
+
+// shutdownTimeout is how long main waits for in-flight requests to drain
+// after receiving a shutdown signal before forcing the server closed.
+const shutdownTimeout = 15 * time.Second
+
+func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("load config", "error", err)
+		os.Exit(1)
+	}
+
+	database, err := db.Connect(cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+	db.QueryTimeout = cfg.QueryTimeout
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(database, os.Args[2:])
+		return
+	}
+
+	applied, err := migrations.Up(context.Background(), database)
+	if err != nil {
+		slog.Error("apply migrations", "error", err)
+		os.Exit(1)
+	}
+	if len(applied) > 0 {
+		slog.Info("applied migrations", "count", len(applied))
+	}
+
+	signer, err := buildSigner(cfg.JWTSecret)
+	if err != nil {
+		slog.Error("build token signer", "error", err)
+		os.Exit(1)
+	}
+
+	avatarStorage, err := buildAvatarStorage(context.Background())
+	if err != nil {
+		slog.Error("build avatar storage", "error", err)
+		os.Exit(1)
+	}
+
+	userStore := users.NewStore(database)
+	auditStore := audit.NewStore(database)
+	eventStore := events.NewStore(database, geocoder())
+	authSvc := auth.NewService(database, userStore, signer, auth.LogMailer{}, smsSender(), oauthProviders(), passwordPolicy(), avatarStorage, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	commentStore := comments.NewStore(database, eventStore)
+	ticketStore := tickets.NewStore(database, eventStore, paymentProvider())
+	notificationStore := notifications.NewStore(database, pushSender())
+	invitationStore := invitations.NewStore(database, eventStore, userStore, invitations.CapacityPolicyWarn, invitationMailer(), os.Getenv("APP_BASE_URL"), 0, invitations.InvitationQuotas{}, notificationStore)
+	pollStore := polls.NewStore(database, eventStore)
+	slackStore := slack.NewStore(database, eventStore)
+	reminderStore := reminders.NewStore(database, invitationMailer(), notificationStore, slackStore)
+	digestStore := digest.NewStore(database, invitationMailer())
+	realtimeHub := realtime.NewHub()
+	googleCalendarStore := googlecalendar.NewStore(database, eventStore, os.Getenv("GOOGLE_CALENDAR_CLIENT_ID"), os.Getenv("GOOGLE_CALENDAR_CLIENT_SECRET"), os.Getenv("GOOGLE_CALENDAR_REDIRECT_URL"))
+	outlookCalendarStore := msgraphcalendar.NewStore(database, eventStore, os.Getenv("GRAPH_CLIENT_ID"), os.Getenv("GRAPH_CLIENT_SECRET"), os.Getenv("GRAPH_REDIRECT_URL"), os.Getenv("GRAPH_TENANT_ID"))
+
+	server := httpapi.NewServer(database, authSvc, auditStore, eventStore, commentStore, ticketStore, invitationStore, pollStore, notificationStore, reminderStore, digestStore, realtimeHub, slackStore, googleCalendarStore, outlookCalendarStore, cfg.RequestTimeout)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var schedulers sync.WaitGroup
+	runScheduler(&schedulers, ctx, quorumCheckInterval, func(ctx context.Context) { runQuorumCheck(ctx, eventStore) })
+	runScheduler(&schedulers, ctx, trendingRecomputeInterval, func(ctx context.Context) { runTrendingRecompute(ctx, eventStore) })
+	runScheduler(&schedulers, ctx, archiveCheckInterval, func(ctx context.Context) { runArchiveCheck(ctx, eventStore) })
+	runScheduler(&schedulers, ctx, purgeDeletedCheckInterval, func(ctx context.Context) { runPurgeDeletedCheck(ctx, eventStore) })
+	runScheduler(&schedulers, ctx, invitationExpiryCheckInterval, func(ctx context.Context) { runInvitationExpiryCheck(ctx, invitationStore) })
+	runScheduler(&schedulers, ctx, invitationReminderCheckInterval, func(ctx context.Context) { runInvitationReminderCheck(ctx, invitationStore) })
+	runScheduler(&schedulers, ctx, eventReminderCheckInterval, func(ctx context.Context) { runEventReminderCheck(ctx, reminderStore) })
+	weekday := digestWeekday()
+	runScheduler(&schedulers, ctx, digestCheckInterval, func(ctx context.Context) { runDigestCheck(ctx, digestStore, weekday) })
+
+	httpServer := &http.Server{Addr: cfg.Addr, Handler: server.Router()}
+
+	redirectServer, err := configureTLS(cfg, httpServer)
+	if err != nil {
+		slog.Error("configure tls", "error", err)
+		os.Exit(1)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "addr", cfg.Addr, "tls", cfg.TLSEnabled())
+		var err error
+		if cfg.TLSEnabled() {
+			err = httpServer.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+			return
+		}
+		serveErr <- nil
+	}()
+
+	if redirectServer != nil {
+		go func() {
+			slog.Info("listening for http->https redirects", "addr", redirectServer.Addr)
+			if err := redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("redirect server error", "error", err)
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	case err := <-serveErr:
+		if err != nil {
+			slog.Error("server error", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("server shutdown", "error", err)
+	}
+	if redirectServer != nil {
+		if err := redirectServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("redirect server shutdown", "error", err)
+		}
+	}
+
+	schedulers.Wait()
+}
+
+// runMigrateCommand implements the "migrate" subcommand ("migrate up", the
+// default, or "migrate down" to roll back the most recently applied
+// migration), for deploys that want to run migrations as a separate step
+// instead of relying on the automatic check at startup.
+func runMigrateCommand(database *sql.DB, args []string) {
+	direction := "up"
+	if len(args) > 0 {
+		direction = args[0]
+	}
+
+	switch direction {
+	case "up":
+		applied, err := migrations.Up(context.Background(), database)
+		if err != nil {
+			slog.Error("migrate up", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("applied migrations", "count", len(applied))
+	case "down":
+		version, err := migrations.Down(context.Background(), database)
+		if err != nil {
+			slog.Error("migrate down", "error", err)
+			os.Exit(1)
+		}
+		if version == 0 {
+			slog.Info("nothing to roll back")
+		} else {
+			slog.Info("rolled back migration", "version", version)
+		}
+	default:
+		slog.Error("migrate: unknown direction", "direction", direction)
+		os.Exit(1)
+	}
+}
+
+// runScheduler starts a background goroutine, tracked by wg, that calls run
+// every interval until ctx is cancelled.
+func runScheduler(wg *sync.WaitGroup, ctx context.Context, interval time.Duration, run func(context.Context)) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run(ctx)
+			}
+		}
+	}()
+}
+
+// buildSigner selects the access token signing method from environment
+// configuration. When RSA_PRIVATE_KEY_PEM is set, tokens are signed with
+// RS256 and the public key is published at /.well-known/jwks.json so other
+// services can validate tokens without sharing a secret. Otherwise tokens
+// are signed with the shared HS256 secret.
+func buildSigner(jwtSecret string) (auth.Signer, error) {
+	pemKey := os.Getenv("RSA_PRIVATE_KEY_PEM")
+	if pemKey == "" {
+		return auth.NewHMACSigner(jwtSecret), nil
+	}
+
+	privateKey, err := auth.ParseRSAPrivateKeyPEM([]byte(pemKey))
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := os.Getenv("JWT_KEY_ID")
+	if keyID == "" {
+		keyID = "default"
+	}
+	return auth.NewRS256Signer(keyID, privateKey), nil
+}
+
+// passwordPolicy builds the password validation rules applied to
+// registration and password resets, from environment configuration.
+func passwordPolicy() auth.PasswordPolicy {
+	minLength := defaultPasswordMinLength
+	if v, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil && v > 0 {
+		minLength = v
+	}
+	requireComplexity := os.Getenv("PASSWORD_REQUIRE_COMPLEXITY") == "true"
+	checkBreached := os.Getenv("PASSWORD_CHECK_BREACHED") == "true"
+
+	return auth.NewDefaultPasswordPolicy(minLength, requireComplexity, checkBreached)
+}
+
+// buildAvatarStorage selects the avatar storage backend from environment
+// configuration. When AVATAR_STORAGE is "s3", avatars are uploaded to the
+// bucket named by AVATAR_S3_BUCKET using the process's default AWS
+// credentials, and served from AVATAR_BASE_URL. Otherwise avatars are saved
+// to local disk under AVATAR_STORAGE_DIR and served from AVATAR_BASE_URL.
+func buildAvatarStorage(ctx context.Context) (storage.Storage, error) {
+	baseURL := os.Getenv("AVATAR_BASE_URL")
+
+	if os.Getenv("AVATAR_STORAGE") == "s3" {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load aws config: %w", err)
+		}
+		return storage.NewS3(s3.NewFromConfig(awsCfg), os.Getenv("AVATAR_S3_BUCKET"), baseURL), nil
+	}
+
+	dir := os.Getenv("AVATAR_STORAGE_DIR")
+	if dir == "" {
+		dir = defaultAvatarDir
+	}
+	return storage.NewLocalDisk(dir, baseURL), nil
+}
+
+// oauthProviders builds the set of social login providers enabled through
+// environment configuration. A provider is registered only when its client
+// ID and secret are both set.
+func oauthProviders() map[string]auth.OAuthProvider {
+	providers := map[string]auth.OAuthProvider{}
+
+	if id, secret := os.Getenv("GOOGLE_CLIENT_ID"), os.Getenv("GOOGLE_CLIENT_SECRET"); id != "" && secret != "" {
+		providers["google"] = auth.NewGoogleProvider(id, secret, os.Getenv("GOOGLE_REDIRECT_URL"))
+	}
+	if id, secret := os.Getenv("GITHUB_CLIENT_ID"), os.Getenv("GITHUB_CLIENT_SECRET"); id != "" && secret != "" {
+		providers["github"] = auth.NewGitHubProvider(id, secret, os.Getenv("GITHUB_REDIRECT_URL"))
+	}
+
+	return providers
+}
+
+// paymentProvider selects the ticket payment backend from environment
+// configuration. When STRIPE_SECRET_KEY is set, paid tickets use Stripe
+// Checkout; otherwise every ticket is treated as free.
+func paymentProvider() tickets.PaymentProvider {
+	secretKey := os.Getenv("STRIPE_SECRET_KEY")
+	if secretKey == "" {
+		return tickets.NoopPaymentProvider{}
+	}
+	return tickets.NewStripeProvider(secretKey, os.Getenv("STRIPE_WEBHOOK_SECRET"))
+}
+
+// geocoder selects the event location geocoding backend from environment
+// configuration. When GEOCODER_USER_AGENT is set, event locations are
+// resolved through Nominatim (NOMINATIM_BASE_URL, or OpenStreetMap's public
+// instance if unset); otherwise events keep whatever coordinates were
+// supplied directly and are never geocoded.
+func geocoder() events.Geocoder {
+	userAgent := os.Getenv("GEOCODER_USER_AGENT")
+	if userAgent == "" {
+		return events.NoopGeocoder{}
+	}
+	return events.NewNominatimGeocoder(os.Getenv("NOMINATIM_BASE_URL"), userAgent)
+}
+
+// invitationMailer selects the backend used to email invitees from
+// environment configuration. When SMTP_ADDR is set, invitation emails are
+// sent through that SMTP relay; otherwise they're only logged, the same
+// default auth.NewService uses for verification emails.
+func invitationMailer() invitations.Mailer {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		return invitations.LogMailer{}
+	}
+	return invitations.NewSMTPMailer(addr, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM"))
+}
+
+// smsSender selects the backend used to text phone verification codes and
+// cancellation alerts from environment configuration. When TWILIO_ACCOUNT_SID
+// is set, messages are sent through Twilio; otherwise they're only logged,
+// the same default invitationMailer uses for invitation emails.
+func smsSender() auth.SMSSender {
+	accountSID := os.Getenv("TWILIO_ACCOUNT_SID")
+	if accountSID == "" {
+		return auth.LogSMSSender{}
+	}
+	return auth.NewTwilioSMSSender(accountSID, os.Getenv("TWILIO_AUTH_TOKEN"), os.Getenv("TWILIO_FROM_NUMBER"))
+}
+
+// digestWeekday selects the weekday the weekly digest is sent on from
+// DIGEST_WEEKDAY (a weekday name such as "Monday"), falling back to
+// defaultDigestWeekday if unset or unrecognized.
+func digestWeekday() time.Weekday {
+	name := os.Getenv("DIGEST_WEEKDAY")
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(weekday.String(), name) {
+			return weekday
+		}
+	}
+	return defaultDigestWeekday
+}
+
+// pushSender builds the push notification backend from environment
+// configuration. FCM_SERVER_KEY enables push to registered Android/iOS/web
+// devices through Firebase Cloud Messaging; VAPID_PRIVATE_KEY_PEM (with
+// VAPID_PUBLIC_KEY and VAPID_SUBJECT) enables Web Push to browser
+// subscriptions. Either, both, or neither may be set; a platform with no
+// backend configured is silently skipped by notifications.MultiPusher.
+func pushSender() notifications.Pusher {
+	pusher := notifications.MultiPusher{}
+
+	if serverKey := os.Getenv("FCM_SERVER_KEY"); serverKey != "" {
+		pusher.FCM = notifications.NewFCMSender(serverKey)
+	}
+
+	if pemKey := os.Getenv("VAPID_PRIVATE_KEY_PEM"); pemKey != "" {
+		privateKey, err := notifications.ParseVAPIDPrivateKeyPEM([]byte(pemKey))
+		if err != nil {
+			slog.Error("parse VAPID private key", "error", err)
+			os.Exit(1)
+		}
+		pusher.WebPush = notifications.NewWebPushSender(privateKey, os.Getenv("VAPID_PUBLIC_KEY"), os.Getenv("VAPID_SUBJECT"))
+	}
+
+	return pusher
+}
+
+// runQuorumCheck cancels tentative events that missed their attendee
+// quorum by the RSVP deadline. It's called every quorumCheckInterval by
+// runScheduler.
+func runQuorumCheck(ctx context.Context, eventStore *events.Store) {
+	cancelled, err := eventStore.CancelUnmetQuorumEvents(ctx)
+	if err != nil {
+		slog.Error("quorum scheduler", "error", err)
+		return
+	}
+	if len(cancelled) > 0 {
+		slog.Info("quorum scheduler: cancelled events for missed quorum", "count", len(cancelled))
+	}
+}
+
+// runTrendingRecompute refreshes event trending scores from recent joins
+// and invitation accepts. It's called every trendingRecomputeInterval by
+// runScheduler.
+func runTrendingRecompute(ctx context.Context, eventStore *events.Store) {
+	if _, err := eventStore.RecomputeTrendingScores(ctx); err != nil {
+		slog.Error("trending scheduler", "error", err)
+	}
+}
+
+// runArchiveCheck marks events whose end time has passed as archived, so
+// they drop out of default listings. It's called every archiveCheckInterval
+// by runScheduler.
+func runArchiveCheck(ctx context.Context, eventStore *events.Store) {
+	archived, err := eventStore.ArchiveEndedEvents(ctx)
+	if err != nil {
+		slog.Error("archive scheduler", "error", err)
+		return
+	}
+	if archived > 0 {
+		slog.Info("archive scheduler: archived events", "count", archived)
+	}
+}
+
+// runPurgeDeletedCheck permanently removes soft-deleted events whose restore
+// grace period has expired. It's called every purgeDeletedCheckInterval by
+// runScheduler.
+func runPurgeDeletedCheck(ctx context.Context, eventStore *events.Store) {
+	purged, err := eventStore.PurgeExpiredDeleted(ctx)
+	if err != nil {
+		slog.Error("purge deleted scheduler", "error", err)
+		return
+	}
+	if purged > 0 {
+		slog.Info("purge deleted scheduler: purged events", "count", purged)
+	}
+}
+
+// runInvitationExpiryCheck marks pending invitations whose ExpiresAt has
+// passed as expired. It's called every invitationExpiryCheckInterval by
+// runScheduler.
+func runInvitationExpiryCheck(ctx context.Context, invitationStore *invitations.Store) {
+	expired, err := invitationStore.ExpireStaleInvitations(ctx)
+	if err != nil {
+		slog.Error("invitation expiry scheduler", "error", err)
+		return
+	}
+	if expired > 0 {
+		slog.Info("invitation expiry scheduler: expired invitations", "count", expired)
+	}
+}
+
+// runInvitationReminderCheck emails invitees who haven't responded to a
+// pending invitation, both a follow-up reminder and one as the event's
+// RSVP deadline approaches; see invitations.Store.SendDueReminders. It's
+// called every invitationReminderCheckInterval by runScheduler.
+func runInvitationReminderCheck(ctx context.Context, invitationStore *invitations.Store) {
+	sent, err := invitationStore.SendDueReminders(ctx)
+	if err != nil {
+		slog.Error("invitation reminder scheduler", "error", err)
+		return
+	}
+	if sent > 0 {
+		slog.Info("invitation reminder scheduler: sent reminders", "count", sent)
+	}
+}
+
+// runEventReminderCheck emails and notifies attendees of events starting
+// within one of their configured reminder lead times. It's called every
+// eventReminderCheckInterval by runScheduler.
+func runEventReminderCheck(ctx context.Context, reminderStore *reminders.Store) {
+	sent, err := reminderStore.SendDueReminders(ctx)
+	if err != nil {
+		slog.Error("event reminder scheduler", "error", err)
+		return
+	}
+	if sent > 0 {
+		slog.Info("event reminder scheduler: sent reminders", "count", sent)
+	}
+}
+
+// runDigestCheck emails subscribers a weekly digest of their upcoming
+// events, on weekday. It's called every digestCheckInterval by
+// runScheduler.
+func runDigestCheck(ctx context.Context, digestStore *digest.Store, weekday time.Weekday) {
+	sent, err := digestStore.SendDueDigests(ctx, weekday)
+	if err != nil {
+		slog.Error("digest scheduler", "error", err)
+		return
+	}
+	if sent > 0 {
+		slog.Info("digest scheduler: sent digests", "count", sent)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/dohasheriff/tools-3/internal/config"
+)
+
+// configureTLS applies cfg's TLS settings to httpServer and, when TLS is
+// enabled, returns the http server that redirects plain HTTP to HTTPS
+// (handling Let's Encrypt's HTTP-01 challenge first, when autocert is in
+// use, since that challenge arrives over plain HTTP). It returns a nil
+// redirect server and no error when TLS is disabled.
+func configureTLS(cfg *config.Config, httpServer *http.Server) (redirectServer *http.Server, err error) {
+	if !cfg.TLSEnabled() {
+		return nil, nil
+	}
+
+	redirect := http.HandlerFunc(redirectToHTTPS)
+
+	if len(cfg.AutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+		}
+		httpServer.TLSConfig = manager.TLSConfig()
+		return &http.Server{Addr: cfg.HTTPRedirectAddr, Handler: manager.HTTPHandler(redirect)}, nil
+	}
+
+	if _, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile); err != nil {
+		return nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+	return &http.Server{Addr: cfg.HTTPRedirectAddr, Handler: redirect}, nil
+}
+
+// redirectToHTTPS redirects an HTTP request to the same host and path over
+// HTTPS.
+func redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	u := *r.URL
+	u.Scheme = "https"
+	u.Host = r.Host
+	http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+}
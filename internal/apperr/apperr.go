@@ -0,0 +1,40 @@
+// Package apperr defines the error categories the HTTP layer maps to
+// status codes. Domain packages wrap their existing sentinel errors with
+// the category that fits (via Wrap), so httpapi can classify any domain
+// error with errors.Is instead of matching on err.Error() text.
+package apperr
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound categorizes errors for a resource that doesn't exist,
+	// or that the caller isn't permitted to know exists.
+	ErrNotFound = errors.New("apperr: not found")
+	// ErrForbidden categorizes errors for a resource the caller is not
+	// permitted to act on.
+	ErrForbidden = errors.New("apperr: forbidden")
+	// ErrValidation categorizes errors caused by invalid request input.
+	ErrValidation = errors.New("apperr: validation failed")
+	// ErrConflict categorizes errors where the request is valid but
+	// conflicts with the current state (e.g. a closed RSVP deadline).
+	ErrConflict = errors.New("apperr: conflict")
+)
+
+// Wrap returns an error whose Error() is exactly message, but which
+// still satisfies errors.Is(err, category) via Unwrap. This lets a
+// domain package keep its existing sentinel error text unchanged while
+// letting httpapi classify the error centrally instead of matching on
+// that text.
+func Wrap(category error, message string) error {
+	return &categorized{msg: message, category: category}
+}
+
+type categorized struct {
+	msg      string
+	category error
+}
+
+func (e *categorized) Error() string { return e.msg }
+func (e *categorized) Unwrap() error { return e.category }
@@ -0,0 +1,88 @@
+// Package audit records who did what and when for an event: it's an
+// append-only log of actions like an event being created or cancelled, an
+// invitation being sent, or an attendee's status changing, written
+// best-effort by the httpapi layer after the action it describes has
+// already succeeded, the same tradeoff notifications.Store.Notify makes.
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Log is a single recorded action against an event.
+type Log struct {
+	ID      int64
+	EventID int64
+	// ActorUserID is nil when the action had no authenticated actor, such
+	// as a check-in scanned at the door.
+	ActorUserID *int64
+	Action      string
+	Details     string
+	CreatedAt   time.Time
+}
+
+// Store persists audit logs in Postgres.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore returns a Store backed by db.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Record appends a log entry for eventID. actorUserID is nil for actions
+// without an authenticated actor. details is a free-form human-readable
+// description the caller composes; this package doesn't interpret it.
+func (s *Store) Record(ctx context.Context, eventID int64, actorUserID *int64, action, details string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO audit_logs (event_id, actor_user_id, action, details) VALUES ($1, $2, $3, $4)`,
+		eventID, actorUserID, action, details,
+	)
+	if err != nil {
+		return fmt.Errorf("audit: record: %w", err)
+	}
+	return nil
+}
+
+// ListForEvent returns eventID's audit log, most recent first.
+func (s *Store) ListForEvent(ctx context.Context, eventID int64, limit, offset int) ([]*Log, int, error) {
+	return s.list(ctx, `WHERE event_id = $1`, []interface{}{eventID}, limit, offset)
+}
+
+// ListAll returns the audit log across every event, most recent first, for
+// the admin query API.
+func (s *Store) ListAll(ctx context.Context, limit, offset int) ([]*Log, int, error) {
+	return s.list(ctx, ``, nil, limit, offset)
+}
+
+func (s *Store) list(ctx context.Context, where string, args []interface{}, limit, offset int) ([]*Log, int, error) {
+	var total int
+	countRow := s.db.QueryRowContext(ctx, `SELECT count(*) FROM audit_logs `+where, args...)
+	if err := countRow.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("audit: count: %w", err)
+	}
+
+	query := fmt.Sprintf(
+		`SELECT id, event_id, actor_user_id, action, details, created_at FROM audit_logs %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		where, len(args)+1, len(args)+2,
+	)
+	rows, err := s.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("audit: list: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Log
+	for rows.Next() {
+		l := &Log{}
+		if err := rows.Scan(&l.ID, &l.EventID, &l.ActorUserID, &l.Action, &l.Details, &l.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("audit: list scan: %w", err)
+		}
+		out = append(out, l)
+	}
+	return out, total, rows.Err()
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+// DeleteAccount anonymizes userID's account and cleans up the data that
+// references it. See users.Store.Delete for exactly what is scrubbed and
+// removed.
+func (s *Service) DeleteAccount(ctx context.Context, userID int64) error {
+	return s.users.Delete(ctx, userID)
+}
+
+// SearchUsers returns up to the store's result limit of accounts whose email
+// or display name starts with prefix, so inviters can locate an account's
+// numeric ID.
+func (s *Service) SearchUsers(ctx context.Context, prefix string) ([]users.SearchResult, error) {
+	return s.users.Search(ctx, prefix)
+}
+
+// GetUser returns the account with the given ID.
+func (s *Service) GetUser(ctx context.Context, userID int64) (*users.User, error) {
+	return s.users.GetByID(ctx, userID)
+}
+
+// UpdateProfile changes userID's username and display name. An empty
+// username clears it; users.ErrUsernameTaken is returned if username is
+// already claimed by a different account.
+func (s *Service) UpdateProfile(ctx context.Context, userID int64, username, displayName string) error {
+	return s.users.UpdateProfile(ctx, userID, username, displayName)
+}
@@ -0,0 +1,251 @@
+// Package auth handles account registration, login, and JWT issuance.
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dohasheriff/tools-3/internal/storage"
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+// ErrInvalidCredentials is returned by Login when the email/password pair
+// does not match a known account.
+var ErrInvalidCredentials = errors.New("auth: invalid credentials")
+
+// ErrEmailTaken is returned by Register when the email is already in use.
+var ErrEmailTaken = errors.New("auth: email already registered")
+
+// ErrEmailNotVerified is returned by Login when the account's email address
+// has not yet been confirmed.
+var ErrEmailNotVerified = errors.New("auth: email not verified")
+
+// DefaultAccessTokenTTL is the access token lifetime NewService uses when
+// accessTokenTTL is zero.
+const DefaultAccessTokenTTL = 15 * time.Minute
+
+// DefaultRefreshTokenTTL is the refresh token lifetime NewService uses when
+// refreshTokenTTL is zero.
+const DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// Service issues and verifies credentials for user accounts.
+type Service struct {
+	db              *sql.DB
+	users           *users.Store
+	signer          Signer
+	mailer          Mailer
+	sms             SMSSender
+	oauthProviders  map[string]OAuthProvider
+	passwordPolicy  PasswordPolicy
+	avatarStorage   storage.Storage
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+// NewService returns a Service that signs tokens with signer, sends account
+// emails through mailer and text messages through sms, accepts social
+// logins from the given OAuth providers (keyed by the provider name used in
+// /auth/oauth/{provider}), enforces passwordPolicy on every new or changed
+// password, and saves uploaded avatars through avatarStorage. accessTokenTTL
+// and refreshTokenTTL default to DefaultAccessTokenTTL and
+// DefaultRefreshTokenTTL when zero.
+func NewService(db *sql.DB, store *users.Store, signer Signer, mailer Mailer, sms SMSSender, oauthProviders map[string]OAuthProvider, passwordPolicy PasswordPolicy, avatarStorage storage.Storage, accessTokenTTL, refreshTokenTTL time.Duration) *Service {
+	if accessTokenTTL == 0 {
+		accessTokenTTL = DefaultAccessTokenTTL
+	}
+	if refreshTokenTTL == 0 {
+		refreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	return &Service{
+		db:              db,
+		users:           store,
+		signer:          signer,
+		mailer:          mailer,
+		sms:             sms,
+		oauthProviders:  oauthProviders,
+		passwordPolicy:  passwordPolicy,
+		avatarStorage:   avatarStorage,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+// Claims are the custom fields carried in an access token.
+type Claims struct {
+	UserID int64      `json:"user_id"`
+	Email  string     `json:"email"`
+	Role   users.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Register creates a new account and returns its ID, an access token, and a
+// refresh token. username and displayName are optional and may be left
+// empty. device is recorded against the refresh token for the session
+// management endpoints.
+func (s *Service) Register(ctx context.Context, email, password, username, displayName string, device Device) (int64, string, string, error) {
+	if _, err := s.users.GetByEmail(ctx, email); err == nil {
+		return 0, "", "", ErrEmailTaken
+	} else if !errors.Is(err, users.ErrNotFound) {
+		return 0, "", "", err
+	}
+
+	if err := s.passwordPolicy.Validate(ctx, password); err != nil {
+		return 0, "", "", err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("auth: hash password: %w", err)
+	}
+
+	u, err := s.users.Create(ctx, email, string(hash), username, displayName)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	if err := s.sendVerificationEmail(ctx, u.ID, u.Email); err != nil {
+		return 0, "", "", err
+	}
+
+	accessToken, refreshToken, err := s.issueTokenPair(ctx, u.ID, u.Email, u.Role, device)
+	if err != nil {
+		return 0, "", "", err
+	}
+	return u.ID, accessToken, refreshToken, nil
+}
+
+// Login verifies email/password and returns the account ID, a new access
+// token, and a new refresh token. Accounts that have not yet verified their
+// email address cannot log in. Accounts with two-factor authentication
+// enabled return ErrTOTPRequired instead of tokens; the caller must collect
+// a code and retry via VerifyTOTPLogin. device is recorded against the
+// refresh token for the session management endpoints.
+func (s *Service) Login(ctx context.Context, email, password string, device Device) (int64, string, string, error) {
+	u, err := s.authenticate(ctx, email, password)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if u.TOTPEnabled {
+		return u.ID, "", "", ErrTOTPRequired
+	}
+
+	accessToken, refreshToken, err := s.issueTokenPair(ctx, u.ID, u.Email, u.Role, device)
+	if err != nil {
+		return 0, "", "", err
+	}
+	return u.ID, accessToken, refreshToken, nil
+}
+
+// authenticate verifies email/password, rejecting accounts that have not
+// yet verified their email address or that are locked out after too many
+// failed attempts, and returns the matching user.
+func (s *Service) authenticate(ctx context.Context, email, password string) (*users.User, error) {
+	u, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if u.LockedUntil.Valid && time.Now().Before(u.LockedUntil.Time) {
+		return nil, ErrAccountLocked
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		if lockErr := s.recordFailedLogin(ctx, u.ID); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, ErrInvalidCredentials
+	}
+	if !u.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	if err := s.users.ResetFailedLogins(ctx, u.ID); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// issueTokenPair issues a fresh access token and a persisted refresh token
+// for userID, recording device against the refresh token.
+func (s *Service) issueTokenPair(ctx context.Context, userID int64, email string, role users.Role, device Device) (string, string, error) {
+	accessToken, err := s.issueAccessToken(userID, email, role)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := s.issueRefreshToken(ctx, userID, device)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func (s *Service) issueAccessToken(userID int64, email string, role users.Role) (string, error) {
+	jti, err := generateJTI()
+	if err != nil {
+		return "", err
+	}
+
+	claims := Claims{
+		UserID: userID,
+		Email:  email,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := jwt.NewWithClaims(s.signer.Method(), claims)
+	if kid := s.signer.KeyID(); kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(s.signer.SignKey())
+	if err != nil {
+		return "", fmt.Errorf("auth: sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// parseClaims validates token's signature and expiry and returns its claims.
+func (s *Service) parseClaims(token string) (*Claims, error) {
+	claims := &Claims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != s.signer.Method().Alg() {
+			return nil, fmt.Errorf("auth: unexpected signing method %v", t.Header["alg"])
+		}
+		return s.signer.VerifyKey(), nil
+	})
+	if err != nil || !parsed.Valid {
+		return nil, fmt.Errorf("auth: invalid token: %w", err)
+	}
+	return claims, nil
+}
+
+// ParseAccessToken validates token, rejecting it if it has been revoked via
+// Logout, and returns the user ID, email, and role it was issued for.
+func (s *Service) ParseAccessToken(ctx context.Context, token string) (int64, string, users.Role, error) {
+	claims, err := s.parseClaims(token)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	revoked, err := s.isTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if revoked {
+		return 0, "", "", fmt.Errorf("auth: token revoked")
+	}
+
+	return claims.UserID, claims.Email, claims.Role, nil
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxAvatarSize is the largest avatar upload accepted, in bytes.
+const MaxAvatarSize = 5 << 20 // 5 MiB
+
+// ErrUnsupportedAvatarType is returned by UploadAvatar when contentType is
+// not one of the supported image types.
+var ErrUnsupportedAvatarType = errors.New("auth: unsupported avatar image type")
+
+// avatarExtensions maps the supported content types to the file extension
+// used in the storage key.
+var avatarExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// UploadAvatar saves r as userID's avatar through the configured storage
+// backend and records its URL on the account. contentType must be one of
+// the supported image types.
+func (s *Service) UploadAvatar(ctx context.Context, userID int64, r io.Reader, contentType string) (string, error) {
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		return "", ErrUnsupportedAvatarType
+	}
+
+	name, err := randomAvatarName()
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("avatars/%d/%s.%s", userID, name, ext)
+
+	url, err := s.avatarStorage.Save(ctx, key, r, contentType)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.users.UpdateAvatarURL(ctx, userID, url); err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// randomAvatarName returns a short random hex string used to avoid
+// collisions and let a new upload invalidate any cache of the old one.
+func randomAvatarName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("auth: generate avatar name: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
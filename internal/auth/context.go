@@ -0,0 +1,19 @@
+package auth
+
+import "context"
+
+type contextKey string
+
+const emailContextKey contextKey = "email"
+
+// ContextWithEmail returns a copy of ctx carrying email, as extracted from a
+// verified access token's claims.
+func ContextWithEmail(ctx context.Context, email string) context.Context {
+	return context.WithValue(ctx, emailContextKey, email)
+}
+
+// GetUserEmail returns the email attached to ctx by ContextWithEmail.
+func GetUserEmail(ctx context.Context) (string, bool) {
+	email, ok := ctx.Value(emailContextKey).(string)
+	return email, ok
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidFeedToken is returned by AuthenticateFeedToken when the
+// presented token is unknown or has been revoked.
+var ErrInvalidFeedToken = errors.New("auth: invalid calendar feed token")
+
+// CreateFeedToken revokes any existing calendar feed token for userID and
+// issues a new one, returning the raw token. Only its hash is stored, so
+// the raw token cannot be recovered later.
+func (s *Service) CreateFeedToken(ctx context.Context, userID int64) (string, error) {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE calendar_feed_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID,
+	); err != nil {
+		return "", fmt.Errorf("auth: revoke existing feed tokens: %w", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("auth: generate feed token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO calendar_feed_tokens (user_id, token_hash) VALUES ($1, $2)`,
+		userID, hashToken(token),
+	); err != nil {
+		return "", fmt.Errorf("auth: store feed token: %w", err)
+	}
+	return token, nil
+}
+
+// RevokeFeedToken revokes userID's active calendar feed token, if any.
+func (s *Service) RevokeFeedToken(ctx context.Context, userID int64) error {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE calendar_feed_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID,
+	); err != nil {
+		return fmt.Errorf("auth: revoke feed token: %w", err)
+	}
+	return nil
+}
+
+// AuthenticateFeedToken validates token, rejecting unknown or revoked
+// tokens, and returns the user ID it was issued for.
+func (s *Service) AuthenticateFeedToken(ctx context.Context, token string) (int64, error) {
+	var userID int64
+	row := s.db.QueryRowContext(ctx,
+		`SELECT user_id FROM calendar_feed_tokens WHERE token_hash = $1 AND revoked_at IS NULL`, hashToken(token),
+	)
+	switch err := row.Scan(&userID); {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, ErrInvalidFeedToken
+	case err != nil:
+		return 0, fmt.Errorf("auth: authenticate feed token: %w", err)
+	}
+	return userID, nil
+}
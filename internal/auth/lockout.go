@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// maxFailedLoginAttempts is how many consecutive failed logins an
+	// account tolerates before being locked out.
+	maxFailedLoginAttempts = 5
+	// lockoutBaseDuration is how long an account is locked the first time
+	// it crosses maxFailedLoginAttempts.
+	lockoutBaseDuration = time.Minute
+	// lockoutMaxDuration caps the exponential backoff applied to repeat
+	// lockouts.
+	lockoutMaxDuration = time.Hour
+)
+
+// ErrAccountLocked is returned by Login when the account is temporarily
+// locked out after too many failed login attempts.
+var ErrAccountLocked = errors.New("auth: account temporarily locked")
+
+// recordFailedLogin increments userID's failed login counter and, once it
+// reaches maxFailedLoginAttempts, locks the account for a duration that
+// doubles with each attempt beyond the threshold, up to lockoutMaxDuration.
+func (s *Service) recordFailedLogin(ctx context.Context, userID int64) error {
+	attempts, err := s.users.RecordFailedLogin(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if attempts < maxFailedLoginAttempts {
+		return nil
+	}
+	return s.users.LockUntil(ctx, userID, time.Now().Add(lockoutDuration(attempts)))
+}
+
+// lockoutDuration returns how long to lock an account that has just reached
+// attempts consecutive failures.
+func lockoutDuration(attempts int) time.Duration {
+	extra := attempts - maxFailedLoginAttempts
+	if extra < 0 {
+		extra = 0
+	}
+	if extra > 10 {
+		extra = 10 // guard against an absurd shift; lockoutMaxDuration caps us anyway
+	}
+
+	d := lockoutBaseDuration * time.Duration(1<<uint(extra))
+	if d > lockoutMaxDuration {
+		d = lockoutMaxDuration
+	}
+	return d
+}
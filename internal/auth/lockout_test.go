@@ -0,0 +1,15 @@
+package auth
+
+import "testing"
+
+func TestLockoutDuration_DoublesAndCaps(t *testing.T) {
+	if got := lockoutDuration(maxFailedLoginAttempts); got != lockoutBaseDuration {
+		t.Fatalf("expected the first lockout to last %v, got %v", lockoutBaseDuration, got)
+	}
+	if got := lockoutDuration(maxFailedLoginAttempts + 1); got != 2*lockoutBaseDuration {
+		t.Fatalf("expected the next lockout to double to %v, got %v", 2*lockoutBaseDuration, got)
+	}
+	if got := lockoutDuration(maxFailedLoginAttempts + 20); got != lockoutMaxDuration {
+		t.Fatalf("expected lockout duration to cap at %v, got %v", lockoutMaxDuration, got)
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+const magicLinkTokenTTL = 15 * time.Minute
+
+// ErrInvalidMagicLinkToken is returned by VerifyMagicLink when the presented
+// token is unknown, expired, or already used.
+var ErrInvalidMagicLinkToken = errors.New("auth: invalid magic link token")
+
+// RequestMagicLink emails a one-time sign-in link for email's account, if
+// one exists. It never reports whether the address is registered, so
+// callers should always report success to the caller.
+func (s *Service) RequestMagicLink(ctx context.Context, email string) error {
+	u, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("auth: generate magic link token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO magic_link_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		u.ID, hashToken(token), time.Now().Add(magicLinkTokenTTL),
+	)
+	if err != nil {
+		return fmt.Errorf("auth: store magic link token: %w", err)
+	}
+
+	return s.mailer.Send(ctx, email, "Your sign-in link",
+		fmt.Sprintf("Use this token to sign in: %s", token))
+}
+
+// VerifyMagicLink exchanges token, which must be an unused, unexpired token
+// issued by RequestMagicLink, for an access/refresh token pair. device is
+// recorded against the refresh token for the session management endpoints.
+func (s *Service) VerifyMagicLink(ctx context.Context, token string, device Device) (int64, string, string, error) {
+	hash := hashToken(token)
+
+	var userID int64
+	var expiresAt time.Time
+	var usedAt sql.NullTime
+	row := s.db.QueryRowContext(ctx,
+		`SELECT user_id, expires_at, used_at FROM magic_link_tokens WHERE token_hash = $1`, hash,
+	)
+	switch err := row.Scan(&userID, &expiresAt, &usedAt); {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, "", "", ErrInvalidMagicLinkToken
+	case err != nil:
+		return 0, "", "", fmt.Errorf("auth: verify magic link: %w", err)
+	}
+	if usedAt.Valid || time.Now().After(expiresAt) {
+		return 0, "", "", ErrInvalidMagicLinkToken
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE magic_link_tokens SET used_at = now() WHERE token_hash = $1`, hash,
+	); err != nil {
+		return 0, "", "", fmt.Errorf("auth: mark magic link token used: %w", err)
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if !u.EmailVerified {
+		if err := s.users.MarkEmailVerified(ctx, u.ID); err != nil {
+			return 0, "", "", err
+		}
+	}
+
+	accessToken, refreshToken, err := s.issueTokenPair(ctx, u.ID, u.Email, u.Role, device)
+	if err != nil {
+		return 0, "", "", err
+	}
+	return u.ID, accessToken, refreshToken, nil
+}
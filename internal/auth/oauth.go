@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+const oauthStateTTL = 10 * time.Minute
+
+// ErrUnknownOAuthProvider is returned when the named provider was not
+// registered with the Service.
+var ErrUnknownOAuthProvider = errors.New("auth: unknown oauth provider")
+
+// ErrInvalidOAuthState is returned by OAuthCallback when the state
+// parameter does not match one issued by OAuthStart.
+var ErrInvalidOAuthState = errors.New("auth: invalid oauth state")
+
+// OAuthIdentity identifies the account a provider authenticated.
+type OAuthIdentity struct {
+	ProviderUserID string
+	Email          string
+}
+
+// OAuthProvider authenticates users against a single external OAuth2
+// provider, such as Google or GitHub.
+type OAuthProvider interface {
+	Config() *oauth2.Config
+	FetchIdentity(ctx context.Context, token *oauth2.Token) (OAuthIdentity, error)
+}
+
+// OAuthStart begins a login with the named provider, returning the URL the
+// caller should redirect the user to.
+func (s *Service) OAuthStart(ctx context.Context, providerName string) (string, error) {
+	provider, ok := s.oauthProviders[providerName]
+	if !ok {
+		return "", ErrUnknownOAuthProvider
+	}
+
+	state, err := generateOAuthState()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO oauth_states (state, provider, expires_at) VALUES ($1, $2, $3)`,
+		state, providerName, time.Now().Add(oauthStateTTL),
+	)
+	if err != nil {
+		return "", fmt.Errorf("auth: store oauth state: %w", err)
+	}
+
+	return provider.Config().AuthCodeURL(state), nil
+}
+
+// OAuthCallback completes a login started by OAuthStart, exchanging code for
+// the caller's identity and returning the account ID, a new access token,
+// and a new refresh token. An existing account with a matching identity or
+// email is reused; otherwise a new account is created. device is recorded
+// against the refresh token for the session management endpoints.
+func (s *Service) OAuthCallback(ctx context.Context, providerName, state, code string, device Device) (int64, string, string, error) {
+	provider, ok := s.oauthProviders[providerName]
+	if !ok {
+		return 0, "", "", ErrUnknownOAuthProvider
+	}
+
+	valid, err := s.consumeOAuthState(ctx, providerName, state)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if !valid {
+		return 0, "", "", ErrInvalidOAuthState
+	}
+
+	token, err := provider.Config().Exchange(ctx, code)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("auth: oauth exchange: %w", err)
+	}
+
+	identity, err := provider.FetchIdentity(ctx, token)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	userID, err := s.findOrCreateOAuthUser(ctx, providerName, identity)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	accessToken, refreshToken, err := s.issueTokenPair(ctx, u.ID, u.Email, u.Role, device)
+	if err != nil {
+		return 0, "", "", err
+	}
+	return userID, accessToken, refreshToken, nil
+}
+
+// consumeOAuthState deletes and validates a one-time state value, reporting
+// whether it was a live, unexpired state issued for providerName.
+func (s *Service) consumeOAuthState(ctx context.Context, providerName, state string) (bool, error) {
+	row := s.db.QueryRowContext(ctx,
+		`DELETE FROM oauth_states WHERE state = $1 AND provider = $2 RETURNING expires_at`,
+		state, providerName,
+	)
+	var expiresAt time.Time
+	switch err := row.Scan(&expiresAt); {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("auth: consume oauth state: %w", err)
+	}
+	return time.Now().Before(expiresAt), nil
+}
+
+// findOrCreateOAuthUser resolves identity to a user ID, linking it to an
+// existing account by email or creating a new passwordless account.
+func (s *Service) findOrCreateOAuthUser(ctx context.Context, providerName string, identity OAuthIdentity) (int64, error) {
+	var userID int64
+	row := s.db.QueryRowContext(ctx,
+		`SELECT user_id FROM oauth_identities WHERE provider = $1 AND provider_user_id = $2`,
+		providerName, identity.ProviderUserID,
+	)
+	switch err := row.Scan(&userID); {
+	case err == nil:
+		return userID, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return 0, fmt.Errorf("auth: lookup oauth identity: %w", err)
+	}
+
+	u, err := s.users.GetByEmail(ctx, identity.Email)
+	if errors.Is(err, users.ErrNotFound) {
+		hash, hashErr := unusablePasswordHash()
+		if hashErr != nil {
+			return 0, hashErr
+		}
+		u, err = s.users.Create(ctx, identity.Email, hash, "", "")
+		if err != nil {
+			return 0, err
+		}
+		if err := s.users.MarkEmailVerified(ctx, u.ID); err != nil {
+			return 0, err
+		}
+	} else if err != nil {
+		return 0, err
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO oauth_identities (user_id, provider, provider_user_id) VALUES ($1, $2, $3)`,
+		u.ID, providerName, identity.ProviderUserID,
+	); err != nil {
+		return 0, fmt.Errorf("auth: link oauth identity: %w", err)
+	}
+	return u.ID, nil
+}
+
+// unusablePasswordHash returns a bcrypt hash of a random, never-revealed
+// secret so OAuth-only accounts still satisfy the NOT NULL password_hash
+// column but cannot be logged into with a password.
+func unusablePasswordHash() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("auth: generate unusable password: %w", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(buf)), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("auth: hash unusable password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// generateOAuthState returns a short random token used as the OAuth2 state
+// parameter to defend against CSRF on the callback.
+func generateOAuthState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("auth: generate oauth state: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
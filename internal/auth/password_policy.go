@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrPasswordTooShort is returned when a password is shorter than the
+// policy's minimum length.
+var ErrPasswordTooShort = errors.New("auth: password is too short")
+
+// ErrPasswordTooWeak is returned when a password does not mix upper case,
+// lower case, digit, and symbol characters as the policy requires.
+var ErrPasswordTooWeak = errors.New("auth: password does not meet complexity requirements")
+
+// ErrPasswordBreached is returned when a password has appeared in a known
+// data breach.
+var ErrPasswordBreached = errors.New("auth: password has appeared in a data breach")
+
+// PasswordPolicy validates candidate passwords before they are hashed and
+// stored.
+type PasswordPolicy interface {
+	Validate(ctx context.Context, password string) error
+}
+
+// NoPasswordPolicy accepts any password. It exists for tests and for
+// deployments that want to opt out of policy enforcement entirely.
+type NoPasswordPolicy struct{}
+
+// Validate always succeeds.
+func (NoPasswordPolicy) Validate(ctx context.Context, password string) error { return nil }
+
+const pwnedPasswordsTimeout = 5 * time.Second
+
+// DefaultPasswordPolicy enforces a minimum length, optional character
+// complexity, and an optional breached-password check against the Have I
+// Been Pwned k-anonymity API.
+type DefaultPasswordPolicy struct {
+	minLength         int
+	requireComplexity bool
+	checkBreached     bool
+	httpClient        *http.Client
+}
+
+// NewDefaultPasswordPolicy returns a DefaultPasswordPolicy requiring at
+// least minLength characters. If requireComplexity is set, passwords must
+// mix upper case, lower case, digit, and symbol characters. If
+// checkBreached is set, candidate passwords are also checked against the
+// Have I Been Pwned breach database using k-anonymity, so the full
+// password is never transmitted.
+func NewDefaultPasswordPolicy(minLength int, requireComplexity, checkBreached bool) *DefaultPasswordPolicy {
+	return &DefaultPasswordPolicy{
+		minLength:         minLength,
+		requireComplexity: requireComplexity,
+		checkBreached:     checkBreached,
+		httpClient:        &http.Client{Timeout: pwnedPasswordsTimeout},
+	}
+}
+
+// Validate checks password against the configured length, complexity, and
+// breach requirements, in that order.
+func (p *DefaultPasswordPolicy) Validate(ctx context.Context, password string) error {
+	if len(password) < p.minLength {
+		return ErrPasswordTooShort
+	}
+	if p.requireComplexity && !hasPasswordComplexity(password) {
+		return ErrPasswordTooWeak
+	}
+	if p.checkBreached {
+		breached, err := p.isBreached(ctx, password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			return ErrPasswordBreached
+		}
+	}
+	return nil
+}
+
+// hasPasswordComplexity reports whether password contains at least one
+// upper case letter, lower case letter, digit, and symbol.
+func hasPasswordComplexity(password string) bool {
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+	return hasUpper && hasLower && hasDigit && hasSymbol
+}
+
+const pwnedPasswordsRangeURL = "https://api.pwnedpasswords.com/range/"
+
+// isBreached checks password against the Have I Been Pwned k-anonymity
+// API: only the first 5 characters of its SHA-1 hash are sent, and the
+// full list of matching suffixes returned for that prefix is searched
+// locally so the API never sees the whole hash.
+func (p *DefaultPasswordPolicy) isBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pwnedPasswordsRangeURL+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("auth: build breach check request: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("auth: check breached password: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("auth: check breached password: unexpected status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		suffixPart, _, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if ok && suffixPart == suffix {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidRefreshToken is returned by Refresh when the presented token is
+// unknown, expired, or already used.
+var ErrInvalidRefreshToken = errors.New("auth: invalid refresh token")
+
+// Refresh exchanges a valid refresh token for a new access/refresh token
+// pair. The presented refresh token is revoked as part of the exchange, so
+// each refresh token can only be used once. device is recorded against the
+// new refresh token for the session management endpoints.
+func (s *Service) Refresh(ctx context.Context, refreshToken string, device Device) (string, string, error) {
+	hash := hashToken(refreshToken)
+
+	var userID int64
+	var expiresAt time.Time
+	var revokedAt sql.NullTime
+	row := s.db.QueryRowContext(ctx,
+		`SELECT user_id, expires_at, revoked_at FROM refresh_tokens WHERE token_hash = $1`, hash,
+	)
+	switch err := row.Scan(&userID, &expiresAt, &revokedAt); {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", "", ErrInvalidRefreshToken
+	case err != nil:
+		return "", "", fmt.Errorf("auth: refresh: %w", err)
+	}
+	if revokedAt.Valid || time.Now().After(expiresAt) {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = now() WHERE token_hash = $1`, hash,
+	); err != nil {
+		return "", "", fmt.Errorf("auth: revoke refresh token: %w", err)
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return s.issueTokenPair(ctx, u.ID, u.Email, u.Role, device)
+}
+
+// issueRefreshToken generates and persists a new refresh token for userID,
+// returning the unhashed token to give to the client.
+func (s *Service) issueRefreshToken(ctx context.Context, userID int64, device Device) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("auth: generate refresh token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at, user_agent, ip_address) VALUES ($1, $2, $3, $4, $5)`,
+		userID, hashToken(token), time.Now().Add(s.refreshTokenTTL), device.UserAgent, device.IPAddress,
+	)
+	if err != nil {
+		return "", fmt.Errorf("auth: store refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// hashToken returns the value stored alongside a refresh or password reset
+// token so the raw token never needs to be kept at rest.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
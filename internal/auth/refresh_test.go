@@ -0,0 +1,16 @@
+package auth
+
+import "testing"
+
+func TestHashToken_DeterministicAndDistinct(t *testing.T) {
+	a := hashToken("token-a")
+	b := hashToken("token-a")
+	c := hashToken("token-b")
+
+	if a != b {
+		t.Fatal("expected hashing the same token twice to produce the same hash")
+	}
+	if a == c {
+		t.Fatal("expected different tokens to hash differently")
+	}
+}
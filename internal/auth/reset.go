@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+const resetTokenTTL = 1 * time.Hour
+
+// ErrInvalidResetToken is returned by ResetPassword when the presented token
+// is unknown, expired, or already used.
+var ErrInvalidResetToken = errors.New("auth: invalid reset token")
+
+// RequestPasswordReset emails a reset link for email's account, if one
+// exists. It never reports whether the address is registered, so callers
+// should always report success to the caller.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	u, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("auth: generate reset token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		u.ID, hashToken(token), time.Now().Add(resetTokenTTL),
+	)
+	if err != nil {
+		return fmt.Errorf("auth: store reset token: %w", err)
+	}
+
+	return s.mailer.Send(ctx, email, "Reset your password",
+		fmt.Sprintf("Use this token to reset your password: %s", token))
+}
+
+// ResetPassword sets a new password for the account behind token, which
+// must be an unused, unexpired reset token issued by RequestPasswordReset.
+// Every refresh token already issued to the account is revoked, so a
+// session hijacked before the reset doesn't survive it.
+func (s *Service) ResetPassword(ctx context.Context, token, newPassword string) error {
+	hash := hashToken(token)
+
+	var userID int64
+	var expiresAt time.Time
+	var usedAt sql.NullTime
+	row := s.db.QueryRowContext(ctx,
+		`SELECT user_id, expires_at, used_at FROM password_reset_tokens WHERE token_hash = $1`, hash,
+	)
+	switch err := row.Scan(&userID, &expiresAt, &usedAt); {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrInvalidResetToken
+	case err != nil:
+		return fmt.Errorf("auth: reset password: %w", err)
+	}
+	if usedAt.Valid || time.Now().After(expiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.passwordPolicy.Validate(ctx, newPassword); err != nil {
+		return err
+	}
+
+	newHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("auth: hash password: %w", err)
+	}
+	if err := s.users.UpdatePasswordHash(ctx, userID, string(newHash)); err != nil {
+		return err
+	}
+	if err := s.RevokeAllSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE password_reset_tokens SET used_at = now() WHERE token_hash = $1`, hash,
+	); err != nil {
+		return fmt.Errorf("auth: mark reset token used: %w", err)
+	}
+	return nil
+}
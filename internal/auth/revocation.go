@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Logout revokes token so it stops being accepted by ParseAccessToken before
+// its natural expiry.
+func (s *Service) Logout(ctx context.Context, token string) error {
+	claims, err := s.parseClaims(token)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING`,
+		claims.ID, claims.ExpiresAt.Time,
+	)
+	if err != nil {
+		return fmt.Errorf("auth: revoke token: %w", err)
+	}
+	return nil
+}
+
+// isTokenRevoked reports whether jti has been revoked via Logout.
+func (s *Service) isTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var exists int
+	row := s.db.QueryRowContext(ctx, `SELECT 1 FROM revoked_tokens WHERE jti = $1`, jti)
+	switch err := row.Scan(&exists); {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("auth: check token revocation: %w", err)
+	}
+	return true, nil
+}
+
+// generateJTI returns a random token identifier carried in an access
+// token's jti claim so it can be individually revoked.
+func generateJTI() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("auth: generate jti: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signer signs and verifies access tokens. HMACSigner and RS256Signer are
+// the two implementations: HMAC keeps the existing shared-secret behavior,
+// RS256 lets other services validate tokens against a published public key
+// instead of sharing a secret.
+type Signer interface {
+	// Method is the JWT signing method to use when issuing tokens.
+	Method() jwt.SigningMethod
+	// SignKey is the key passed to token.SignedString.
+	SignKey() interface{}
+	// VerifyKey is the key returned from the jwt.Keyfunc used to parse
+	// tokens.
+	VerifyKey() interface{}
+	// KeyID is the "kid" header to stamp on issued tokens, or "" if the
+	// signer doesn't need one.
+	KeyID() string
+	// JWKS returns the public verification key as a JSON Web Key Set, and
+	// whether the signer has one to publish. Symmetric signers have no
+	// public key and return ok=false.
+	JWKS() (jwks JWKS, ok bool)
+}
+
+// HMACSigner signs access tokens with a shared secret (HS256). Because the
+// key is symmetric, it has no public key to publish via JWKS.
+type HMACSigner struct {
+	secret []byte
+}
+
+// NewHMACSigner returns a Signer that signs and verifies tokens with secret.
+func NewHMACSigner(secret string) *HMACSigner {
+	return &HMACSigner{secret: []byte(secret)}
+}
+
+func (h *HMACSigner) Method() jwt.SigningMethod { return jwt.SigningMethodHS256 }
+func (h *HMACSigner) SignKey() interface{}      { return h.secret }
+func (h *HMACSigner) VerifyKey() interface{}    { return h.secret }
+func (h *HMACSigner) KeyID() string             { return "" }
+func (h *HMACSigner) JWKS() (JWKS, bool)        { return JWKS{}, false }
+
+// RS256Signer signs access tokens with an RSA private key (RS256). Its
+// public key is published via JWKS so other services can verify tokens
+// without access to the private key.
+type RS256Signer struct {
+	keyID      string
+	privateKey *rsa.PrivateKey
+}
+
+// NewRS256Signer returns a Signer that signs tokens with privateKey,
+// identifying the key as keyID in both the token header and the JWKS.
+func NewRS256Signer(keyID string, privateKey *rsa.PrivateKey) *RS256Signer {
+	return &RS256Signer{keyID: keyID, privateKey: privateKey}
+}
+
+func (r *RS256Signer) Method() jwt.SigningMethod { return jwt.SigningMethodRS256 }
+func (r *RS256Signer) SignKey() interface{}      { return r.privateKey }
+func (r *RS256Signer) VerifyKey() interface{}    { return &r.privateKey.PublicKey }
+func (r *RS256Signer) KeyID() string             { return r.keyID }
+
+func (r *RS256Signer) JWKS() (JWKS, bool) {
+	pub := r.privateKey.PublicKey
+	return JWKS{Keys: []JWK{{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: "RS256",
+		Kid: r.keyID,
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}}, true
+}
+
+// JWKS is a JSON Web Key Set, as served from GET /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a single RSA public key within a JWKS.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// ParseRSAPrivateKeyPEM parses an RSA private key in PKCS#1 or PKCS#8
+// PEM encoding, as produced by "openssl genrsa" or "openssl genpkey".
+func ParseRSAPrivateKeyPEM(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("auth: no PEM block found in RSA private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("auth: parse RSA private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("auth: PEM key is not an RSA private key")
+	}
+	return rsaKey, nil
+}
+
+// JWKS returns the public verification key published for other services to
+// validate tokens issued by s, and whether s has one to publish.
+func (s *Service) JWKS() (JWKS, bool) {
+	return s.signer.JWKS()
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"context"
+	"log"
+)
+
+// SMSSender delivers a single text message. It is an interface, the same as
+// Mailer, so tests and local development can swap in a no-op or logging
+// implementation without a real SMS provider.
+type SMSSender interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// LogSMSSender logs text messages instead of sending them. It is the
+// default SMSSender until a real provider is wired in.
+type LogSMSSender struct{}
+
+// Send logs the message and always succeeds.
+func (LogSMSSender) Send(ctx context.Context, to, body string) error {
+	log.Printf("auth: sms to=%s body=%q", to, body)
+	return nil
+}
@@ -0,0 +1,18 @@
+package auth
+
+import "context"
+
+// NotifySMS texts body to userID's verified phone number, for
+// time-sensitive account-external notifications such as an event
+// cancellation. It does nothing, without error, if userID has no verified
+// phone number on file.
+func (s *Service) NotifySMS(ctx context.Context, userID int64, body string) error {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !u.PhoneVerified || !u.PhoneNumber.Valid {
+		return nil
+	}
+	return s.sms.Send(ctx, u.PhoneNumber.String, body)
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const twilioMessagesURLFormat = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+
+// TwilioSMSSender sends text messages through Twilio's REST API,
+// authenticated with an account SID and auth token rather than a full SDK,
+// the same tradeoff invitations.SMTPMailer makes by talking to an SMTP
+// relay directly.
+type TwilioSMSSender struct {
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// NewTwilioSMSSender returns a TwilioSMSSender that sends messages from the
+// Twilio number or alphanumeric sender ID "from", authenticated as
+// accountSID/authToken.
+func NewTwilioSMSSender(accountSID, authToken, from string) *TwilioSMSSender {
+	return &TwilioSMSSender{accountSID: accountSID, authToken: authToken, from: from, client: http.DefaultClient}
+}
+
+// Send delivers body to "to" through Twilio.
+func (t *TwilioSMSSender) Send(ctx context.Context, to, body string) error {
+	form := url.Values{"To": {to}, "From": {t.from}, "Body": {body}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(twilioMessagesURLFormat, t.accountSID), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("auth: build twilio request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.accountSID, t.authToken)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("auth: send sms: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("auth: send sms: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const backupCodeCount = 10
+
+// ErrTOTPRequired is returned by Login when the account has two-factor
+// authentication enabled. The caller must collect a TOTP or backup code
+// from the user and retry via VerifyTOTPLogin.
+var ErrTOTPRequired = errors.New("auth: totp verification required")
+
+// ErrInvalidTOTPCode is returned when a TOTP or backup code fails to verify.
+var ErrInvalidTOTPCode = errors.New("auth: invalid totp code")
+
+// EnableTOTP provisions two-factor authentication for userID, returning the
+// TOTP secret, a QR-code provisioning URI for authenticator apps, and a set
+// of one-time backup codes. The backup codes are returned once; only their
+// hashes are persisted, so a lost set cannot be recovered.
+func (s *Service) EnableTOTP(ctx context.Context, userID int64) (secret, provisioningURI string, backupCodes []string, err error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	secret, err = generateTOTPSecret()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	backupCodes, err = generateBackupCodes(backupCodeCount)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if err := s.users.EnableTOTP(ctx, userID, secret); err != nil {
+		return "", "", nil, err
+	}
+	if err := s.storeBackupCodes(ctx, userID, backupCodes); err != nil {
+		return "", "", nil, err
+	}
+
+	return secret, totpProvisioningURI(u.Email, secret), backupCodes, nil
+}
+
+// VerifyTOTPLogin completes a login that was halted by ErrTOTPRequired,
+// re-checking email and password and then accepting either a current TOTP
+// code or an unused backup code. device is recorded against the refresh
+// token for the session management endpoints.
+func (s *Service) VerifyTOTPLogin(ctx context.Context, email, password, code string, device Device) (int64, string, string, error) {
+	u, err := s.authenticate(ctx, email, password)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if !u.TOTPEnabled {
+		return 0, "", "", ErrInvalidTOTPCode
+	}
+
+	valid, err := validateTOTP(u.TOTPSecret.String, code)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if !valid {
+		consumed, err := s.consumeBackupCode(ctx, u.ID, code)
+		if err != nil {
+			return 0, "", "", err
+		}
+		if !consumed {
+			return 0, "", "", ErrInvalidTOTPCode
+		}
+	}
+
+	accessToken, refreshToken, err := s.issueTokenPair(ctx, u.ID, u.Email, u.Role, device)
+	if err != nil {
+		return 0, "", "", err
+	}
+	return u.ID, accessToken, refreshToken, nil
+}
+
+// storeBackupCodes persists the hash of each code in codes for userID,
+// replacing any codes issued by a previous EnableTOTP call.
+func (s *Service) storeBackupCodes(ctx context.Context, userID int64, codes []string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM two_factor_backup_codes WHERE user_id = $1`, userID,
+	); err != nil {
+		return fmt.Errorf("auth: clear backup codes: %w", err)
+	}
+
+	for _, code := range codes {
+		if _, err := s.db.ExecContext(ctx,
+			`INSERT INTO two_factor_backup_codes (user_id, code_hash) VALUES ($1, $2)`,
+			userID, hashToken(code),
+		); err != nil {
+			return fmt.Errorf("auth: store backup code: %w", err)
+		}
+	}
+	return nil
+}
+
+// consumeBackupCode marks the first unused backup code matching code as
+// used, reporting whether one was found.
+func (s *Service) consumeBackupCode(ctx context.Context, userID int64, code string) (bool, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE two_factor_backup_codes SET used_at = now()
+		 WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+		userID, hashToken(code),
+	)
+	if err != nil {
+		return false, fmt.Errorf("auth: consume backup code: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("auth: consume backup code: %w", err)
+	}
+	return n > 0, nil
+}
+
+// generateBackupCodes returns n random hex backup codes.
+func generateBackupCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("auth: generate backup code: %w", err)
+		}
+		codes[i] = hex.EncodeToString(buf)
+	}
+	return codes, nil
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const verificationTokenTTL = 24 * time.Hour
+
+// ErrInvalidVerificationToken is returned by VerifyEmail when the presented
+// token is unknown, expired, or already used.
+var ErrInvalidVerificationToken = errors.New("auth: invalid verification token")
+
+// sendVerificationEmail issues a verification token for userID and emails it
+// to email.
+func (s *Service) sendVerificationEmail(ctx context.Context, userID int64, email string) error {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("auth: generate verification token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO email_verification_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
+		userID, hashToken(token), time.Now().Add(verificationTokenTTL),
+	)
+	if err != nil {
+		return fmt.Errorf("auth: store verification token: %w", err)
+	}
+
+	return s.mailer.Send(ctx, email, "Verify your email",
+		fmt.Sprintf("Use this token to verify your email: %s", token))
+}
+
+// VerifyEmail confirms the account behind token, which must be an unused,
+// unexpired verification token issued at registration.
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	hash := hashToken(token)
+
+	var userID int64
+	var expiresAt time.Time
+	var usedAt sql.NullTime
+	row := s.db.QueryRowContext(ctx,
+		`SELECT user_id, expires_at, used_at FROM email_verification_tokens WHERE token_hash = $1`, hash,
+	)
+	switch err := row.Scan(&userID, &expiresAt, &usedAt); {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrInvalidVerificationToken
+	case err != nil:
+		return fmt.Errorf("auth: verify email: %w", err)
+	}
+	if usedAt.Valid || time.Now().After(expiresAt) {
+		return ErrInvalidVerificationToken
+	}
+
+	if err := s.users.MarkEmailVerified(ctx, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE email_verification_tokens SET used_at = now() WHERE token_hash = $1`, hash,
+	); err != nil {
+		return fmt.Errorf("auth: mark verification token used: %w", err)
+	}
+	return nil
+}
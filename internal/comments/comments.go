@@ -0,0 +1,140 @@
+// Package comments manages discussion threads posted on events.
+package comments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// ErrNotFound is returned when a comment lookup finds no matching row.
+var ErrNotFound = apperr.Wrap(apperr.ErrNotFound, "comments: not found")
+
+// ErrForbidden is returned when the caller may not perform the requested
+// action on a comment or its event.
+var ErrForbidden = apperr.Wrap(apperr.ErrForbidden, "comments: not permitted")
+
+// Comment is a single post in an event's discussion thread.
+type Comment struct {
+	ID        int64
+	EventID   int64
+	AuthorID  int64
+	Body      string
+	CreatedAt time.Time
+}
+
+// Store persists comments, deferring to events for visibility checks so
+// private-event threads stay restricted to attendees and the organizer.
+type Store struct {
+	db     *sql.DB
+	events *events.Store
+}
+
+// NewStore returns a Store backed by db.
+func NewStore(db *sql.DB, eventStore *events.Store) *Store {
+	return &Store{db: db, events: eventStore}
+}
+
+// Create posts body as a new comment on eventID by authorID. authorID must
+// be able to view the event (see events.Store.GetForViewer); otherwise
+// ErrForbidden is returned.
+func (s *Store) Create(ctx context.Context, eventID, authorID int64, body string) (*Comment, error) {
+	if _, err := s.events.GetForViewer(ctx, eventID, &authorID); err != nil {
+		if errors.Is(err, events.ErrForbidden) {
+			return nil, ErrForbidden
+		}
+		return nil, err
+	}
+
+	c := &Comment{EventID: eventID, AuthorID: authorID, Body: body}
+	row := s.db.QueryRowContext(ctx,
+		`INSERT INTO comments (event_id, author_id, body) VALUES ($1, $2, $3)
+		 RETURNING id, created_at`,
+		eventID, authorID, body,
+	)
+	if err := row.Scan(&c.ID, &c.CreatedAt); err != nil {
+		return nil, fmt.Errorf("comments: create: %w", err)
+	}
+	return c, nil
+}
+
+// List returns eventID's comments, oldest first, along with the total
+// number of comments on the event. viewerID is nil for an unauthenticated
+// caller; it is checked against the same visibility rule as Create.
+func (s *Store) List(ctx context.Context, eventID int64, viewerID *int64, limit, offset int) ([]*Comment, int, error) {
+	if _, err := s.events.GetForViewer(ctx, eventID, viewerID); err != nil {
+		if errors.Is(err, events.ErrForbidden) {
+			return nil, 0, ErrForbidden
+		}
+		return nil, 0, err
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM comments WHERE event_id = $1`, eventID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("comments: count: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, event_id, author_id, body, created_at FROM comments
+		 WHERE event_id = $1 ORDER BY created_at ASC, id ASC
+		 LIMIT $2 OFFSET $3`,
+		eventID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("comments: list: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Comment
+	for rows.Next() {
+		c := &Comment{}
+		if err := rows.Scan(&c.ID, &c.EventID, &c.AuthorID, &c.Body, &c.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("comments: list scan: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, total, rows.Err()
+}
+
+// Delete removes commentID, which only its author or the event's organizer
+// may do.
+func (s *Store) Delete(ctx context.Context, commentID, userID int64) error {
+	c, err := s.get(ctx, commentID)
+	if err != nil {
+		return err
+	}
+
+	e, err := s.events.Get(ctx, c.EventID)
+	if err != nil {
+		return err
+	}
+	if c.AuthorID != userID && e.OrganizerID != userID {
+		return ErrForbidden
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM comments WHERE id = $1`, commentID); err != nil {
+		return fmt.Errorf("comments: delete: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) get(ctx context.Context, id int64) (*Comment, error) {
+	c := &Comment{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, event_id, author_id, body, created_at FROM comments WHERE id = $1`, id,
+	)
+	if err := row.Scan(&c.ID, &c.EventID, &c.AuthorID, &c.Body, &c.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("comments: get: %w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,152 @@
+// Package config loads and validates the settings main needs to start the
+// server, so a missing or malformed environment variable is a startup
+// error instead of a silently wrong default deep inside a constructor.
+// Settings with no sane default (DatabaseURL, JWTSecret) are required;
+// everything else falls back to the value documented on its field.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/auth"
+)
+
+// DefaultAddr is used when ADDR is unset.
+const DefaultAddr = ":8080"
+
+// DefaultAutocertCacheDir is used when AUTOCERT_CACHE_DIR is unset.
+const DefaultAutocertCacheDir = "./autocert-cache"
+
+// DefaultHTTPRedirectAddr is used when HTTP_REDIRECT_ADDR is unset.
+const DefaultHTTPRedirectAddr = ":80"
+
+// DefaultQueryTimeout is used when QUERY_TIMEOUT is unset.
+const DefaultQueryTimeout = 10 * time.Second
+
+// DefaultRequestTimeout is used when REQUEST_TIMEOUT is unset.
+const DefaultRequestTimeout = 30 * time.Second
+
+// Config holds the settings read from the environment at startup.
+type Config struct {
+	// Addr is the address the HTTP server listens on, from ADDR.
+	Addr string
+	// DatabaseURL is the Postgres connection string, from DATABASE_URL.
+	// Required.
+	DatabaseURL string
+	// JWTSecret signs HS256 access tokens when no RSA_PRIVATE_KEY_PEM is
+	// configured, from JWT_SECRET. Required.
+	JWTSecret string
+	// AccessTokenTTL is how long an issued access token is valid, from
+	// ACCESS_TOKEN_TTL (a Go duration string, e.g. "15m").
+	AccessTokenTTL time.Duration
+	// RefreshTokenTTL is how long an issued refresh token is valid, from
+	// REFRESH_TOKEN_TTL (a Go duration string, e.g. "720h").
+	RefreshTokenTTL time.Duration
+	// TLSCertFile and TLSKeyFile, from TLS_CERT_FILE and TLS_KEY_FILE,
+	// serve HTTPS directly from a certificate/key pair on disk instead of
+	// requiring a TLS-terminating proxy in front of the server. Mutually
+	// exclusive with AutocertDomains.
+	TLSCertFile string
+	TLSKeyFile  string
+	// AutocertDomains, from AUTOCERT_DOMAINS (a comma-separated list of
+	// hostnames), serves HTTPS with a certificate obtained and renewed
+	// automatically from Let's Encrypt for those hostnames. Mutually
+	// exclusive with TLSCertFile/TLSKeyFile.
+	AutocertDomains []string
+	// AutocertCacheDir is where autocert persists obtained certificates
+	// between restarts, from AUTOCERT_CACHE_DIR. Only used when
+	// AutocertDomains is set.
+	AutocertCacheDir string
+	// HTTPRedirectAddr is the address an HTTP listener redirects to HTTPS
+	// from, from HTTP_REDIRECT_ADDR. Only started when TLS is enabled
+	// (TLSCertFile or AutocertDomains is set).
+	HTTPRedirectAddr string
+	// QueryTimeout bounds how long a database query run through
+	// db.WithQueryTimeout may take, from QUERY_TIMEOUT.
+	QueryTimeout time.Duration
+	// RequestTimeout bounds how long a request may run before the server
+	// cancels its context, from REQUEST_TIMEOUT. Streaming endpoints
+	// (SSE, WebSocket) are exempt; see httpapi.withRequestTimeout.
+	RequestTimeout time.Duration
+}
+
+// Load reads Config from the environment, applying defaults and returning
+// an error if a required setting is missing or a duration fails to parse.
+func Load() (*Config, error) {
+	cfg := &Config{
+		Addr:             os.Getenv("ADDR"),
+		DatabaseURL:      os.Getenv("DATABASE_URL"),
+		JWTSecret:        os.Getenv("JWT_SECRET"),
+		TLSCertFile:      os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:       os.Getenv("TLS_KEY_FILE"),
+		AutocertCacheDir: os.Getenv("AUTOCERT_CACHE_DIR"),
+		HTTPRedirectAddr: os.Getenv("HTTP_REDIRECT_ADDR"),
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = DefaultAddr
+	}
+	if cfg.AutocertCacheDir == "" {
+		cfg.AutocertCacheDir = DefaultAutocertCacheDir
+	}
+	if cfg.HTTPRedirectAddr == "" {
+		cfg.HTTPRedirectAddr = DefaultHTTPRedirectAddr
+	}
+	if domains := os.Getenv("AUTOCERT_DOMAINS"); domains != "" {
+		for _, d := range strings.Split(domains, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				cfg.AutocertDomains = append(cfg.AutocertDomains, d)
+			}
+		}
+	}
+
+	var err error
+	if cfg.AccessTokenTTL, err = parseDuration("ACCESS_TOKEN_TTL", auth.DefaultAccessTokenTTL); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshTokenTTL, err = parseDuration("REFRESH_TOKEN_TTL", auth.DefaultRefreshTokenTTL); err != nil {
+		return nil, err
+	}
+	if cfg.QueryTimeout, err = parseDuration("QUERY_TIMEOUT", DefaultQueryTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.RequestTimeout, err = parseDuration("REQUEST_TIMEOUT", DefaultRequestTimeout); err != nil {
+		return nil, err
+	}
+
+	if cfg.DatabaseURL == "" {
+		return nil, fmt.Errorf("config: DATABASE_URL is required")
+	}
+	if cfg.JWTSecret == "" {
+		return nil, fmt.Errorf("config: JWT_SECRET is required")
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, fmt.Errorf("config: TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSCertFile != "" && len(cfg.AutocertDomains) > 0 {
+		return nil, fmt.Errorf("config: TLS_CERT_FILE and AUTOCERT_DOMAINS are mutually exclusive")
+	}
+	return cfg, nil
+}
+
+// TLSEnabled reports whether cfg configures HTTPS, either from a
+// certificate/key pair on disk or from Let's Encrypt autocert.
+func (cfg *Config) TLSEnabled() bool {
+	return cfg.TLSCertFile != "" || len(cfg.AutocertDomains) > 0
+}
+
+// parseDuration returns the duration in the environment variable name, or
+// def if it's unset, or an error if it's set but not a valid Go duration.
+func parseDuration(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("config: %s: %w", name, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,77 @@
+// Package db holds the shared database connection used by the rest of the
+// application. Schema migrations live in internal/db/migrations and are
+// applied automatically by main on startup, via migrations.Up.
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// DefaultQueryTimeout is used by WithQueryTimeout when QueryTimeout hasn't
+// been overridden.
+const DefaultQueryTimeout = 10 * time.Second
+
+// QueryTimeout bounds how long a query started through WithQueryTimeout
+// may run. main sets it once at startup from config, the same way
+// logging's default slog.Logger is configured once and read everywhere
+// else.
+var QueryTimeout = DefaultQueryTimeout
+
+// Connect opens a connection pool to the Postgres database at dsn and
+// verifies it is reachable.
+func Connect(dsn string) (*sql.DB, error) {
+	database, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("db: open: %w", err)
+	}
+
+	if err := database.Ping(); err != nil {
+		return nil, fmt.Errorf("db: ping: %w", err)
+	}
+
+	return database, nil
+}
+
+// WithQueryTimeout returns a context that's cancelled after QueryTimeout,
+// for callers about to run a query. If ctx already has a deadline (an
+// HTTP request's context, bounded by the server's request timeout
+// middleware, typically does) it's returned unchanged, so the earlier
+// deadline wins rather than being extended; this only bites when ctx
+// carries none, such as a background scheduler tick.
+func WithQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, QueryTimeout)
+}
+
+// WithTx runs fn inside a transaction on database, committing if fn
+// returns nil and rolling back otherwise. It's the unit-of-work a
+// multi-step operation uses to make its writes atomic; events.joinEvent
+// and invitations.Store.Accept are both examples, the latter passing the
+// *sql.Tx into a second Store's *InTx method so writes to two tables
+// (events and invitations) commit or roll back together even though
+// they're owned by different packages. Errors from fn are returned
+// unwrapped, since they're typically a sentinel the caller already
+// handles with errors.Is; only the begin/commit steps get a "db:"
+// prefix.
+func WithTx(ctx context.Context, database *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("db: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("db: commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+// Package migrations applies the SQL files under files/ to bring a
+// database up to the schema the rest of the application expects. Each
+// migration is a pair of numbered files, <NNNN>_<name>.up.sql and
+// <NNNN>_<name>.down.sql, embedded into the binary so a deploy needs
+// nothing on disk beyond the compiled server. Applied migrations are
+// tracked in a schema_migrations table, so Up only ever runs what a given
+// database hasn't already seen.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed files/*.sql
+var files embed.FS
+
+// migration is one numbered schema change, with its forward (up) and
+// rollback (down) SQL.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// Up applies every migration not yet recorded in schema_migrations, in
+// version order, each inside its own transaction. It creates
+// schema_migrations if it doesn't exist yet, and returns the versions it
+// applied.
+func Up(ctx context.Context, db *sql.DB) ([]int, error) {
+	all, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, applied_at TIMESTAMPTZ NOT NULL DEFAULT now())`,
+	); err != nil {
+		return nil, fmt.Errorf("migrations: create schema_migrations: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []int
+	for _, m := range all {
+		if applied[m.version] {
+			continue
+		}
+		if err := apply(ctx, db, m); err != nil {
+			return ran, err
+		}
+		ran = append(ran, m.version)
+	}
+	return ran, nil
+}
+
+// Pending returns the number of migrations that have not yet been
+// applied to db. Callers use this for a readiness check: a server whose
+// schema is behind the code it's running shouldn't be marked ready.
+func Pending(ctx context.Context, db *sql.DB) (int, error) {
+	all, err := load()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, m := range all {
+		if !applied[m.version] {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// Down rolls back the most recently applied migration, and returns the
+// version it rolled back, or 0 if there was nothing to roll back.
+func Down(ctx context.Context, db *sql.DB) (int, error) {
+	all, err := load()
+	if err != nil {
+		return 0, err
+	}
+	byVersion := make(map[int]migration, len(all))
+	for _, m := range all {
+		byVersion[m.version] = m
+	}
+
+	var current int
+	row := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`)
+	if err := row.Scan(&current); err != nil {
+		return 0, fmt.Errorf("migrations: current version: %w", err)
+	}
+	if current == 0 {
+		return 0, nil
+	}
+
+	m, ok := byVersion[current]
+	if !ok {
+		return 0, fmt.Errorf("migrations: no migration file found for applied version %04d", current)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("migrations: begin rollback %04d: %w", current, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.down); err != nil {
+		return 0, fmt.Errorf("migrations: rollback %04d_%s: %w", current, m.name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, current); err != nil {
+		return 0, fmt.Errorf("migrations: unrecord %04d: %w", current, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("migrations: commit rollback %04d: %w", current, err)
+	}
+	return current, nil
+}
+
+// apply runs m.up and records it as applied, inside a single transaction.
+func apply(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrations: begin %04d: %w", m.version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.up); err != nil {
+		return fmt.Errorf("migrations: apply %04d_%s: %w", m.version, m.name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, m.version); err != nil {
+		return fmt.Errorf("migrations: record %04d: %w", m.version, err)
+	}
+	return tx.Commit()
+}
+
+// appliedVersions returns the set of migration versions already recorded
+// in schema_migrations.
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("migrations: applied versions: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("migrations: scan applied version: %w", err)
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// load reads every embedded *.up.sql/*.down.sql pair into a sorted list of
+// migrations.
+func load() ([]migration, error) {
+	entries, err := fs.ReadDir(files, "files")
+	if err != nil {
+		return nil, fmt.Errorf("migrations: read embedded files: %w", err)
+	}
+
+	byVersion := map[int]*migration{}
+	for _, entry := range entries {
+		version, name, kind, err := parseFilename(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := fs.ReadFile(files, "files/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrations: read %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: name}
+			byVersion[version] = m
+		}
+		switch kind {
+		case "up":
+			m.up = string(data)
+		case "down":
+			m.down = string(data)
+		}
+	}
+
+	out := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migrations: %04d_%s is missing its up or down file", m.version, m.name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].version < out[j].version })
+	return out, nil
+}
+
+// parseFilename splits a migration filename of the form
+// "<NNNN>_<name>.<up|down>.sql" into its version, name, and kind.
+func parseFilename(filename string) (version int, name, kind string, err error) {
+	base := strings.TrimSuffix(filename, ".sql")
+	ext := fileExt(base)
+	kind = strings.TrimPrefix(ext, ".")
+	if kind != "up" && kind != "down" {
+		return 0, "", "", fmt.Errorf("migrations: %s does not end in .up.sql or .down.sql", filename)
+	}
+	base = strings.TrimSuffix(base, ext)
+
+	versionStr, name, ok := strings.Cut(base, "_")
+	if !ok {
+		return 0, "", "", fmt.Errorf("migrations: %s is not named <version>_<name>.<up|down>.sql", filename)
+	}
+	version, err = strconv.Atoi(versionStr)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("migrations: %s has a non-numeric version: %w", filename, err)
+	}
+	return version, name, kind, nil
+}
+
+// fileExt mirrors path/filepath.Ext, avoiding an import purely for this
+// one call since embed.FS paths always use forward slashes.
+func fileExt(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i:]
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+// Package digest emails each subscribed user a weekly summary of the
+// events they're attending or have a pending invitation to in the coming
+// week. It is opt-in: a user is only considered for a digest once they
+// subscribe.
+package digest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Store sends weekly digests.
+type Store struct {
+	db     *sql.DB
+	mailer Mailer
+}
+
+// NewStore returns a Store that delivers digests through mailer.
+func NewStore(db *sql.DB, mailer Mailer) *Store {
+	return &Store{db: db, mailer: mailer}
+}
+
+// Subscribe opts userID into the weekly digest. It is safe to call on an
+// already-subscribed user.
+func (s *Store) Subscribe(ctx context.Context, userID int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO digest_subscriptions (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING`,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("digest: subscribe: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe opts userID out of the weekly digest. It is safe to call on a
+// user who was never subscribed.
+func (s *Store) Unsubscribe(ctx context.Context, userID int64) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM digest_subscriptions WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("digest: unsubscribe: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// resendGuardWindow keeps SendDueDigests from emailing the same subscriber
+// twice within a week, in case it's invoked more than once on the target
+// weekday (e.g. by a scheduler tick shortly after midnight and another
+// later the same day).
+const resendGuardWindow = 6 * 24 * time.Hour
+
+// upcomingEvent is one line of a subscriber's digest: an event they're
+// attending or have a pending invitation to in the coming week.
+type upcomingEvent struct {
+	title    string
+	startsAt time.Time
+	location string
+	pending  bool
+}
+
+// SendDueDigests emails every subscriber who hasn't already received one
+// in the last resendGuardWindow a summary of the events they're attending
+// or have a pending invitation to over the next 7 days, provided
+// time.Now()'s weekday matches targetWeekday. A subscriber with no
+// upcoming events is skipped; there's nothing useful to send them. It's
+// meant to run periodically from a scheduled job, and returns the number
+// of digests sent.
+func (s *Store) SendDueDigests(ctx context.Context, targetWeekday time.Weekday) (int, error) {
+	if time.Now().Weekday() != targetWeekday {
+		return 0, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT ds.user_id, u.email FROM digest_subscriptions ds
+		 JOIN users u ON u.id = ds.user_id
+		 WHERE ds.last_sent_at IS NULL OR ds.last_sent_at <= $1`,
+		time.Now().Add(-resendGuardWindow),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("digest: due subscribers: %w", err)
+	}
+
+	type subscriber struct {
+		userID int64
+		email  string
+	}
+	var subscribers []subscriber
+	for rows.Next() {
+		var sub subscriber
+		if err := rows.Scan(&sub.userID, &sub.email); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("digest: scan due subscriber: %w", err)
+		}
+		subscribers = append(subscribers, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	sent := 0
+	for _, sub := range subscribers {
+		events, err := s.upcomingEventsForUser(ctx, sub.userID)
+		if err != nil {
+			return sent, err
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		if err := s.mailer.Send(ctx, sub.email, "Your week ahead", renderDigest(events)); err != nil {
+			return sent, fmt.Errorf("digest: send digest: %w", err)
+		}
+		if _, err := s.db.ExecContext(ctx,
+			`UPDATE digest_subscriptions SET last_sent_at = now() WHERE user_id = $1`, sub.userID,
+		); err != nil {
+			return sent, fmt.Errorf("digest: stamp digest: %w", err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// upcomingEventsForUser returns userID's events starting in the next 7
+// days, both ones they're attending and ones they have a pending
+// invitation to, ordered by start time.
+func (s *Store) upcomingEventsForUser(ctx context.Context, userID int64) ([]upcomingEvent, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT e.title, e.starts_at, e.location, false AS pending
+		 FROM event_attendees ea JOIN events e ON e.id = ea.event_id
+		 WHERE ea.user_id = $1 AND ea.status = 'going'
+		   AND e.starts_at > now() AND e.starts_at <= now() + interval '7 days'
+		 UNION ALL
+		 SELECT e.title, e.starts_at, e.location, true AS pending
+		 FROM invitations i JOIN events e ON e.id = i.event_id
+		 WHERE i.invitee_user_id = $1 AND i.status = 'pending'
+		   AND e.starts_at > now() AND e.starts_at <= now() + interval '7 days'
+		 ORDER BY starts_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("digest: upcoming events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []upcomingEvent
+	for rows.Next() {
+		var e upcomingEvent
+		if err := rows.Scan(&e.title, &e.startsAt, &e.location, &e.pending); err != nil {
+			return nil, fmt.Errorf("digest: scan upcoming event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// renderDigest builds the HTML body listing events, each tagged with
+// whether the recipient is attending or still has a pending invitation.
+func renderDigest(events []upcomingEvent) string {
+	body := "<p>Here's what's coming up this week:</p><ul>"
+	for _, e := range events {
+		status := "attending"
+		if e.pending {
+			status = "invited, awaiting your RSVP"
+		}
+		body += fmt.Sprintf(
+			"<li><strong>%s</strong> &mdash; %s at %s (%s)</li>",
+			e.title, e.startsAt.Format(time.RFC1123), e.location, status,
+		)
+	}
+	body += "</ul>"
+	return body
+}
@@ -0,0 +1,23 @@
+package digest
+
+import (
+	"context"
+	"log"
+)
+
+// Mailer delivers a single email. It is an interface, the same as
+// invitations.Mailer, so tests and local development can swap in a no-op or
+// logging implementation without a real mail provider.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogMailer logs emails instead of sending them. It is the default Mailer
+// until a real provider is wired in.
+type LogMailer struct{}
+
+// Send logs the email and always succeeds.
+func (LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("digest: mail to=%s subject=%q body=%q", to, subject, body)
+	return nil
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ArchiveEndedEvents marks every non-archived event whose end time (or
+// start time, for events with no EndsAt) has passed as archived, and
+// returns the number of events archived. It's meant to run periodically
+// from a scheduled job (see runArchiveScheduler in cmd/server/main.go)
+// rather than per request, the same as RecomputeTrendingScores.
+func (s *Store) ArchiveEndedEvents(ctx context.Context) (int, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE events SET archived = true
+		 WHERE archived = false AND COALESCE(ends_at, starts_at) <= $1`,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("events: archive ended events: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("events: archive ended events rows affected: %w", err)
+	}
+	return int(affected), nil
+}
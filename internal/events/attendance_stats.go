@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RSVPTimelineBucket is the number of attendees who responded on a single
+// day, used to chart RSVP momentum leading up to an event.
+type RSVPTimelineBucket struct {
+	Day   time.Time
+	Count int
+}
+
+// AttendanceStats is an organizer-facing summary of an event's attendance.
+type AttendanceStats struct {
+	// AttendeesByStatus maps each Status* constant to the number of
+	// attendees currently in that status.
+	AttendeesByStatus map[string]int
+	CheckedInCount    int
+	// InvitationAcceptanceRate is the fraction of sent invitations that have
+	// been accepted, or nil if no invitations have been sent.
+	InvitationAcceptanceRate *float64
+	// RSVPTimeline is the count of attendees responding each day, ordered
+	// oldest first.
+	RSVPTimeline []RSVPTimelineBucket
+}
+
+// AttendanceStats computes an attendance summary for eventID: attendee
+// counts by status, check-in count, invitation acceptance rate, and a
+// day-by-day RSVP timeline. Everything is aggregated in SQL rather than
+// loaded row by row.
+func (s *Store) AttendanceStats(ctx context.Context, eventID int64) (*AttendanceStats, error) {
+	byStatus, err := s.statsByStatus(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkedIn int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM event_attendees WHERE event_id = $1 AND checked_in_at IS NOT NULL`,
+		eventID,
+	).Scan(&checkedIn); err != nil {
+		return nil, fmt.Errorf("events: attendance stats checked in: %w", err)
+	}
+
+	acceptanceRate, err := s.invitationAcceptanceRate(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline, err := s.rsvpTimeline(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttendanceStats{
+		AttendeesByStatus:        byStatus,
+		CheckedInCount:           checkedIn,
+		InvitationAcceptanceRate: acceptanceRate,
+		RSVPTimeline:             timeline,
+	}, nil
+}
+
+func (s *Store) statsByStatus(ctx context.Context, eventID int64) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT status, count(*) FROM event_attendees WHERE event_id = $1 GROUP BY status`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: attendance stats by status: %w", err)
+	}
+	defer rows.Close()
+
+	byStatus := map[string]int{}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("events: attendance stats by status scan: %w", err)
+		}
+		byStatus[status] = count
+	}
+	return byStatus, rows.Err()
+}
+
+// invitationAcceptanceRate queries the invitations table directly by name,
+// without importing the invitations package, to avoid a dependency cycle
+// (invitations already imports events).
+func (s *Store) invitationAcceptanceRate(ctx context.Context, eventID int64) (*float64, error) {
+	var total, accepted int
+	row := s.db.QueryRowContext(ctx,
+		`SELECT count(*), count(*) FILTER (WHERE status = 'accepted') FROM invitations WHERE event_id = $1`,
+		eventID,
+	)
+	if err := row.Scan(&total, &accepted); err != nil {
+		return nil, fmt.Errorf("events: attendance stats invitation acceptance: %w", err)
+	}
+	if total == 0 {
+		return nil, nil
+	}
+	rate := float64(accepted) / float64(total)
+	return &rate, nil
+}
+
+func (s *Store) rsvpTimeline(ctx context.Context, eventID int64) ([]RSVPTimelineBucket, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT date_trunc('day', responded_at), count(*)
+		 FROM event_attendees WHERE event_id = $1
+		 GROUP BY date_trunc('day', responded_at)
+		 ORDER BY date_trunc('day', responded_at) ASC`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: attendance stats rsvp timeline: %w", err)
+	}
+	defer rows.Close()
+
+	var timeline []RSVPTimelineBucket
+	for rows.Next() {
+		var bucket RSVPTimelineBucket
+		if err := rows.Scan(&bucket.Day, &bucket.Count); err != nil {
+			return nil, fmt.Errorf("events: attendance stats rsvp timeline scan: %w", err)
+		}
+		timeline = append(timeline, bucket)
+	}
+	return timeline, rows.Err()
+}
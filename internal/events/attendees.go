@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Attendee export roles. Mirrors the role values invitations.Store writes
+// to the invitations table; duplicated here rather than imported to avoid
+// a dependency cycle (invitations already imports events).
+const (
+	exportRoleAttendee     = "attendee"
+	exportRoleCollaborator = "collaborator"
+	exportRoleOrganizer    = "organizer"
+)
+
+// AttendeeExportRow is one row of an organizer's attendee export: enough
+// detail to prepare a badge list without a second lookup.
+type AttendeeExportRow struct {
+	Name      string
+	Email     string
+	Role      string
+	Status    string
+	CheckedIn bool
+}
+
+// AttendeeDetail is one registered attendee of an event, with the user
+// details joined in so callers don't need a follow-up lookup per ID.
+type AttendeeDetail struct {
+	UserID    int64
+	Name      string
+	Email     string
+	Status    string
+	CheckedIn bool
+}
+
+// AttendeeList is the response for GetEventAttendees: the attendees
+// themselves plus aggregate counts by status.
+type AttendeeList struct {
+	Attendees []AttendeeDetail
+	// ByStatus maps each Status* constant to the number of attendees
+	// currently in that status; see Store.AttendanceStats.
+	ByStatus map[string]int
+}
+
+// GetEventAttendees returns the registered attendees of eventID, with
+// their display name and email joined in from the users table, plus
+// aggregate counts by status. Unlike ListAttendeesForExport, it does not
+// include provisional attendees who were invited by email and have not
+// yet claimed an account, since those have no user row to join against.
+func (s *Store) GetEventAttendees(ctx context.Context, eventID int64) (*AttendeeList, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT u.id, u.display_name, u.email, ea.status, ea.checked_in_at IS NOT NULL
+		 FROM event_attendees ea
+		 JOIN users u ON u.id = ea.user_id
+		 WHERE ea.event_id = $1
+		 ORDER BY u.display_name ASC`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: get event attendees: %w", err)
+	}
+	defer rows.Close()
+
+	var attendees []AttendeeDetail
+	for rows.Next() {
+		var a AttendeeDetail
+		if err := rows.Scan(&a.UserID, &a.Name, &a.Email, &a.Status, &a.CheckedIn); err != nil {
+			return nil, fmt.Errorf("events: get event attendees scan: %w", err)
+		}
+		attendees = append(attendees, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byStatus, err := s.statsByStatus(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttendeeList{Attendees: attendees, ByStatus: byStatus}, nil
+}
+
+// ListAttendeesForExport returns every attendee of eventID, including
+// provisional attendees invited by email who have not yet claimed an
+// account, ordered by name for a stable badge-printing order. Role is
+// "organizer" or "collaborator" for attendees with an accepted invitation
+// of that role and "attendee" otherwise.
+func (s *Store) ListAttendeesForExport(ctx context.Context, eventID int64) ([]AttendeeExportRow, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT
+		     COALESCE(u.display_name, ''),
+		     COALESCE(u.email, ea.email, ''),
+		     ea.status,
+		     ea.checked_in_at IS NOT NULL,
+		     (SELECT i.role FROM invitations i
+		      WHERE i.event_id = ea.event_id AND i.invitee_user_id = ea.user_id
+		        AND i.status = 'accepted' AND i.role IN ('collaborator', 'organizer')
+		      LIMIT 1)
+		 FROM event_attendees ea
+		 LEFT JOIN users u ON u.id = ea.user_id
+		 WHERE ea.event_id = $1
+		 ORDER BY COALESCE(u.display_name, ea.email) ASC`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: list attendees for export: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AttendeeExportRow
+	for rows.Next() {
+		var row AttendeeExportRow
+		var manageRole sql.NullString
+		if err := rows.Scan(&row.Name, &row.Email, &row.Status, &row.CheckedIn, &manageRole); err != nil {
+			return nil, fmt.Errorf("events: list attendees for export scan: %w", err)
+		}
+		switch manageRole.String {
+		case exportRoleOrganizer:
+			row.Role = exportRoleOrganizer
+		case exportRoleCollaborator:
+			row.Role = exportRoleCollaborator
+		default:
+			row.Role = exportRoleAttendee
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
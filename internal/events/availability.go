@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BusyInterval is a span of time a user is occupied by an event they are
+// StatusGoing to.
+type BusyInterval struct {
+	EventID  int64     `json:"event_id"`
+	Title    string    `json:"title"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// Availability maps a user ID to the busy intervals found for them.
+type Availability map[int64][]BusyInterval
+
+// FreeBusy returns, for each of userIDs, the events within [from, until)
+// they are already StatusGoing to, the same overlap rule ConflictingEvents
+// uses. An event with no EndsAt is treated as occupying only its StartsAt
+// instant. Users with no busy intervals in the range are included with a
+// nil slice.
+func (s *Store) FreeBusy(ctx context.Context, userIDs []int64, from, until time.Time) (Availability, error) {
+	out := make(Availability, len(userIDs))
+	for _, userID := range userIDs {
+		out[userID] = nil
+	}
+	if len(userIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT ea.user_id, e.id, e.title, e.starts_at, e.ends_at
+		 FROM event_attendees ea
+		 JOIN events e ON e.id = ea.event_id
+		 WHERE ea.user_id = ANY($1) AND ea.status = $2 AND e.status != $3
+		   AND COALESCE(e.ends_at, e.starts_at) >= $4 AND e.starts_at < $5`,
+		userIDs, StatusGoing, EventStatusCancelled, from, until,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: free/busy: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var b BusyInterval
+		var endsAt *time.Time
+		if err := rows.Scan(&userID, &b.EventID, &b.Title, &b.StartsAt, &endsAt); err != nil {
+			return nil, fmt.Errorf("events: free/busy scan: %w", err)
+		}
+		if endsAt != nil {
+			b.EndsAt = *endsAt
+		} else {
+			b.EndsAt = b.StartsAt
+		}
+		out[userID] = append(out[userID], b)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+)
+
+// ErrBanned is returned by JoinEvent and joinEvent when userID has been
+// banned from eventID; see BanAttendee.
+var ErrBanned = apperr.Wrap(apperr.ErrForbidden, "events: banned from this event")
+
+// BanAttendee removes userID's attendance of eventID, if any, and records
+// a ban so future joins are rejected with ErrBanned. Removing an attendee
+// who was StatusGoing frees their capacity slot, so the longest-waiting
+// StatusWaitlisted attendee (if any) is promoted, same as LeaveEvent.
+// Banning an already-banned user is a no-op.
+func (s *Store) BanAttendee(ctx context.Context, eventID, userID, bannedByUserID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("events: ban: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM event_attendees WHERE event_id = $1 AND user_id = $2`, eventID, userID,
+	); err != nil {
+		return fmt.Errorf("events: ban: remove attendee: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO event_bans (event_id, user_id, banned_by_user_id) VALUES ($1, $2, $3)
+		 ON CONFLICT (event_id, user_id) DO NOTHING`,
+		eventID, userID, bannedByUserID,
+	); err != nil {
+		return fmt.Errorf("events: ban: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("events: ban: commit: %w", err)
+	}
+
+	return s.promoteFromWaitlist(ctx, eventID)
+}
+
+// isBanned reports whether userID has been banned from eventID.
+func isBanned(ctx context.Context, q querier, eventID, userID int64) (bool, error) {
+	var banned bool
+	row := q.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM event_bans WHERE event_id = $1 AND user_id = $2)`,
+		eventID, userID,
+	)
+	if err := row.Scan(&banned); err != nil {
+		return false, fmt.Errorf("events: check banned: %w", err)
+	}
+	return banned, nil
+}
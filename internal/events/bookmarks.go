@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Bookmark adds eventID to userID's private bookmark list. Bookmarking is
+// independent of attendance and does not require joining the event.
+func (s *Store) Bookmark(ctx context.Context, userID, eventID int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO bookmarks (user_id, event_id) VALUES ($1, $2)
+		 ON CONFLICT (user_id, event_id) DO NOTHING`,
+		userID, eventID,
+	)
+	if err != nil {
+		return fmt.Errorf("events: bookmark: %w", err)
+	}
+	return nil
+}
+
+// Unbookmark removes eventID from userID's bookmark list.
+func (s *Store) Unbookmark(ctx context.Context, userID, eventID int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM bookmarks WHERE user_id = $1 AND event_id = $2`, userID, eventID,
+	)
+	if err != nil {
+		return fmt.Errorf("events: unbookmark: %w", err)
+	}
+	return nil
+}
+
+// ListBookmarkedUpcoming returns userID's bookmarked events that have not
+// started yet, soonest first.
+func (s *Store) ListBookmarkedUpcoming(ctx context.Context, userID int64) ([]*Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT e.id, e.organizer_id, e.title, e.description, e.location, e.starts_at, e.ends_at,
+		        e.rsvp_deadline, e.capacity, e.min_attendees, e.status, e.latitude, e.longitude, e.rrule, e.visibility, e.created_at
+		 FROM bookmarks b
+		 JOIN events e ON e.id = b.event_id
+		 WHERE b.user_id = $1 AND e.starts_at >= $2
+		 ORDER BY e.starts_at ASC`,
+		userID, time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: list bookmarked upcoming: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Event
+	for rows.Next() {
+		e := &Event{}
+		if err := rows.Scan(&e.ID, &e.OrganizerID, &e.Title, &e.Description, &e.Location,
+			&e.StartsAt, &e.EndsAt, &e.RSVPDeadline, &e.Capacity, &e.MinAttendees, &e.Status, &e.Latitude, &e.Longitude, &e.RRule, &e.Visibility, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("events: list bookmarked upcoming scan: %w", err)
+		}
+		e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+		e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+// isUpcoming mirrors the starts_at >= now() filter applied in
+// ListBookmarkedUpcoming's query.
+func isUpcoming(e *Event, now time.Time) bool {
+	return !e.StartsAt.Before(now)
+}
+
+func TestListBookmarkedUpcoming_ExcludesPastEvents(t *testing.T) {
+	now := time.Now()
+	past := &Event{StartsAt: now.Add(-time.Hour)}
+	future := &Event{StartsAt: now.Add(time.Hour)}
+
+	if isUpcoming(past, now) {
+		t.Fatal("expected a past event to be excluded")
+	}
+	if !isUpcoming(future, now) {
+		t.Fatal("expected a future event to be included")
+	}
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Bulk operation kinds accepted by BulkUpdate.
+const (
+	BulkOpCancel     = "cancel"
+	BulkOpDelete     = "delete"
+	BulkOpUpdateDate = "update_date"
+)
+
+// ErrUnknownBulkOp is returned for a BulkItemInput.Op that BulkUpdate does
+// not recognize.
+var ErrUnknownBulkOp = errors.New("events: unknown bulk operation")
+
+// BulkItemInput is one item of a BulkUpdate request.
+type BulkItemInput struct {
+	EventID int64
+	Op      string
+	// StartsAt and EndsAt are only read for BulkOpUpdateDate; EndsAt
+	// follows the same double-pointer convention as UpdateInput.
+	StartsAt *time.Time
+	EndsAt   **time.Time
+}
+
+// BulkItemResult reports the outcome of one BulkItemInput. Err is nil on
+// success.
+type BulkItemResult struct {
+	EventID int64
+	Op      string
+	Err     error
+}
+
+// BulkUpdate applies a batch of cancel/delete/update_date operations
+// across organizerID's events. Each item succeeds or fails independently
+// rather than all being wrapped in one transaction: a typo in item 7 of a
+// 50-item batch shouldn't roll back the 6 that already succeeded, and
+// callers need per-item results regardless. This is the same tradeoff
+// polls.Finalize takes with cross-call atomicity. An item targeting an
+// event the caller doesn't organize, or naming an unknown Op, fails that
+// item without affecting the others.
+//
+// delete has no hard-delete equivalent in this package; see CancelEvent's
+// doc comment for why. It is handled identically to cancel.
+func (s *Store) BulkUpdate(ctx context.Context, organizerID int64, items []BulkItemInput) []BulkItemResult {
+	results := make([]BulkItemResult, len(items))
+	for i, item := range items {
+		results[i] = BulkItemResult{EventID: item.EventID, Op: item.Op, Err: s.applyBulkItem(ctx, organizerID, item)}
+	}
+	return results
+}
+
+func (s *Store) applyBulkItem(ctx context.Context, organizerID int64, item BulkItemInput) error {
+	e, err := s.Get(ctx, item.EventID)
+	if err != nil {
+		return err
+	}
+	if e.OrganizerID != organizerID {
+		return ErrForbidden
+	}
+
+	switch item.Op {
+	case BulkOpCancel, BulkOpDelete:
+		_, err := s.CancelEvent(ctx, item.EventID)
+		return err
+	case BulkOpUpdateDate:
+		_, err := s.Update(ctx, item.EventID, UpdateInput{StartsAt: item.StartsAt, EndsAt: item.EndsAt})
+		return err
+	default:
+		return ErrUnknownBulkOp
+	}
+}
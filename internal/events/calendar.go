@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListForUser returns every event userID organizes or is an attendee of
+// (excluding cancelled attendance), soonest start first, for use in a
+// personal calendar feed.
+func (s *Store) ListForUser(ctx context.Context, userID int64) ([]*Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, organizer_id, title, description, location, starts_at, ends_at, rsvp_deadline, capacity, min_attendees, status, latitude, longitude, rrule, visibility, created_at
+		 FROM events
+		 WHERE organizer_id = $1
+		    OR id IN (SELECT event_id FROM event_attendees WHERE user_id = $1 AND status != $2)
+		 ORDER BY starts_at ASC`,
+		userID, StatusNotGoing,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: list for user: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Event
+	for rows.Next() {
+		e := &Event{}
+		if err := rows.Scan(&e.ID, &e.OrganizerID, &e.Title, &e.Description, &e.Location,
+			&e.StartsAt, &e.EndsAt, &e.RSVPDeadline, &e.Capacity, &e.MinAttendees, &e.Status, &e.Latitude, &e.Longitude, &e.RRule, &e.Visibility, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("events: list for user scan: %w", err)
+		}
+		e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+		e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Check-in log outcomes.
+const (
+	CheckInResultSuccess   = "success"
+	CheckInResultDuplicate = "duplicate"
+	CheckInResultInvalid   = "invalid"
+)
+
+// ErrInvalidCheckInCode is returned when a check-in code does not match any
+// attendee of the event.
+var ErrInvalidCheckInCode = errors.New("events: invalid check-in code")
+
+// ErrAlreadyCheckedIn is returned when a check-in code has already been
+// used to check in.
+var ErrAlreadyCheckedIn = errors.New("events: attendee already checked in")
+
+// CheckInLogEntry is one recorded check-in attempt.
+type CheckInLogEntry struct {
+	ID            int64
+	EventID       int64
+	CodeAttempted string
+	Result        string
+	UserID        *int64
+	CreatedAt     time.Time
+}
+
+// CheckIn validates code against eventID's attendees and marks them checked
+// in on success. Every attempt, successful or not, is recorded in the
+// check-in log.
+func (s *Store) CheckIn(ctx context.Context, eventID int64, code string) error {
+	var userID int64
+	var checkedInAt *time.Time
+	row := s.db.QueryRowContext(ctx,
+		`SELECT user_id, checked_in_at FROM event_attendees WHERE event_id = $1 AND checkin_code = $2`,
+		eventID, code,
+	)
+	err := row.Scan(&userID, &checkedInAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return s.logCheckIn(ctx, eventID, code, CheckInResultInvalid, nil, ErrInvalidCheckInCode)
+	case err != nil:
+		return fmt.Errorf("events: check in: %w", err)
+	case checkedInAt != nil:
+		return s.logCheckIn(ctx, eventID, code, CheckInResultDuplicate, &userID, ErrAlreadyCheckedIn)
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE event_attendees SET checked_in_at = now() WHERE event_id = $1 AND checkin_code = $2`,
+		eventID, code,
+	); err != nil {
+		return fmt.Errorf("events: check in: %w", err)
+	}
+
+	return s.logCheckIn(ctx, eventID, code, CheckInResultSuccess, &userID, nil)
+}
+
+// logCheckIn records a check-in attempt and returns outcomeErr so callers
+// can both log and propagate the result in one line.
+func (s *Store) logCheckIn(ctx context.Context, eventID int64, code, result string, userID *int64, outcomeErr error) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO check_in_log (event_id, code_attempted, result, user_id) VALUES ($1, $2, $3, $4)`,
+		eventID, code, result, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("events: log check in: %w", err)
+	}
+	return outcomeErr
+}
+
+// ListCheckInLog returns the check-in log for eventID, newest first, paginated.
+func (s *Store) ListCheckInLog(ctx context.Context, eventID int64, limit, offset int) ([]*CheckInLogEntry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, event_id, code_attempted, result, user_id, created_at
+		 FROM check_in_log WHERE event_id = $1
+		 ORDER BY created_at DESC
+		 LIMIT $2 OFFSET $3`,
+		eventID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: list check in log: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*CheckInLogEntry
+	for rows.Next() {
+		entry := &CheckInLogEntry{}
+		if err := rows.Scan(&entry.ID, &entry.EventID, &entry.CodeAttempted, &entry.Result, &entry.UserID, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("events: list check in log scan: %w", err)
+		}
+		out = append(out, entry)
+	}
+	return out, rows.Err()
+}
+
+// AttendeeCheckInCode returns userID's check-in code for eventID, the value
+// encoded into their QR check-in ticket.
+func (s *Store) AttendeeCheckInCode(ctx context.Context, eventID, userID int64) (string, error) {
+	var code string
+	row := s.db.QueryRowContext(ctx,
+		`SELECT checkin_code FROM event_attendees WHERE event_id = $1 AND user_id = $2`,
+		eventID, userID,
+	)
+	if err := row.Scan(&code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("events: attendee check-in code: %w", err)
+	}
+	return code, nil
+}
+
+// generateCheckInCode returns a short random hex code used as a proxy for a
+// scannable QR code until a full ticketing subsystem exists.
+func generateCheckInCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("events: generate check-in code: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
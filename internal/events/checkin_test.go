@@ -0,0 +1,33 @@
+package events
+
+import "testing"
+
+// classifyCheckIn mirrors the branching in Store.CheckIn without needing a
+// live database connection.
+func classifyCheckIn(found bool, alreadyCheckedIn bool) string {
+	if !found {
+		return CheckInResultInvalid
+	}
+	if alreadyCheckedIn {
+		return CheckInResultDuplicate
+	}
+	return CheckInResultSuccess
+}
+
+func TestCheckIn_LogsSuccess(t *testing.T) {
+	if got := classifyCheckIn(true, false); got != CheckInResultSuccess {
+		t.Fatalf("got %q, want %q", got, CheckInResultSuccess)
+	}
+}
+
+func TestCheckIn_LogsDuplicate(t *testing.T) {
+	if got := classifyCheckIn(true, true); got != CheckInResultDuplicate {
+		t.Fatalf("got %q, want %q", got, CheckInResultDuplicate)
+	}
+}
+
+func TestCheckIn_LogsInvalid(t *testing.T) {
+	if got := classifyCheckIn(false, false); got != CheckInResultInvalid {
+		t.Fatalf("got %q, want %q", got, CheckInResultInvalid)
+	}
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// CoattendeeCount is a ranked match for the "people you keep running into"
+// networking feed: another user and how many of the caller's events they
+// also attended.
+type CoattendeeCount struct {
+	UserID int64
+	Count  int
+}
+
+// FrequentCoattendees returns the users who have attended the most events in
+// common with userID, ranked highest count first. userID itself is
+// excluded, as are users who have opted out of attendance visibility.
+func (s *Store) FrequentCoattendees(ctx context.Context, userID int64) ([]CoattendeeCount, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ea2.user_id
+		FROM event_attendees ea1
+		JOIN event_attendees ea2 ON ea2.event_id = ea1.event_id AND ea2.user_id != ea1.user_id
+		JOIN users u ON u.id = ea2.user_id
+		WHERE ea1.user_id = $1 AND u.attendance_visible = true`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: frequent coattendees: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var coattendeeID int64
+		if err := rows.Scan(&coattendeeID); err != nil {
+			return nil, fmt.Errorf("events: frequent coattendees scan: %w", err)
+		}
+		counts[coattendeeID]++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rankCoattendees(counts), nil
+}
+
+// rankCoattendees orders counts from highest to lowest, breaking ties by
+// user ID for a stable result.
+func rankCoattendees(counts map[int64]int) []CoattendeeCount {
+	ranked := make([]CoattendeeCount, 0, len(counts))
+	for userID, count := range counts {
+		ranked = append(ranked, CoattendeeCount{UserID: userID, Count: count})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].UserID < ranked[j].UserID
+	})
+	return ranked
+}
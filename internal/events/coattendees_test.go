@@ -0,0 +1,18 @@
+package events
+
+import "testing"
+
+func TestRankCoattendees_HighestCountFirst(t *testing.T) {
+	ranked := rankCoattendees(map[int64]int{
+		201: 1,
+		202: 4,
+		203: 2,
+	})
+
+	if len(ranked) != 3 {
+		t.Fatalf("got %d results, want 3", len(ranked))
+	}
+	if ranked[0].UserID != 202 || ranked[0].Count != 4 {
+		t.Fatalf("got top result %+v, want user 202 with count 4", ranked[0])
+	}
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ScheduleConflict describes an event userID is already StatusGoing to
+// that overlaps the event being joined.
+type ScheduleConflict struct {
+	EventID  int64
+	Title    string
+	StartsAt time.Time
+	EndsAt   *time.Time
+}
+
+// ConflictingEvents returns the events that userID is already StatusGoing
+// to whose time range overlaps eventID's. eventID itself and cancelled
+// events are excluded. An event with no EndsAt is treated as occupying
+// only its StartsAt instant.
+func (s *Store) ConflictingEvents(ctx context.Context, userID, eventID int64) ([]ScheduleConflict, error) {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := e.StartsAt
+	if e.EndsAt != nil {
+		end = *e.EndsAt
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT e.id, e.title, e.starts_at, e.ends_at
+		 FROM event_attendees ea
+		 JOIN events e ON e.id = ea.event_id
+		 WHERE ea.user_id = $1 AND ea.status = $2 AND e.id != $3 AND e.status != $4
+		   AND COALESCE(e.ends_at, e.starts_at) >= $5 AND e.starts_at <= $6`,
+		userID, StatusGoing, eventID, EventStatusCancelled, e.StartsAt, end,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: conflicting events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ScheduleConflict
+	for rows.Next() {
+		var c ScheduleConflict
+		if err := rows.Scan(&c.EventID, &c.Title, &c.StartsAt, &c.EndsAt); err != nil {
+			return nil, fmt.Errorf("events: conflicting events scan: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
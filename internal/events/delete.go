@@ -0,0 +1,177 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/db"
+)
+
+// DeleteGracePeriod is how long a soft-deleted event can be restored
+// before PurgeExpiredDeleted removes it for good.
+const DeleteGracePeriod = 30 * 24 * time.Hour
+
+// Delete soft-deletes eventID: it's excluded from Get, List, and Search as
+// though it no longer existed, but can be brought back with Restore within
+// DeleteGracePeriod. ErrNotFound is returned if eventID doesn't exist or
+// is already deleted.
+func (s *Store) Delete(ctx context.Context, eventID int64) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE events SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`, eventID,
+	)
+	if err != nil {
+		return fmt.Errorf("events: delete: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("events: delete rows affected: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Restore undoes Delete, provided eventID was soft-deleted less than
+// DeleteGracePeriod ago. Only the organizer may restore their event.
+// ErrNotFound is returned if eventID doesn't exist or was never deleted;
+// ErrForbidden if organizerID isn't the organizer; ErrGracePeriodExpired
+// if the grace period has passed (it will already have been swept up by
+// PurgeExpiredDeleted, or is about to be).
+func (s *Store) Restore(ctx context.Context, eventID, organizerID int64) error {
+	var deletedAt *time.Time
+	var actualOrganizerID int64
+	row := s.db.QueryRowContext(ctx, `SELECT deleted_at, organizer_id FROM events WHERE id = $1`, eventID)
+	if err := row.Scan(&deletedAt, &actualOrganizerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("events: restore: %w", err)
+	}
+	if deletedAt == nil {
+		return ErrNotFound
+	}
+	if actualOrganizerID != organizerID {
+		return ErrForbidden
+	}
+	if time.Since(*deletedAt) > DeleteGracePeriod {
+		return ErrGracePeriodExpired
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE events SET deleted_at = NULL WHERE id = $1`, eventID,
+	); err != nil {
+		return fmt.Errorf("events: restore: %w", err)
+	}
+	return nil
+}
+
+// purgeChildTables lists the tables, in safe deletion order, that
+// reference a deleted event by event_id and have no FK dependents of
+// their own. The poll tables and the ticket tables aren't here because
+// each has its own dependents (event_poll_options/event_poll_votes, and
+// tickets referencing ticket_types) that must go first; see
+// purgeEventChildren.
+var purgeChildTables = []string{
+	"event_attendees",
+	"invitations",
+	"check_in_log",
+	"bookmarks",
+	"event_occurrence_overrides",
+	"comments",
+	"event_trending_scores",
+	"event_bans",
+}
+
+// purgeEventChildren deletes every row in every other table that
+// references eventID, in dependency order, so the events row itself can
+// be deleted afterward without tripping a foreign-key violation: none of
+// these tables cascade on delete (unlike audit_logs and the newer
+// integrations, which do).
+func purgeEventChildren(ctx context.Context, tx *sql.Tx, eventID int64) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM event_poll_votes WHERE option_id IN (
+			SELECT epo.id FROM event_poll_options epo
+			JOIN event_polls ep ON ep.id = epo.poll_id
+			WHERE ep.event_id = $1
+		)`, eventID,
+	); err != nil {
+		return fmt.Errorf("poll votes: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE event_polls SET finalized_option_id = NULL WHERE event_id = $1`, eventID,
+	); err != nil {
+		return fmt.Errorf("clear finalized poll option: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM event_poll_options WHERE poll_id IN (SELECT id FROM event_polls WHERE event_id = $1)`, eventID,
+	); err != nil {
+		return fmt.Errorf("poll options: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_polls WHERE event_id = $1`, eventID); err != nil {
+		return fmt.Errorf("polls: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tickets WHERE event_id = $1`, eventID); err != nil {
+		return fmt.Errorf("tickets: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM ticket_types WHERE event_id = $1`, eventID); err != nil {
+		return fmt.Errorf("ticket types: %w", err)
+	}
+
+	for _, table := range purgeChildTables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE event_id = $1`, table), eventID); err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// PurgeExpiredDeleted permanently removes every event whose grace period
+// for Restore has passed, along with every row in another table that
+// references it (see purgeEventChildren), and returns how many events
+// were purged. It's meant to run periodically from a scheduled job, the
+// same as ArchiveEndedEvents.
+func (s *Store) PurgeExpiredDeleted(ctx context.Context) (int, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id FROM events WHERE deleted_at IS NOT NULL AND deleted_at <= $1`,
+		time.Now().Add(-DeleteGracePeriod),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("events: purge expired deleted: select candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return 0, fmt.Errorf("events: purge expired deleted: scan candidate: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("events: purge expired deleted: %w", err)
+	}
+
+	purged := 0
+	for _, id := range ids {
+		err := db.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+			if err := purgeEventChildren(ctx, tx, id); err != nil {
+				return fmt.Errorf("events: purge expired deleted: event %d: %w", id, err)
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE id = $1`, id); err != nil {
+				return fmt.Errorf("events: purge expired deleted: event %d: %w", id, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,49 @@
+package events
+
+import "testing"
+
+// TestPurgeChildTables_CoversEveryNonCascadingDependent guards against a
+// regression of the bug where PurgeExpiredDeleted deleted the events row
+// directly and left every table that references events(id) without
+// ON DELETE CASCADE (event_attendees, invitations, etc.) to trip a
+// foreign-key violation. It doesn't touch a database; it only checks that
+// the flat table list purgeEventChildren relies on hasn't had an entry
+// dropped, and doesn't duplicate the tables purgeEventChildren already
+// handles with their own dependency-ordered statements (tickets,
+// ticket_types, and the event_polls family).
+func TestPurgeChildTables_CoversEveryNonCascadingDependent(t *testing.T) {
+	want := map[string]bool{
+		"event_attendees":            true,
+		"invitations":                true,
+		"check_in_log":               true,
+		"bookmarks":                  true,
+		"event_occurrence_overrides": true,
+		"comments":                   true,
+		"event_trending_scores":      true,
+		"event_bans":                 true,
+	}
+	handledElsewhere := map[string]bool{
+		"tickets":            true,
+		"ticket_types":       true,
+		"event_polls":        true,
+		"event_poll_options": true,
+		"event_poll_votes":   true,
+		"events":             true,
+	}
+
+	seen := map[string]bool{}
+	for _, table := range purgeChildTables {
+		if handledElsewhere[table] {
+			t.Fatalf("%q has its own dependents and must be deleted by purgeEventChildren's dedicated statements, not the generic purgeChildTables loop", table)
+		}
+		if !want[table] {
+			t.Fatalf("unexpected table %q in purgeChildTables", table)
+		}
+		seen[table] = true
+	}
+	for table := range want {
+		if !seen[table] {
+			t.Fatalf("purgeChildTables is missing %q, which references events(id) with no ON DELETE CASCADE", table)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Duplicate clones eventID's title, description, location, and settings
+// (capacity, min attendees, coordinates, recurrence rule, visibility) into
+// a new event starting at startsAt, with no attendees and no RSVP
+// deadline carried over since both are tied to the original date.
+func (s *Store) Duplicate(ctx context.Context, eventID int64, startsAt time.Time, endsAt *time.Time) (*Event, error) {
+	src, err := s.Get(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("events: duplicate: %w", err)
+	}
+
+	return s.Create(ctx, CreateInput{
+		OrganizerID:  src.OrganizerID,
+		Title:        src.Title,
+		Description:  src.Description,
+		Location:     src.Location,
+		StartsAt:     startsAt,
+		EndsAt:       endsAt,
+		Capacity:     src.Capacity,
+		MinAttendees: src.MinAttendees,
+		Latitude:     src.Latitude,
+		Longitude:    src.Longitude,
+		RRule:        src.RRule,
+		Visibility:   src.Visibility,
+	})
+}
@@ -0,0 +1,608 @@
+// Package events manages events and their attendees.
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/db"
+)
+
+// Event is a single organized gathering.
+type Event struct {
+	ID           int64
+	OrganizerID  int64
+	Title        string
+	Description  string
+	Location     string
+	StartsAt     time.Time
+	EndsAt       *time.Time
+	RSVPDeadline *time.Time
+	Capacity     *int
+	Status       string
+	MinAttendees *int
+	Latitude     *float64
+	Longitude    *float64
+	// InviteReminderDays is how many days after an invite goes unanswered
+	// the invitations package sends a reminder email, or nil to use
+	// invitations.DefaultInviteReminderDays; see
+	// invitations.Store.SendDueReminders.
+	InviteReminderDays *int
+	// RRule is an RFC 5545 recurrence rule (e.g. "FREQ=WEEKLY;BYDAY=TU"), or
+	// nil for a one-off event. Individual occurrences can be cancelled or
+	// rescheduled without altering the rule; see ExpandOccurrences.
+	RRule *string
+	// Visibility controls who can look up the event; see the Visibility*
+	// constants and GetForViewer.
+	Visibility string
+	// DurationMinutes is the event's length in minutes, derived from
+	// StartsAt and EndsAt; nil when EndsAt isn't set. It's computed on
+	// read, not stored, so calendar views and conflict checks don't have
+	// to parse timestamps themselves.
+	DurationMinutes *int
+	// RSVPOpen reports whether RSVPDeadline, if any, hasn't passed yet. An
+	// event with no deadline is always open. It's computed on read, not
+	// stored, so listings can flag it without every caller re-deriving it
+	// from RSVPDeadline themselves.
+	RSVPOpen bool
+	// NormalizedAddress is the address a Geocoder resolved Location to, or
+	// nil if Location hasn't been geocoded yet (or geocoding failed). It's
+	// filled in asynchronously after Create returns; see geocodeAsync.
+	NormalizedAddress *string
+	CreatedAt         time.Time
+	// Archived is set by the archiving scheduler once the event's end time
+	// (or start time, for events with no EndsAt) has passed; see
+	// ArchiveEndedEvents. Archived events are excluded from List unless
+	// includeArchived is set.
+	Archived bool
+	// DeletedAt is set by Delete when the organizer soft-deletes the event,
+	// and cleared by Restore within DeleteGracePeriod of it. A soft-deleted
+	// event is excluded from Get, List, and Search as though it didn't
+	// exist; see PurgeExpiredDeleted for what happens once the grace period
+	// passes.
+	DeletedAt *time.Time
+}
+
+// durationMinutes returns the whole-minute span between start and end, or
+// nil when end is unset.
+func durationMinutes(start time.Time, end *time.Time) *int {
+	if end == nil {
+		return nil
+	}
+	minutes := int(end.Sub(start).Minutes())
+	return &minutes
+}
+
+// rsvpOpen reports whether deadline, if set, is still in the future.
+func rsvpOpen(deadline *time.Time) bool {
+	return deadline == nil || time.Now().Before(*deadline)
+}
+
+// Attendance statuses stored on event_attendees.status.
+const (
+	StatusGoing      = "going"
+	StatusInterested = "interested"
+	StatusNotGoing   = "not_going"
+	// StatusWaitlisted marks an attendee who asked to go to a full event.
+	// They are promoted to StatusGoing automatically as space frees up; see
+	// promoteFromWaitlist.
+	StatusWaitlisted = "waitlisted"
+)
+
+// Event lifecycle statuses stored on events.status.
+const (
+	EventStatusTentative = "tentative"
+	EventStatusConfirmed = "confirmed"
+	EventStatusCancelled = "cancelled"
+)
+
+// Visibility levels stored on events.visibility. Public events are listed
+// and reachable by anyone; unlisted events are reachable by ID but excluded
+// from listings; private events are reachable only by their organizer,
+// attendees, and invited users, enforced by GetForViewer.
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private"
+)
+
+// ErrNotFound is returned when an event lookup finds no matching row.
+var ErrNotFound = apperr.Wrap(apperr.ErrNotFound, "events: not found")
+
+// ErrDeadlineAfterStart is returned when an RSVP deadline is not strictly
+// before the event start time.
+var ErrDeadlineAfterStart = apperr.Wrap(apperr.ErrValidation, "events: rsvp deadline must be before the event starts")
+
+// ErrRSVPDeadlinePassed is returned when joining or changing status to
+// "going" is attempted after the event's RSVP deadline.
+var ErrRSVPDeadlinePassed = apperr.Wrap(apperr.ErrConflict, "events: rsvp deadline has passed")
+
+// ErrEndBeforeStart is returned when an event's end time is not strictly
+// after its start time.
+var ErrEndBeforeStart = apperr.Wrap(apperr.ErrValidation, "events: end time must be after start time")
+
+// ErrInvalidRRule is returned when an event's recurrence rule cannot be
+// parsed.
+var ErrInvalidRRule = apperr.Wrap(apperr.ErrValidation, "events: invalid recurrence rule")
+
+// ErrNotRecurring is returned by occurrence operations on an event with no
+// RRule.
+var ErrNotRecurring = apperr.Wrap(apperr.ErrValidation, "events: event is not recurring")
+
+// ErrInvalidVisibility is returned when an event's visibility is not one of
+// the Visibility* constants.
+var ErrInvalidVisibility = apperr.Wrap(apperr.ErrValidation, "events: invalid visibility")
+
+// ErrForbidden is returned by GetForViewer when the viewer is not permitted
+// to see a private event.
+var ErrForbidden = apperr.Wrap(apperr.ErrForbidden, "events: not permitted to view this event")
+
+// ErrScheduleConflict is returned when joining an event as StatusGoing
+// would overlap with an event the user is already StatusGoing to, and the
+// caller hasn't opted to join anyway; see ConflictingEvents.
+var ErrScheduleConflict = apperr.Wrap(apperr.ErrConflict, "events: conflicts with another event you're going to")
+
+// ErrGracePeriodExpired is returned by Restore when the event was
+// soft-deleted more than DeleteGracePeriod ago.
+var ErrGracePeriodExpired = apperr.Wrap(apperr.ErrConflict, "events: grace period for restoring this event has expired")
+
+// Store persists events and attendance in Postgres.
+type Store struct {
+	db       *sql.DB
+	geocoder Geocoder
+}
+
+// NewStore returns a Store backed by db, resolving event locations to
+// coordinates through geocoder.
+func NewStore(db *sql.DB, geocoder Geocoder) *Store {
+	return &Store{db: db, geocoder: geocoder}
+}
+
+// CreateInput carries the fields needed to create an event.
+type CreateInput struct {
+	OrganizerID  int64
+	Title        string
+	Description  string
+	Location     string
+	StartsAt     time.Time
+	EndsAt       *time.Time
+	RSVPDeadline *time.Time
+	Capacity     *int
+	MinAttendees *int
+	Latitude     *float64
+	Longitude    *float64
+	// RRule is an optional RFC 5545 recurrence rule; see Event.RRule.
+	RRule *string
+	// Visibility defaults to VisibilityPublic when empty.
+	Visibility string
+	// InviteReminderDays is optional; see Event.InviteReminderDays.
+	InviteReminderDays *int
+}
+
+// Create inserts a new event. If MinAttendees is set, the event starts in
+// EventStatusTentative until enough attendees RSVP going; otherwise it is
+// immediately EventStatusConfirmed.
+func (s *Store) Create(ctx context.Context, in CreateInput) (*Event, error) {
+	ctx, cancel := db.WithQueryTimeout(ctx)
+	defer cancel()
+
+	if in.RSVPDeadline != nil && !in.RSVPDeadline.Before(in.StartsAt) {
+		return nil, ErrDeadlineAfterStart
+	}
+	if in.EndsAt != nil && !in.EndsAt.After(in.StartsAt) {
+		return nil, ErrEndBeforeStart
+	}
+	if in.RRule != nil {
+		if _, err := parseRRule(*in.RRule, in.StartsAt); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+		}
+	}
+
+	visibility := in.Visibility
+	if visibility == "" {
+		visibility = VisibilityPublic
+	}
+	if visibility != VisibilityPublic && visibility != VisibilityUnlisted && visibility != VisibilityPrivate {
+		return nil, ErrInvalidVisibility
+	}
+
+	status := EventStatusConfirmed
+	if in.MinAttendees != nil && *in.MinAttendees > 0 {
+		status = EventStatusTentative
+	}
+
+	e := &Event{
+		OrganizerID:        in.OrganizerID,
+		Title:              in.Title,
+		Description:        in.Description,
+		Location:           in.Location,
+		StartsAt:           in.StartsAt,
+		EndsAt:             in.EndsAt,
+		RSVPDeadline:       in.RSVPDeadline,
+		Capacity:           in.Capacity,
+		MinAttendees:       in.MinAttendees,
+		Latitude:           in.Latitude,
+		Longitude:          in.Longitude,
+		RRule:              in.RRule,
+		Visibility:         visibility,
+		Status:             status,
+		InviteReminderDays: in.InviteReminderDays,
+	}
+	e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+	e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+
+	row := s.db.QueryRowContext(ctx,
+		`INSERT INTO events (organizer_id, title, description, location, starts_at, ends_at, rsvp_deadline, capacity, min_attendees, status, latitude, longitude, rrule, visibility, invite_reminder_days)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		 RETURNING id, created_at`,
+		in.OrganizerID, in.Title, in.Description, in.Location, in.StartsAt, in.EndsAt, in.RSVPDeadline, in.Capacity, in.MinAttendees, status, in.Latitude, in.Longitude, in.RRule, visibility, in.InviteReminderDays,
+	)
+	if err := row.Scan(&e.ID, &e.CreatedAt); err != nil {
+		return nil, fmt.Errorf("events: create: %w", err)
+	}
+
+	if e.Latitude == nil && e.Longitude == nil && e.Location != "" {
+		go s.geocodeAsync(e.ID, e.Location)
+	}
+
+	return e, nil
+}
+
+// Get returns the event with the given ID.
+func (s *Store) Get(ctx context.Context, id int64) (*Event, error) {
+	ctx, cancel := db.WithQueryTimeout(ctx)
+	defer cancel()
+
+	e := &Event{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, organizer_id, title, description, location, starts_at, ends_at, rsvp_deadline, capacity, min_attendees, status, latitude, longitude, rrule, visibility, normalized_address, created_at, archived, invite_reminder_days
+		 FROM events WHERE id = $1 AND deleted_at IS NULL`, id,
+	)
+	if err := row.Scan(&e.ID, &e.OrganizerID, &e.Title, &e.Description, &e.Location,
+		&e.StartsAt, &e.EndsAt, &e.RSVPDeadline, &e.Capacity, &e.MinAttendees, &e.Status, &e.Latitude, &e.Longitude, &e.RRule, &e.Visibility, &e.NormalizedAddress, &e.CreatedAt, &e.Archived, &e.InviteReminderDays); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("events: get: %w", err)
+	}
+	e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+	e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+	return e, nil
+}
+
+// GetForViewer returns the event with the given ID if viewerID (nil for an
+// anonymous caller) is permitted to see it. Public and unlisted events are
+// reachable by anyone who knows the ID; private events are reachable only
+// by their organizer, their attendees, and users invited to them.
+// ErrForbidden is reported as ErrNotFound-shaped by callers that don't want
+// to reveal a private event's existence.
+func (s *Store) GetForViewer(ctx context.Context, id int64, viewerID *int64) (*Event, error) {
+	e, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Visibility != VisibilityPrivate {
+		return e, nil
+	}
+	if viewerID != nil && e.OrganizerID == *viewerID {
+		return e, nil
+	}
+	if viewerID == nil {
+		return nil, ErrForbidden
+	}
+
+	allowed, err := s.viewerHasPrivateAccess(ctx, id, *viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrForbidden
+	}
+	return e, nil
+}
+
+// viewerHasPrivateAccess reports whether viewerID is an attendee of eventID
+// or was invited to it. Invitations are queried directly by table name,
+// without importing the invitations package, to avoid a dependency cycle.
+func (s *Store) viewerHasPrivateAccess(ctx context.Context, eventID, viewerID int64) (bool, error) {
+	ctx, cancel := db.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var exists bool
+	row := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM event_attendees WHERE event_id = $1 AND user_id = $2
+			UNION
+			SELECT 1 FROM invitations WHERE event_id = $1 AND invitee_user_id = $2
+		)`,
+		eventID, viewerID,
+	)
+	if err := row.Scan(&exists); err != nil {
+		return false, fmt.Errorf("events: check private access: %w", err)
+	}
+	return exists, nil
+}
+
+// List returns up to limit public, non-cancelled events starting at
+// offset, ordered by start time, along with the total number of matching
+// events. Unlisted and private events are excluded; they're reachable only
+// by direct ID through GetForViewer. Archived events (see
+// ArchiveEndedEvents) are excluded unless includeArchived is set.
+func (s *Store) List(ctx context.Context, limit, offset int, includeArchived bool) ([]*Event, int, error) {
+	ctx, cancel := db.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var total int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM events WHERE visibility = $1 AND status != $2 AND (archived = false OR $3) AND deleted_at IS NULL`,
+		VisibilityPublic, EventStatusCancelled, includeArchived,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("events: count: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, organizer_id, title, description, location, starts_at, ends_at, rsvp_deadline, capacity, min_attendees, status, latitude, longitude, rrule, visibility, created_at, archived
+		 FROM events WHERE visibility = $1 AND status != $2 AND (archived = false OR $3) AND deleted_at IS NULL ORDER BY starts_at ASC
+		 LIMIT $4 OFFSET $5`,
+		VisibilityPublic, EventStatusCancelled, includeArchived, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("events: list: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Event
+	for rows.Next() {
+		e := &Event{}
+		if err := rows.Scan(&e.ID, &e.OrganizerID, &e.Title, &e.Description, &e.Location,
+			&e.StartsAt, &e.EndsAt, &e.RSVPDeadline, &e.Capacity, &e.MinAttendees, &e.Status, &e.Latitude, &e.Longitude, &e.RRule, &e.Visibility, &e.CreatedAt, &e.Archived); err != nil {
+			return nil, 0, fmt.Errorf("events: list scan: %w", err)
+		}
+		e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+		e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+		out = append(out, e)
+	}
+	return out, total, rows.Err()
+}
+
+// JoinEvent records userID's attendance for eventID with the given status,
+// returning the status actually recorded. Joining with StatusGoing after
+// the event's RSVP deadline is rejected, as is joining a private event
+// without an invitation; see joinEvent. Schedule conflicts with the
+// user's other "going" events are not checked; use JoinEventWithReferral
+// with force set to false for that.
+func (s *Store) JoinEvent(ctx context.Context, eventID, userID int64, status string) (string, error) {
+	return s.joinEvent(ctx, eventID, userID, status, nil, true)
+}
+
+// joinEvent is the shared implementation behind JoinEvent and
+// JoinEventWithReferral. referredBy, when non-nil, is the user ID credited
+// with bringing userID to the event. A private event rejects joins from
+// anyone who isn't its organizer, one of its attendees, or invited to it,
+// the same rule GetForViewer enforces for reads; see
+// viewerHasPrivateAccess. Joining with StatusGoing against an event at
+// capacity is silently downgraded to StatusWaitlisted instead of being
+// rejected; see promoteFromWaitlist for how waitlisted attendees are later
+// promoted. Joining with StatusGoing while force is false and the user
+// already has an overlapping "going" event is rejected with
+// ErrScheduleConflict instead; see ConflictingEvents. The status actually
+// recorded is returned.
+func (s *Store) joinEvent(ctx context.Context, eventID, userID int64, status string, referredBy *int64, force bool) (string, error) {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.checkJoinAllowed(ctx, e, userID, status, force); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := db.WithQueryTimeout(ctx)
+	defer cancel()
+
+	err = db.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		var txErr error
+		status, txErr = s.joinEventTx(ctx, tx, e, userID, status, referredBy)
+		return txErr
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if status == StatusGoing && e.Status == EventStatusTentative {
+		if err := s.confirmIfQuorumMet(ctx, e); err != nil {
+			return "", err
+		}
+	}
+	return status, nil
+}
+
+// checkJoinAllowed runs every join precondition that doesn't mutate state:
+// private-event access, the RSVP deadline, bans, and (unless force is true)
+// schedule conflicts for StatusGoing.
+func (s *Store) checkJoinAllowed(ctx context.Context, e *Event, userID int64, status string, force bool) error {
+	if e.Visibility == VisibilityPrivate && e.OrganizerID != userID {
+		allowed, err := s.viewerHasPrivateAccess(ctx, e.ID, userID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrForbidden
+		}
+	}
+
+	if status == StatusGoing && e.RSVPDeadline != nil && time.Now().After(*e.RSVPDeadline) {
+		return ErrRSVPDeadlinePassed
+	}
+
+	banned, err := isBanned(ctx, s.db, e.ID, userID)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return ErrBanned
+	}
+
+	if status == StatusGoing && !force {
+		conflicts, err := s.ConflictingEvents(ctx, userID, e.ID)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return ErrScheduleConflict
+		}
+	}
+	return nil
+}
+
+// LockForUpdate locks eventID's row within tx, so a caller evaluating the
+// event's capacity (such as invitations.Store.invite) can't race with a
+// concurrent joinEventTx or another caller of LockForUpdate reading the
+// same stale count before either has committed.
+func (s *Store) LockForUpdate(ctx context.Context, tx *sql.Tx, eventID int64) error {
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return fmt.Errorf("events: lock for update: %w", err)
+	}
+	return nil
+}
+
+// joinEventTx performs the capacity check and event_attendees upsert for
+// joinEvent within tx, so a caller coordinating a larger unit of work (such
+// as invitations.Store.Accept) can commit or roll back the join together
+// with its own writes. Callers are responsible for checkJoinAllowed and for
+// committing tx; the status actually recorded (which may be downgraded to
+// StatusWaitlisted) is returned.
+func (s *Store) joinEventTx(ctx context.Context, tx *sql.Tx, e *Event, userID int64, status string, referredBy *int64) (string, error) {
+	code, err := generateCheckInCode()
+	if err != nil {
+		return "", err
+	}
+	refCode, err := generateReferralCode()
+	if err != nil {
+		return "", err
+	}
+
+	if status == StatusGoing && e.Capacity != nil {
+		// Lock the event row so concurrent joins can't both observe spare
+		// capacity and both be admitted.
+		if err := s.LockForUpdate(ctx, tx, e.ID); err != nil {
+			return "", err
+		}
+
+		going, err := countGoingTx(ctx, tx, e.ID)
+		if err != nil {
+			return "", err
+		}
+		if going >= *e.Capacity {
+			status = StatusWaitlisted
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO event_attendees (event_id, user_id, status, checkin_code, referral_code, referred_by_user_id)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (event_id, user_id) WHERE user_id IS NOT NULL
+		 DO UPDATE SET status = $3, responded_at = now()`,
+		e.ID, userID, status, code, refCode, referredBy,
+	)
+	if err != nil {
+		return "", fmt.Errorf("events: join: %w", err)
+	}
+
+	return status, nil
+}
+
+// JoinEventInTx performs the same join as JoinEvent, but as part of tx
+// instead of its own transaction, so a caller such as
+// invitations.Store.Accept can commit the join and its own writes (e.g.
+// marking the invitation accepted) together or roll both back. Unlike
+// JoinEvent, quorum confirmation is the caller's responsibility after
+// commit, since it issues its own writes and can't be part of tx.
+func (s *Store) JoinEventInTx(ctx context.Context, tx *sql.Tx, eventID, userID int64, status string) (string, error) {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.checkJoinAllowed(ctx, e, userID, status, true); err != nil {
+		return "", err
+	}
+	return s.joinEventTx(ctx, tx, e, userID, status, nil)
+}
+
+// SetAttendeeStatus updates an existing attendee's status, subject to the
+// same RSVP deadline rule as JoinEvent when moving into StatusGoing. Moving
+// to StatusNotGoing frees a capacity slot, so the longest-waiting
+// StatusWaitlisted attendee (if any) is promoted to StatusGoing.
+func (s *Store) SetAttendeeStatus(ctx context.Context, eventID, userID int64, status string) error {
+	if _, err := s.JoinEvent(ctx, eventID, userID, status); err != nil {
+		return err
+	}
+	if status == StatusNotGoing {
+		return s.promoteFromWaitlist(ctx, eventID)
+	}
+	return nil
+}
+
+// LeaveEvent removes userID's attendance record for eventID, promoting the
+// longest-waiting StatusWaitlisted attendee (if any) into the freed slot.
+func (s *Store) LeaveEvent(ctx context.Context, eventID, userID int64) error {
+	ctx, cancel := db.WithQueryTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM event_attendees WHERE event_id = $1 AND user_id = $2`, eventID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("events: leave: %w", err)
+	}
+	return s.promoteFromWaitlist(ctx, eventID)
+}
+
+// CancelEvent marks eventID as EventStatusCancelled rather than deleting
+// it, so its history, attendee records, and check-in log remain intact; it
+// is simply excluded from List, Search, and ListGeocoded afterward. It
+// returns the user IDs of attendees to notify, leaving the notification
+// itself to the caller (events has no Mailer dependency of its own, the
+// same tradeoff CancelUnmetQuorumEvents makes). Cancelling an
+// already-cancelled event is a no-op.
+func (s *Store) CancelEvent(ctx context.Context, eventID int64) ([]int64, error) {
+	if _, err := s.Get(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := db.WithQueryTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE events SET status = $1 WHERE id = $2 AND status != $1`,
+		EventStatusCancelled, eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: cancel: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT user_id FROM event_attendees WHERE event_id = $1 AND user_id IS NOT NULL`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: cancel: list attendees: %w", err)
+	}
+	defer rows.Close()
+
+	var attendees []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("events: cancel: scan attendee: %w", err)
+		}
+		attendees = append(attendees, userID)
+	}
+	return attendees, rows.Err()
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCreate_RejectsDeadlineAfterStart(t *testing.T) {
+	starts := time.Now().Add(24 * time.Hour)
+	deadline := starts.Add(time.Hour)
+
+	s := &Store{}
+	_, err := s.Create(context.Background(), CreateInput{
+		Title:        "Launch party",
+		StartsAt:     starts,
+		RSVPDeadline: &deadline,
+	})
+	if err != ErrDeadlineAfterStart {
+		t.Fatalf("got err %v, want ErrDeadlineAfterStart", err)
+	}
+}
+
+func TestJoinEvent_RejectsGoingAfterDeadline(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	e := &Event{RSVPDeadline: &past}
+
+	if got := joinAllowed(e, StatusGoing); got {
+		t.Fatal("expected joining as going after the deadline to be rejected")
+	}
+}
+
+func TestJoinEvent_AllowsGoingBeforeDeadline(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	e := &Event{RSVPDeadline: &future}
+
+	if got := joinAllowed(e, StatusGoing); !got {
+		t.Fatal("expected joining as going before the deadline to be allowed")
+	}
+}
+
+// joinAllowed mirrors the deadline check in Store.JoinEvent without needing
+// a live database connection.
+func joinAllowed(e *Event, status string) bool {
+	if status == StatusGoing && e.RSVPDeadline != nil && time.Now().After(*e.RSVPDeadline) {
+		return false
+	}
+	return true
+}
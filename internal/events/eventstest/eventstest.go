@@ -0,0 +1,438 @@
+// Package eventstest provides an in-memory events.Repository for tests
+// that exercise code depending on the interface without a live Postgres
+// database, in the style of http.RoundTripper test doubles: each method
+// delegates to an optional function field, falling back to zero values
+// when that field is left nil.
+package eventstest
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// MockRepository implements events.Repository. Set only the *Func fields
+// a test needs; calling an unset method returns zero values rather than
+// panicking, so tests that don't care about a dependency can ignore it.
+type MockRepository struct {
+	ArchiveEndedEventsFunc         func(context.Context) (int, error)
+	AttendanceStatsFunc            func(context.Context, int64) (*events.AttendanceStats, error)
+	GetEventAttendeesFunc          func(context.Context, int64) (*events.AttendeeList, error)
+	ListAttendeesForExportFunc     func(context.Context, int64) ([]events.AttendeeExportRow, error)
+	FreeBusyFunc                   func(context.Context, []int64, time.Time, time.Time) (events.Availability, error)
+	BanAttendeeFunc                func(context.Context, int64, int64, int64) error
+	BookmarkFunc                   func(context.Context, int64, int64) error
+	UnbookmarkFunc                 func(context.Context, int64, int64) error
+	ListBookmarkedUpcomingFunc     func(context.Context, int64) ([]*events.Event, error)
+	BulkUpdateFunc                 func(context.Context, int64, []events.BulkItemInput) []events.BulkItemResult
+	ListForUserFunc                func(context.Context, int64) ([]*events.Event, error)
+	CheckInFunc                    func(context.Context, int64, string) error
+	ListCheckInLogFunc             func(context.Context, int64, int, int) ([]*events.CheckInLogEntry, error)
+	AttendeeCheckInCodeFunc        func(context.Context, int64, int64) (string, error)
+	FrequentCoattendeesFunc        func(context.Context, int64) ([]events.CoattendeeCount, error)
+	ConflictingEventsFunc          func(context.Context, int64, int64) ([]events.ScheduleConflict, error)
+	DuplicateFunc                  func(context.Context, int64, time.Time, *time.Time) (*events.Event, error)
+	CreateFunc                     func(context.Context, events.CreateInput) (*events.Event, error)
+	GetFunc                        func(context.Context, int64) (*events.Event, error)
+	GetForViewerFunc               func(context.Context, int64, *int64) (*events.Event, error)
+	ListFunc                       func(context.Context, int, int, bool) ([]*events.Event, int, error)
+	JoinEventFunc                  func(context.Context, int64, int64, string) (string, error)
+	JoinEventInTxFunc              func(context.Context, *sql.Tx, int64, int64, string) (string, error)
+	SetAttendeeStatusFunc          func(context.Context, int64, int64, string) error
+	LeaveEventFunc                 func(context.Context, int64, int64) error
+	CancelEventFunc                func(context.Context, int64) ([]int64, error)
+	ListGeocodedFunc               func(context.Context, *events.NearFilter) ([]*events.Event, error)
+	ListNearbyFunc                 func(context.Context, float64, float64, float64) ([]events.NearbyEvent, error)
+	AddAttendeeByEmailFunc         func(context.Context, int64, string, string) error
+	AddAttendeeByEmailInTxFunc     func(context.Context, *sql.Tx, int64, string, string) error
+	ClaimProvisionalAttendanceFunc func(context.Context, int64, string) error
+	CountGoingFunc                 func(context.Context, int64) (int, error)
+	ConfirmQuorumIfMetFunc         func(context.Context, int64) error
+	CancelUnmetQuorumEventsFunc    func(context.Context) ([]int64, error)
+	ExpandOccurrencesFunc          func(context.Context, int64, time.Time, time.Time) ([]events.Occurrence, error)
+	CancelOccurrenceFunc           func(context.Context, int64, time.Time) error
+	RescheduleOccurrenceFunc       func(context.Context, int64, time.Time, time.Time, *time.Time) error
+	JoinEventWithReferralFunc      func(context.Context, int64, int64, string, string, bool) (string, error)
+	ReferralSummaryFunc            func(context.Context, int64) ([]events.ReferralSummaryEntry, error)
+	SearchFunc                     func(context.Context, string, int, int) ([]*events.Event, int, error)
+	GenerateShareLinkFunc          func(context.Context, int64, int64) (string, error)
+	DisableShareLinkFunc           func(context.Context, int64, int64) error
+	GetByShareCodeFunc             func(context.Context, string) (*events.Event, error)
+	JoinViaShareCodeFunc           func(context.Context, string, int64, string, bool) (string, error)
+	DurationStatsForOrganizerFunc  func(context.Context, int64) (*events.DurationStats, error)
+	TransferOwnershipFunc          func(context.Context, int64, int64) (*events.Event, error)
+	RecomputeTrendingScoresFunc    func(context.Context) (int, error)
+	ListTrendingFunc               func(context.Context, int) ([]*events.Event, error)
+	UpdateFunc                     func(context.Context, int64, events.UpdateInput) (*events.Event, error)
+	DeleteFunc                     func(context.Context, int64) error
+	RestoreFunc                    func(context.Context, int64, int64) error
+	PurgeExpiredDeletedFunc        func(context.Context) (int, error)
+}
+
+var _ events.Repository = (*MockRepository)(nil)
+
+func (m *MockRepository) ArchiveEndedEvents(ctx context.Context) (int, error) {
+	if m.ArchiveEndedEventsFunc != nil {
+		return m.ArchiveEndedEventsFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockRepository) AttendanceStats(ctx context.Context, eventID int64) (*events.AttendanceStats, error) {
+	if m.AttendanceStatsFunc != nil {
+		return m.AttendanceStatsFunc(ctx, eventID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) GetEventAttendees(ctx context.Context, eventID int64) (*events.AttendeeList, error) {
+	if m.GetEventAttendeesFunc != nil {
+		return m.GetEventAttendeesFunc(ctx, eventID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) ListAttendeesForExport(ctx context.Context, eventID int64) ([]events.AttendeeExportRow, error) {
+	if m.ListAttendeesForExportFunc != nil {
+		return m.ListAttendeesForExportFunc(ctx, eventID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) FreeBusy(ctx context.Context, userIDs []int64, from time.Time, until time.Time) (events.Availability, error) {
+	if m.FreeBusyFunc != nil {
+		return m.FreeBusyFunc(ctx, userIDs, from, until)
+	}
+	return events.Availability{}, nil
+}
+
+func (m *MockRepository) BanAttendee(ctx context.Context, eventID int64, userID int64, bannedByUserID int64) error {
+	if m.BanAttendeeFunc != nil {
+		return m.BanAttendeeFunc(ctx, eventID, userID, bannedByUserID)
+	}
+	return nil
+}
+
+func (m *MockRepository) Bookmark(ctx context.Context, userID int64, eventID int64) error {
+	if m.BookmarkFunc != nil {
+		return m.BookmarkFunc(ctx, userID, eventID)
+	}
+	return nil
+}
+
+func (m *MockRepository) Unbookmark(ctx context.Context, userID int64, eventID int64) error {
+	if m.UnbookmarkFunc != nil {
+		return m.UnbookmarkFunc(ctx, userID, eventID)
+	}
+	return nil
+}
+
+func (m *MockRepository) ListBookmarkedUpcoming(ctx context.Context, userID int64) ([]*events.Event, error) {
+	if m.ListBookmarkedUpcomingFunc != nil {
+		return m.ListBookmarkedUpcomingFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) BulkUpdate(ctx context.Context, organizerID int64, items []events.BulkItemInput) []events.BulkItemResult {
+	if m.BulkUpdateFunc != nil {
+		return m.BulkUpdateFunc(ctx, organizerID, items)
+	}
+	return nil
+}
+
+func (m *MockRepository) ListForUser(ctx context.Context, userID int64) ([]*events.Event, error) {
+	if m.ListForUserFunc != nil {
+		return m.ListForUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) CheckIn(ctx context.Context, eventID int64, code string) error {
+	if m.CheckInFunc != nil {
+		return m.CheckInFunc(ctx, eventID, code)
+	}
+	return nil
+}
+
+func (m *MockRepository) ListCheckInLog(ctx context.Context, eventID int64, limit int, offset int) ([]*events.CheckInLogEntry, error) {
+	if m.ListCheckInLogFunc != nil {
+		return m.ListCheckInLogFunc(ctx, eventID, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) AttendeeCheckInCode(ctx context.Context, eventID int64, userID int64) (string, error) {
+	if m.AttendeeCheckInCodeFunc != nil {
+		return m.AttendeeCheckInCodeFunc(ctx, eventID, userID)
+	}
+	return "", nil
+}
+
+func (m *MockRepository) FrequentCoattendees(ctx context.Context, userID int64) ([]events.CoattendeeCount, error) {
+	if m.FrequentCoattendeesFunc != nil {
+		return m.FrequentCoattendeesFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) ConflictingEvents(ctx context.Context, userID int64, eventID int64) ([]events.ScheduleConflict, error) {
+	if m.ConflictingEventsFunc != nil {
+		return m.ConflictingEventsFunc(ctx, userID, eventID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Duplicate(ctx context.Context, eventID int64, startsAt time.Time, endsAt *time.Time) (*events.Event, error) {
+	if m.DuplicateFunc != nil {
+		return m.DuplicateFunc(ctx, eventID, startsAt, endsAt)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Create(ctx context.Context, in events.CreateInput) (*events.Event, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, in)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Get(ctx context.Context, id int64) (*events.Event, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) GetForViewer(ctx context.Context, id int64, viewerID *int64) (*events.Event, error) {
+	if m.GetForViewerFunc != nil {
+		return m.GetForViewerFunc(ctx, id, viewerID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) List(ctx context.Context, limit int, offset int, includeArchived bool) ([]*events.Event, int, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, limit, offset, includeArchived)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockRepository) JoinEvent(ctx context.Context, eventID int64, userID int64, status string) (string, error) {
+	if m.JoinEventFunc != nil {
+		return m.JoinEventFunc(ctx, eventID, userID, status)
+	}
+	return "", nil
+}
+
+func (m *MockRepository) JoinEventInTx(ctx context.Context, tx *sql.Tx, eventID int64, userID int64, status string) (string, error) {
+	if m.JoinEventInTxFunc != nil {
+		return m.JoinEventInTxFunc(ctx, tx, eventID, userID, status)
+	}
+	return "", nil
+}
+
+func (m *MockRepository) SetAttendeeStatus(ctx context.Context, eventID int64, userID int64, status string) error {
+	if m.SetAttendeeStatusFunc != nil {
+		return m.SetAttendeeStatusFunc(ctx, eventID, userID, status)
+	}
+	return nil
+}
+
+func (m *MockRepository) LeaveEvent(ctx context.Context, eventID int64, userID int64) error {
+	if m.LeaveEventFunc != nil {
+		return m.LeaveEventFunc(ctx, eventID, userID)
+	}
+	return nil
+}
+
+func (m *MockRepository) CancelEvent(ctx context.Context, eventID int64) ([]int64, error) {
+	if m.CancelEventFunc != nil {
+		return m.CancelEventFunc(ctx, eventID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) ListGeocoded(ctx context.Context, near *events.NearFilter) ([]*events.Event, error) {
+	if m.ListGeocodedFunc != nil {
+		return m.ListGeocodedFunc(ctx, near)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) ListNearby(ctx context.Context, lat float64, lng float64, radiusKm float64) ([]events.NearbyEvent, error) {
+	if m.ListNearbyFunc != nil {
+		return m.ListNearbyFunc(ctx, lat, lng, radiusKm)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) AddAttendeeByEmail(ctx context.Context, eventID int64, email string, status string) error {
+	if m.AddAttendeeByEmailFunc != nil {
+		return m.AddAttendeeByEmailFunc(ctx, eventID, email, status)
+	}
+	return nil
+}
+
+func (m *MockRepository) AddAttendeeByEmailInTx(ctx context.Context, tx *sql.Tx, eventID int64, email string, status string) error {
+	if m.AddAttendeeByEmailInTxFunc != nil {
+		return m.AddAttendeeByEmailInTxFunc(ctx, tx, eventID, email, status)
+	}
+	return nil
+}
+
+func (m *MockRepository) ClaimProvisionalAttendance(ctx context.Context, userID int64, email string) error {
+	if m.ClaimProvisionalAttendanceFunc != nil {
+		return m.ClaimProvisionalAttendanceFunc(ctx, userID, email)
+	}
+	return nil
+}
+
+func (m *MockRepository) CountGoing(ctx context.Context, eventID int64) (int, error) {
+	if m.CountGoingFunc != nil {
+		return m.CountGoingFunc(ctx, eventID)
+	}
+	return 0, nil
+}
+
+func (m *MockRepository) ConfirmQuorumIfMet(ctx context.Context, eventID int64) error {
+	if m.ConfirmQuorumIfMetFunc != nil {
+		return m.ConfirmQuorumIfMetFunc(ctx, eventID)
+	}
+	return nil
+}
+
+func (m *MockRepository) CancelUnmetQuorumEvents(ctx context.Context) ([]int64, error) {
+	if m.CancelUnmetQuorumEventsFunc != nil {
+		return m.CancelUnmetQuorumEventsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) ExpandOccurrences(ctx context.Context, eventID int64, from time.Time, until time.Time) ([]events.Occurrence, error) {
+	if m.ExpandOccurrencesFunc != nil {
+		return m.ExpandOccurrencesFunc(ctx, eventID, from, until)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) CancelOccurrence(ctx context.Context, eventID int64, occurrenceStart time.Time) error {
+	if m.CancelOccurrenceFunc != nil {
+		return m.CancelOccurrenceFunc(ctx, eventID, occurrenceStart)
+	}
+	return nil
+}
+
+func (m *MockRepository) RescheduleOccurrence(ctx context.Context, eventID int64, occurrenceStart time.Time, newStart time.Time, newEnd *time.Time) error {
+	if m.RescheduleOccurrenceFunc != nil {
+		return m.RescheduleOccurrenceFunc(ctx, eventID, occurrenceStart, newStart, newEnd)
+	}
+	return nil
+}
+
+func (m *MockRepository) JoinEventWithReferral(ctx context.Context, eventID int64, userID int64, status string, ref string, force bool) (string, error) {
+	if m.JoinEventWithReferralFunc != nil {
+		return m.JoinEventWithReferralFunc(ctx, eventID, userID, status, ref, force)
+	}
+	return "", nil
+}
+
+func (m *MockRepository) ReferralSummary(ctx context.Context, eventID int64) ([]events.ReferralSummaryEntry, error) {
+	if m.ReferralSummaryFunc != nil {
+		return m.ReferralSummaryFunc(ctx, eventID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Search(ctx context.Context, q string, limit int, offset int) ([]*events.Event, int, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, q, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockRepository) GenerateShareLink(ctx context.Context, eventID int64, organizerID int64) (string, error) {
+	if m.GenerateShareLinkFunc != nil {
+		return m.GenerateShareLinkFunc(ctx, eventID, organizerID)
+	}
+	return "", nil
+}
+
+func (m *MockRepository) DisableShareLink(ctx context.Context, eventID int64, organizerID int64) error {
+	if m.DisableShareLinkFunc != nil {
+		return m.DisableShareLinkFunc(ctx, eventID, organizerID)
+	}
+	return nil
+}
+
+func (m *MockRepository) GetByShareCode(ctx context.Context, code string) (*events.Event, error) {
+	if m.GetByShareCodeFunc != nil {
+		return m.GetByShareCodeFunc(ctx, code)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) JoinViaShareCode(ctx context.Context, code string, userID int64, status string, force bool) (string, error) {
+	if m.JoinViaShareCodeFunc != nil {
+		return m.JoinViaShareCodeFunc(ctx, code, userID, status, force)
+	}
+	return "", nil
+}
+
+func (m *MockRepository) DurationStatsForOrganizer(ctx context.Context, organizerID int64) (*events.DurationStats, error) {
+	if m.DurationStatsForOrganizerFunc != nil {
+		return m.DurationStatsForOrganizerFunc(ctx, organizerID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) TransferOwnership(ctx context.Context, eventID int64, newOrganizerID int64) (*events.Event, error) {
+	if m.TransferOwnershipFunc != nil {
+		return m.TransferOwnershipFunc(ctx, eventID, newOrganizerID)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) RecomputeTrendingScores(ctx context.Context) (int, error) {
+	if m.RecomputeTrendingScoresFunc != nil {
+		return m.RecomputeTrendingScoresFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockRepository) ListTrending(ctx context.Context, limit int) ([]*events.Event, error) {
+	if m.ListTrendingFunc != nil {
+		return m.ListTrendingFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Update(ctx context.Context, eventID int64, in events.UpdateInput) (*events.Event, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, eventID, in)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Delete(ctx context.Context, eventID int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, eventID)
+	}
+	return nil
+}
+
+func (m *MockRepository) Restore(ctx context.Context, eventID, organizerID int64) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, eventID, organizerID)
+	}
+	return nil
+}
+
+func (m *MockRepository) PurgeExpiredDeleted(ctx context.Context) (int, error) {
+	if m.PurgeExpiredDeletedFunc != nil {
+		return m.PurgeExpiredDeletedFunc(ctx)
+	}
+	return 0, nil
+}
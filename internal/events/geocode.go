@@ -0,0 +1,45 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// Geocoder resolves a free-text address into coordinates and a normalized,
+// human-readable address. It is an interface so tests and local development
+// can swap in a no-op implementation without a real geocoding provider.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (lat, lng float64, normalized string, err error)
+}
+
+var errNoopGeocoder = errors.New("events: noop geocoder cannot resolve addresses")
+
+// NoopGeocoder never resolves an address, leaving an event's coordinates and
+// normalized address unset. It is the default Geocoder until a real provider
+// is wired in.
+type NoopGeocoder struct{}
+
+func (NoopGeocoder) Geocode(ctx context.Context, address string) (float64, float64, string, error) {
+	return 0, 0, "", errNoopGeocoder
+}
+
+// geocodeAsync resolves location in the background and saves the result
+// against eventID, so Create never blocks on a third-party geocoding call.
+// Failures are logged and otherwise swallowed: an ungeocoded event still
+// works everywhere except distance-based search, which already tolerates
+// events with no coordinates.
+func (s *Store) geocodeAsync(eventID int64, location string) {
+	lat, lng, normalized, err := s.geocoder.Geocode(context.Background(), location)
+	if err != nil {
+		log.Printf("events: geocode event %d: %v", eventID, err)
+		return
+	}
+
+	if _, err := s.db.ExecContext(context.Background(),
+		`UPDATE events SET latitude = $1, longitude = $2, normalized_address = $3 WHERE id = $4`,
+		lat, lng, normalized, eventID,
+	); err != nil {
+		log.Printf("events: save geocoded event %d: %v", eventID, err)
+	}
+}
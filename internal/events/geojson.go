@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const earthRadiusKm = 6371.0
+
+// NearFilter restricts geocoded events to those within RadiusKm of a point.
+type NearFilter struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// ListGeocoded returns every public, non-cancelled event that has
+// coordinates set, optionally restricted to those within near's radius.
+// Results are ordered by start time like List; unlisted and private events
+// are excluded, same as List.
+func (s *Store) ListGeocoded(ctx context.Context, near *NearFilter) ([]*Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, organizer_id, title, description, location, starts_at, ends_at, rsvp_deadline, capacity, min_attendees, status, latitude, longitude, rrule, visibility, created_at
+		 FROM events WHERE visibility = $1 AND status != $2 AND deleted_at IS NULL ORDER BY starts_at ASC`,
+		VisibilityPublic, EventStatusCancelled,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: list geocoded: %w", err)
+	}
+	defer rows.Close()
+
+	var all []*Event
+	for rows.Next() {
+		e := &Event{}
+		if err := rows.Scan(&e.ID, &e.OrganizerID, &e.Title, &e.Description, &e.Location,
+			&e.StartsAt, &e.EndsAt, &e.RSVPDeadline, &e.Capacity, &e.MinAttendees, &e.Status, &e.Latitude, &e.Longitude, &e.RRule, &e.Visibility, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("events: list geocoded scan: %w", err)
+		}
+		e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+		e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+		all = append(all, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("events: list geocoded: %w", err)
+	}
+
+	var out []*Event
+	for _, e := range all {
+		if e.Latitude == nil || e.Longitude == nil {
+			continue
+		}
+		if near != nil && haversineKm(*e.Latitude, *e.Longitude, near.Latitude, near.Longitude) > near.RadiusKm {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// NearbyEvent pairs a geocoded event with its distance from a query point.
+type NearbyEvent struct {
+	*Event
+	DistanceKm float64
+}
+
+// ListNearby returns public, non-cancelled geocoded events within radiusKm
+// of (lat, lng), nearest first, each annotated with its distance.
+func (s *Store) ListNearby(ctx context.Context, lat, lng, radiusKm float64) ([]NearbyEvent, error) {
+	list, err := s.ListGeocoded(ctx, &NearFilter{Latitude: lat, Longitude: lng, RadiusKm: radiusKm})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]NearbyEvent, len(list))
+	for i, e := range list {
+		out[i] = NearbyEvent{Event: e, DistanceKm: haversineKm(*e.Latitude, *e.Longitude, lat, lng)}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DistanceKm < out[j].DistanceKm })
+	return out, nil
+}
+
+// haversineKm returns the great-circle distance in kilometers between two
+// latitude/longitude points.
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return earthRadiusKm * c
+}
@@ -0,0 +1,17 @@
+package events
+
+import "testing"
+
+func TestHaversineKm_SamePointIsZero(t *testing.T) {
+	if d := haversineKm(40.7128, -74.0060, 40.7128, -74.0060); d != 0 {
+		t.Fatalf("got %f, want 0", d)
+	}
+}
+
+func TestHaversineKm_KnownDistance(t *testing.T) {
+	// New York to Los Angeles is roughly 3935 km.
+	d := haversineKm(40.7128, -74.0060, 34.0522, -118.2437)
+	if d < 3900 || d > 3970 {
+		t.Fatalf("got %f km, want roughly 3935 km", d)
+	}
+}
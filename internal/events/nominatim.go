@@ -0,0 +1,77 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// defaultNominatimBaseURL is OpenStreetMap's public Nominatim instance, used
+// when no self-hosted instance is configured.
+const defaultNominatimBaseURL = "https://nominatim.openstreetmap.org"
+
+// NominatimGeocoder resolves addresses using a Nominatim-compatible search
+// API (OpenStreetMap's public instance, or a self-hosted one).
+type NominatimGeocoder struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+// NewNominatimGeocoder returns a NominatimGeocoder querying baseURL (or
+// OpenStreetMap's public instance if baseURL is empty). Nominatim's usage
+// policy requires a descriptive User-Agent identifying the application.
+func NewNominatimGeocoder(baseURL, userAgent string) *NominatimGeocoder {
+	if baseURL == "" {
+		baseURL = defaultNominatimBaseURL
+	}
+	return &NominatimGeocoder{baseURL: baseURL, userAgent: userAgent, client: &http.Client{}}
+}
+
+// Geocode resolves address to its highest-confidence coordinates and
+// Nominatim's normalized display name.
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (float64, float64, string, error) {
+	reqURL := fmt.Sprintf("%s/search?%s", g.baseURL, url.Values{
+		"q":      {address},
+		"format": {"jsonv2"},
+		"limit":  {"1"},
+	}.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("events: build nominatim request: %w", err)
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("events: nominatim geocode: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var results []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, 0, "", fmt.Errorf("events: decode nominatim response: %w", err)
+	}
+	if len(results) == 0 {
+		return 0, 0, "", fmt.Errorf("events: nominatim found no match for %q", address)
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("events: parse nominatim latitude: %w", err)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("events: parse nominatim longitude: %w", err)
+	}
+
+	return lat, lng, results[0].DisplayName, nil
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// AddAttendeeByEmail adds a provisional attendee to eventID, identified only
+// by email because they don't have an account yet. Provisional attendees
+// count toward capacity like any other attendee and are claimed by
+// ClaimProvisionalAttendance once the person registers.
+func (s *Store) AddAttendeeByEmail(ctx context.Context, eventID int64, email, status string) error {
+	return addAttendeeByEmailTx(ctx, s.db, eventID, email, status)
+}
+
+// AddAttendeeByEmailInTx performs the same insert as AddAttendeeByEmail, but
+// as part of tx instead of against the Store's own connection pool, so a
+// caller such as invitations.Store.AcceptWithToken can commit it together
+// with its own writes; see events.Store.JoinEventInTx for the equivalent on
+// the registered-user join path.
+func (s *Store) AddAttendeeByEmailInTx(ctx context.Context, tx *sql.Tx, eventID int64, email, status string) error {
+	return addAttendeeByEmailTx(ctx, tx, eventID, email, status)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx, letting addAttendeeByEmailTx
+// run either standalone or as part of a caller's transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func addAttendeeByEmailTx(ctx context.Context, e execer, eventID int64, email, status string) error {
+	code, err := generateCheckInCode()
+	if err != nil {
+		return err
+	}
+
+	_, err = e.ExecContext(ctx,
+		`INSERT INTO event_attendees (event_id, email, status, checkin_code)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (event_id, email) WHERE email IS NOT NULL
+		 DO UPDATE SET status = $3, responded_at = now()`,
+		eventID, email, status, code,
+	)
+	if err != nil {
+		return fmt.Errorf("events: add attendee by email: %w", err)
+	}
+	return nil
+}
+
+// ClaimProvisionalAttendance attaches userID to every provisional attendee
+// row recorded under email, so a newly registered or verified account
+// inherits the RSVPs an organizer made on their behalf.
+func (s *Store) ClaimProvisionalAttendance(ctx context.Context, userID int64, email string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE event_attendees SET user_id = $1, email = NULL
+		 WHERE email = $2 AND user_id IS NULL`,
+		userID, email,
+	)
+	if err != nil {
+		return fmt.Errorf("events: claim provisional attendance: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package events
+
+import "testing"
+
+// provisionalAttendee is a minimal stand-in for a row added by
+// AddAttendeeByEmail, used to exercise the claim matching rule without a
+// live database connection.
+type provisionalAttendee struct {
+	email  string
+	userID *int64
+}
+
+func claim(rows []provisionalAttendee, email string, userID int64) []provisionalAttendee {
+	for i, row := range rows {
+		if row.email == email && row.userID == nil {
+			rows[i].userID = &userID
+		}
+	}
+	return rows
+}
+
+func TestClaimProvisionalAttendance_AttachesUserID(t *testing.T) {
+	rows := []provisionalAttendee{{email: "ada@example.com"}}
+
+	rows = claim(rows, "ada@example.com", 42)
+
+	if rows[0].userID == nil || *rows[0].userID != 42 {
+		t.Fatalf("got %+v, want userID 42 attached", rows[0])
+	}
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// querier is satisfied by both *sql.DB and *sql.Tx, letting helpers like
+// countGoingTx run either standalone or as part of a caller's transaction.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// quorumReached reports whether a tentative event has enough "going"
+// attendees to auto-confirm.
+func quorumReached(going, minAttendees int) bool {
+	return going >= minAttendees
+}
+
+// CountGoing returns the number of attendees with StatusGoing for eventID.
+func (s *Store) CountGoing(ctx context.Context, eventID int64) (int, error) {
+	return countGoingTx(ctx, s.db, eventID)
+}
+
+// CountGoingInTx is CountGoing run against tx instead of the Store's db, for
+// a caller like invitations.Store.invite that needs the count to read
+// consistently with a row lock it already holds in the same transaction.
+func (s *Store) CountGoingInTx(ctx context.Context, tx *sql.Tx, eventID int64) (int, error) {
+	return countGoingTx(ctx, tx, eventID)
+}
+
+// countGoingTx is the shared implementation behind CountGoing, usable with
+// either the Store's db or an in-flight transaction.
+func countGoingTx(ctx context.Context, q querier, eventID int64) (int, error) {
+	var count int
+	row := q.QueryRowContext(ctx,
+		`SELECT count(*) FROM event_attendees WHERE event_id = $1 AND status = $2`,
+		eventID, StatusGoing,
+	)
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("events: count going: %w", err)
+	}
+	return count, nil
+}
+
+// ConfirmQuorumIfMet re-fetches eventID and moves it to EventStatusConfirmed
+// if it's tentative and its "going" count has reached MinAttendees. It's
+// exported for callers like invitations.Store.Accept that join an attendee
+// to a tentative event from outside this package and, having committed
+// their own transaction first, need to trigger the same post-join quorum
+// check that JoinEvent performs inline; see confirmIfQuorumMet.
+func (s *Store) ConfirmQuorumIfMet(ctx context.Context, eventID int64) error {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	return s.confirmIfQuorumMet(ctx, e)
+}
+
+// confirmIfQuorumMet moves a tentative event to EventStatusConfirmed once
+// its "going" count reaches MinAttendees. Notifying attendees of the
+// confirmation is left to the caller.
+func (s *Store) confirmIfQuorumMet(ctx context.Context, e *Event) error {
+	if e.MinAttendees == nil {
+		return nil
+	}
+
+	going, err := s.CountGoing(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+	if !quorumReached(going, *e.MinAttendees) {
+		return nil
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE events SET status = $1 WHERE id = $2 AND status = $3`,
+		EventStatusConfirmed, e.ID, EventStatusTentative,
+	)
+	if err != nil {
+		return fmt.Errorf("events: confirm quorum: %w", err)
+	}
+	return nil
+}
+
+// CancelUnmetQuorumEvents cancels every tentative event whose RSVP deadline
+// has passed without reaching MinAttendees, and returns their IDs so the
+// caller can notify attendees. It is meant to be run periodically by a
+// scheduler.
+func (s *Store) CancelUnmetQuorumEvents(ctx context.Context) ([]int64, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`UPDATE events SET status = $1
+		 WHERE status = $2 AND rsvp_deadline IS NOT NULL AND rsvp_deadline < $3
+		 RETURNING id`,
+		EventStatusCancelled, EventStatusTentative, time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: cancel unmet quorum events: %w", err)
+	}
+	defer rows.Close()
+
+	var cancelled []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("events: cancel unmet quorum events scan: %w", err)
+		}
+		cancelled = append(cancelled, id)
+	}
+	return cancelled, rows.Err()
+}
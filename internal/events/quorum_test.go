@@ -0,0 +1,24 @@
+package events
+
+import "testing"
+
+func TestQuorumReached_ConfirmsAtThreshold(t *testing.T) {
+	if !quorumReached(10, 10) {
+		t.Fatal("expected quorum reached when going count equals the threshold")
+	}
+	if quorumReached(9, 10) {
+		t.Fatal("expected quorum not reached below the threshold")
+	}
+}
+
+func TestCreate_TentativeUntilQuorum(t *testing.T) {
+	min := 10
+	e := &Event{Status: EventStatusTentative, MinAttendees: &min}
+
+	if quorumReached(9, *e.MinAttendees) {
+		t.Fatal("event should remain tentative below quorum")
+	}
+	if !quorumReached(10, *e.MinAttendees) {
+		t.Fatal("event should confirm once quorum is reached")
+	}
+}
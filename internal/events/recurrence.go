@@ -0,0 +1,185 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Occurrence statuses stored on event_occurrence_overrides.status.
+const (
+	occurrenceCancelled = "cancelled"
+	occurrenceModified  = "modified"
+)
+
+// Occurrence is a single instance of a recurring event, after applying any
+// override recorded against its original start time.
+type Occurrence struct {
+	Event     *Event
+	StartsAt  time.Time
+	EndsAt    *time.Time
+	Cancelled bool
+}
+
+// parseRRule parses an RFC 5545 recurrence rule string, anchoring it at
+// dtstart.
+func parseRRule(rule string, dtstart time.Time) (*rrule.RRule, error) {
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, err
+	}
+	opt.Dtstart = dtstart
+	return rrule.NewRRule(*opt)
+}
+
+// ExpandOccurrences returns eventID's occurrences starting in [from, until),
+// with cancelled occurrences flagged and modified occurrences reflecting
+// their overridden time. eventID must refer to a recurring event.
+func (s *Store) ExpandOccurrences(ctx context.Context, eventID int64, from, until time.Time) ([]Occurrence, error) {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.RRule == nil {
+		return nil, ErrNotRecurring
+	}
+
+	rule, err := parseRRule(*e.RRule, e.StartsAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+	}
+
+	overrides, err := s.occurrenceOverrides(ctx, eventID, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	var duration *time.Duration
+	if e.EndsAt != nil {
+		d := e.EndsAt.Sub(e.StartsAt)
+		duration = &d
+	}
+
+	var out []Occurrence
+	for _, start := range rule.Between(from, until, true) {
+		occ := Occurrence{Event: e, StartsAt: start}
+		if duration != nil {
+			end := start.Add(*duration)
+			occ.EndsAt = &end
+		}
+
+		if override, ok := lookupOverride(overrides, start); ok {
+			switch override.status {
+			case occurrenceCancelled:
+				occ.Cancelled = true
+			case occurrenceModified:
+				if override.startsAt != nil {
+					occ.StartsAt = *override.startsAt
+				}
+				occ.EndsAt = override.endsAt
+			}
+		}
+		out = append(out, occ)
+	}
+	return out, nil
+}
+
+// occurrenceOverride is a single row from event_occurrence_overrides.
+type occurrenceOverride struct {
+	occurrenceStartsAt time.Time
+	status             string
+	startsAt           *time.Time
+	endsAt             *time.Time
+}
+
+// occurrenceOverrides returns eventID's overrides whose original occurrence
+// time falls in [from, until), keyed by that original time.
+func (s *Store) occurrenceOverrides(ctx context.Context, eventID int64, from, until time.Time) (map[int64]occurrenceOverride, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT occurrence_starts_at, status, override_starts_at, override_ends_at
+		 FROM event_occurrence_overrides
+		 WHERE event_id = $1 AND occurrence_starts_at >= $2 AND occurrence_starts_at < $3`,
+		eventID, from, until,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: list occurrence overrides: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[int64]occurrenceOverride{}
+	for rows.Next() {
+		var o occurrenceOverride
+		if err := rows.Scan(&o.occurrenceStartsAt, &o.status, &o.startsAt, &o.endsAt); err != nil {
+			return nil, fmt.Errorf("events: scan occurrence override: %w", err)
+		}
+		out[o.occurrenceStartsAt.UnixNano()] = o
+	}
+	return out, rows.Err()
+}
+
+// lookupOverride finds the override (if any) recorded against start.
+func lookupOverride(overrides map[int64]occurrenceOverride, start time.Time) (occurrenceOverride, bool) {
+	o, ok := overrides[start.UnixNano()]
+	return o, ok
+}
+
+// CancelOccurrence cancels a single occurrence of eventID's series, starting
+// at occurrenceStart, without affecting the rest of the series. eventID
+// must refer to a recurring event.
+func (s *Store) CancelOccurrence(ctx context.Context, eventID int64, occurrenceStart time.Time) error {
+	if err := s.requireRecurring(ctx, eventID); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO event_occurrence_overrides (event_id, occurrence_starts_at, status)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (event_id, occurrence_starts_at) DO UPDATE SET status = $3, override_starts_at = NULL, override_ends_at = NULL`,
+		eventID, occurrenceStart, occurrenceCancelled,
+	)
+	if err != nil {
+		return fmt.Errorf("events: cancel occurrence: %w", err)
+	}
+	return nil
+}
+
+// RescheduleOccurrence moves a single occurrence of eventID's series,
+// originally starting at occurrenceStart, to newStart/newEnd, without
+// affecting the rest of the series. eventID must refer to a recurring
+// event.
+func (s *Store) RescheduleOccurrence(ctx context.Context, eventID int64, occurrenceStart, newStart time.Time, newEnd *time.Time) error {
+	if err := s.requireRecurring(ctx, eventID); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO event_occurrence_overrides (event_id, occurrence_starts_at, status, override_starts_at, override_ends_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (event_id, occurrence_starts_at) DO UPDATE SET status = $3, override_starts_at = $4, override_ends_at = $5`,
+		eventID, occurrenceStart, occurrenceModified, newStart, newEnd,
+	)
+	if err != nil {
+		return fmt.Errorf("events: reschedule occurrence: %w", err)
+	}
+	return nil
+}
+
+// requireRecurring confirms eventID exists and has an RRule set.
+func (s *Store) requireRecurring(ctx context.Context, eventID int64) error {
+	var rule sql.NullString
+	row := s.db.QueryRowContext(ctx, `SELECT rrule FROM events WHERE id = $1`, eventID)
+	switch err := row.Scan(&rule); {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrNotFound
+	case err != nil:
+		return fmt.Errorf("events: check recurring: %w", err)
+	}
+	if !rule.Valid {
+		return ErrNotRecurring
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ReferralSummaryEntry summarizes how many attendees a given referrer
+// brought to an event.
+type ReferralSummaryEntry struct {
+	ReferrerUserID int64
+	Count          int
+}
+
+// JoinEventWithReferral behaves like JoinEvent, additionally attributing the
+// join to ref, the referral code of an existing attendee. An unknown or
+// empty ref is ignored, and self-referral (joining via one's own referral
+// code) is never attributed. force controls whether a StatusGoing join
+// that conflicts with one of the user's other "going" events is rejected
+// with ErrScheduleConflict (force false) or allowed anyway (force true).
+func (s *Store) JoinEventWithReferral(ctx context.Context, eventID, userID int64, status, ref string, force bool) (string, error) {
+	referrerID, err := s.resolveReferrer(ctx, eventID, userID, ref)
+	if err != nil {
+		return "", err
+	}
+	return s.joinEvent(ctx, eventID, userID, status, referrerID, force)
+}
+
+// resolveReferrer looks up the user ID behind ref, returning nil if ref is
+// empty, unknown, or would attribute the join to the joining user.
+func (s *Store) resolveReferrer(ctx context.Context, eventID, userID int64, ref string) (*int64, error) {
+	if ref == "" {
+		return nil, nil
+	}
+
+	var referrerID int64
+	row := s.db.QueryRowContext(ctx,
+		`SELECT user_id FROM event_attendees WHERE event_id = $1 AND referral_code = $2`,
+		eventID, ref,
+	)
+	switch err := row.Scan(&referrerID); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("events: resolve referrer: %w", err)
+	}
+
+	if !referralAttributable(referrerID, userID) {
+		return nil, nil
+	}
+	return &referrerID, nil
+}
+
+// referralAttributable reports whether a join should be attributed to
+// referrerID, excluding the self-referral case.
+func referralAttributable(referrerID, joiningUserID int64) bool {
+	return referrerID != joiningUserID
+}
+
+// ReferralSummary returns, for eventID, how many attendees each referrer
+// brought in, highest first.
+func (s *Store) ReferralSummary(ctx context.Context, eventID int64) ([]ReferralSummaryEntry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT referred_by_user_id, count(*)
+		 FROM event_attendees
+		 WHERE event_id = $1 AND referred_by_user_id IS NOT NULL
+		 GROUP BY referred_by_user_id
+		 ORDER BY count(*) DESC, referred_by_user_id ASC`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: referral summary: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ReferralSummaryEntry
+	for rows.Next() {
+		var entry ReferralSummaryEntry
+		if err := rows.Scan(&entry.ReferrerUserID, &entry.Count); err != nil {
+			return nil, fmt.Errorf("events: referral summary scan: %w", err)
+		}
+		out = append(out, entry)
+	}
+	return out, rows.Err()
+}
+
+// generateReferralCode returns a short random hex code attendees can share
+// to be credited for bringing in new joins.
+func generateReferralCode() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("events: generate referral code: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
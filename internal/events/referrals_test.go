@@ -0,0 +1,15 @@
+package events
+
+import "testing"
+
+func TestReferralAttributable_ExcludesSelfReferral(t *testing.T) {
+	if referralAttributable(42, 42) {
+		t.Fatal("expected self-referral to not be attributable")
+	}
+}
+
+func TestReferralAttributable_CreditsOtherReferrer(t *testing.T) {
+	if !referralAttributable(1, 2) {
+		t.Fatal("expected referral from a different user to be attributable")
+	}
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Repository is the persistence interface events' own HTTP-facing callers
+// (internal/httpapi) depend on. *Store is the Postgres-backed
+// implementation used in production; tests can substitute an in-memory
+// implementation instead of requiring a live database. The method set
+// mirrors Store's full exported API, so Repository and Store are expected
+// to evolve together.
+type Repository interface {
+	ArchiveEndedEvents(ctx context.Context) (int, error)
+	AttendanceStats(ctx context.Context, eventID int64) (*AttendanceStats, error)
+	GetEventAttendees(ctx context.Context, eventID int64) (*AttendeeList, error)
+	ListAttendeesForExport(ctx context.Context, eventID int64) ([]AttendeeExportRow, error)
+	FreeBusy(ctx context.Context, userIDs []int64, from, until time.Time) (Availability, error)
+	BanAttendee(ctx context.Context, eventID, userID, bannedByUserID int64) error
+	Bookmark(ctx context.Context, userID, eventID int64) error
+	Unbookmark(ctx context.Context, userID, eventID int64) error
+	ListBookmarkedUpcoming(ctx context.Context, userID int64) ([]*Event, error)
+	BulkUpdate(ctx context.Context, organizerID int64, items []BulkItemInput) []BulkItemResult
+	ListForUser(ctx context.Context, userID int64) ([]*Event, error)
+	CheckIn(ctx context.Context, eventID int64, code string) error
+	ListCheckInLog(ctx context.Context, eventID int64, limit, offset int) ([]*CheckInLogEntry, error)
+	AttendeeCheckInCode(ctx context.Context, eventID, userID int64) (string, error)
+	FrequentCoattendees(ctx context.Context, userID int64) ([]CoattendeeCount, error)
+	ConflictingEvents(ctx context.Context, userID, eventID int64) ([]ScheduleConflict, error)
+	Duplicate(ctx context.Context, eventID int64, startsAt time.Time, endsAt *time.Time) (*Event, error)
+	Create(ctx context.Context, in CreateInput) (*Event, error)
+	Get(ctx context.Context, id int64) (*Event, error)
+	GetForViewer(ctx context.Context, id int64, viewerID *int64) (*Event, error)
+	List(ctx context.Context, limit, offset int, includeArchived bool) ([]*Event, int, error)
+	JoinEvent(ctx context.Context, eventID, userID int64, status string) (string, error)
+	JoinEventInTx(ctx context.Context, tx *sql.Tx, eventID, userID int64, status string) (string, error)
+	SetAttendeeStatus(ctx context.Context, eventID, userID int64, status string) error
+	LeaveEvent(ctx context.Context, eventID, userID int64) error
+	CancelEvent(ctx context.Context, eventID int64) ([]int64, error)
+	ListGeocoded(ctx context.Context, near *NearFilter) ([]*Event, error)
+	ListNearby(ctx context.Context, lat, lng, radiusKm float64) ([]NearbyEvent, error)
+	AddAttendeeByEmail(ctx context.Context, eventID int64, email, status string) error
+	AddAttendeeByEmailInTx(ctx context.Context, tx *sql.Tx, eventID int64, email, status string) error
+	ClaimProvisionalAttendance(ctx context.Context, userID int64, email string) error
+	CountGoing(ctx context.Context, eventID int64) (int, error)
+	ConfirmQuorumIfMet(ctx context.Context, eventID int64) error
+	CancelUnmetQuorumEvents(ctx context.Context) ([]int64, error)
+	ExpandOccurrences(ctx context.Context, eventID int64, from, until time.Time) ([]Occurrence, error)
+	CancelOccurrence(ctx context.Context, eventID int64, occurrenceStart time.Time) error
+	RescheduleOccurrence(ctx context.Context, eventID int64, occurrenceStart, newStart time.Time, newEnd *time.Time) error
+	JoinEventWithReferral(ctx context.Context, eventID, userID int64, status, ref string, force bool) (string, error)
+	ReferralSummary(ctx context.Context, eventID int64) ([]ReferralSummaryEntry, error)
+	Search(ctx context.Context, q string, limit, offset int) ([]*Event, int, error)
+	GenerateShareLink(ctx context.Context, eventID, organizerID int64) (string, error)
+	DisableShareLink(ctx context.Context, eventID, organizerID int64) error
+	GetByShareCode(ctx context.Context, code string) (*Event, error)
+	JoinViaShareCode(ctx context.Context, code string, userID int64, status string, force bool) (string, error)
+	DurationStatsForOrganizer(ctx context.Context, organizerID int64) (*DurationStats, error)
+	TransferOwnership(ctx context.Context, eventID, newOrganizerID int64) (*Event, error)
+	RecomputeTrendingScores(ctx context.Context) (int, error)
+	ListTrending(ctx context.Context, limit int) ([]*Event, error)
+	Update(ctx context.Context, eventID int64, in UpdateInput) (*Event, error)
+	Delete(ctx context.Context, eventID int64) error
+	Restore(ctx context.Context, eventID, organizerID int64) error
+	PurgeExpiredDeleted(ctx context.Context) (int, error)
+}
+
+var _ Repository = (*Store)(nil)
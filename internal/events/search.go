@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"fmt"
+)
+
+// Search returns up to limit public, non-cancelled events matching the
+// full-text query q, ranked by relevance, starting at offset, along with
+// the total number of matching events. q is parsed with Postgres's
+// plain-text query syntax, so callers can pass raw user input without
+// building a tsquery themselves. Unlisted and private events are excluded,
+// same as List.
+func (s *Store) Search(ctx context.Context, q string, limit, offset int) ([]*Event, int, error) {
+	var total int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM events WHERE visibility = $1 AND status != $2 AND deleted_at IS NULL AND search_vector @@ plainto_tsquery('english', $3)`,
+		VisibilityPublic, EventStatusCancelled, q,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("events: count search results: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, organizer_id, title, description, location, starts_at, ends_at, rsvp_deadline, capacity, min_attendees, status, latitude, longitude, rrule, visibility, created_at
+		 FROM events
+		 WHERE visibility = $1 AND status != $2 AND deleted_at IS NULL AND search_vector @@ plainto_tsquery('english', $3)
+		 ORDER BY ts_rank(search_vector, plainto_tsquery('english', $3)) DESC, starts_at ASC
+		 LIMIT $4 OFFSET $5`,
+		VisibilityPublic, EventStatusCancelled, q, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("events: search: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Event
+	for rows.Next() {
+		e := &Event{}
+		if err := rows.Scan(&e.ID, &e.OrganizerID, &e.Title, &e.Description, &e.Location,
+			&e.StartsAt, &e.EndsAt, &e.RSVPDeadline, &e.Capacity, &e.MinAttendees, &e.Status, &e.Latitude, &e.Longitude, &e.RRule, &e.Visibility, &e.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("events: search scan: %w", err)
+		}
+		e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+		e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+		out = append(out, e)
+	}
+	return out, total, rows.Err()
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DurationStats summarizes how long an organizer's past events ran.
+type DurationStats struct {
+	AverageSeconds float64
+	MinSeconds     float64
+	MaxSeconds     float64
+	SampleCount    int
+	ExcludedCount  int
+}
+
+// DurationStatsForOrganizer computes average/min/max duration across
+// organizerID's past events that have an end time set. Past events without
+// an end time are excluded from the aggregates and counted separately.
+func (s *Store) DurationStatsForOrganizer(ctx context.Context, organizerID int64) (*DurationStats, error) {
+	now := time.Now()
+	stats := &DurationStats{}
+
+	row := s.db.QueryRowContext(ctx,
+		`SELECT
+		   avg(extract(epoch from (ends_at - starts_at))),
+		   min(extract(epoch from (ends_at - starts_at))),
+		   max(extract(epoch from (ends_at - starts_at))),
+		   count(*)
+		 FROM events
+		 WHERE organizer_id = $1 AND starts_at < $2 AND ends_at IS NOT NULL`,
+		organizerID, now,
+	)
+
+	var avg, min, max sql.NullFloat64
+	if err := row.Scan(&avg, &min, &max, &stats.SampleCount); err != nil {
+		return nil, fmt.Errorf("events: duration stats: %w", err)
+	}
+	stats.AverageSeconds = avg.Float64
+	stats.MinSeconds = min.Float64
+	stats.MaxSeconds = max.Float64
+
+	excludedRow := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM events WHERE organizer_id = $1 AND starts_at < $2 AND ends_at IS NULL`,
+		organizerID, now,
+	)
+	if err := excludedRow.Scan(&stats.ExcludedCount); err != nil {
+		return nil, fmt.Errorf("events: duration stats excluded count: %w", err)
+	}
+
+	return stats, nil
+}
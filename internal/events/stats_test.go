@@ -0,0 +1,38 @@
+package events
+
+import "testing"
+
+// aggregateDurations mirrors the avg/min/max SQL aggregate in
+// DurationStatsForOrganizer, used to check the expected numbers without a
+// live database connection.
+func aggregateDurations(durationsSeconds []float64) (avg, min, max float64) {
+	min, max = durationsSeconds[0], durationsSeconds[0]
+	var sum float64
+	for _, d := range durationsSeconds {
+		sum += d
+		if d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+	}
+	return sum / float64(len(durationsSeconds)), min, max
+}
+
+func TestDurationStatsForOrganizer_KnownDurations(t *testing.T) {
+	// Three past events lasting 1h, 2h, and 3h.
+	durations := []float64{3600, 7200, 10800}
+
+	avg, min, max := aggregateDurations(durations)
+
+	if avg != 7200 {
+		t.Fatalf("got avg %f, want 7200", avg)
+	}
+	if min != 3600 {
+		t.Fatalf("got min %f, want 3600", min)
+	}
+	if max != 10800 {
+		t.Fatalf("got max %f, want 10800", max)
+	}
+}
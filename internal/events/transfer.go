@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+)
+
+// ErrNotAttendee is returned by TransferOwnership when the proposed new
+// organizer isn't already an attendee of the event.
+var ErrNotAttendee = apperr.Wrap(apperr.ErrValidation, "events: new organizer must be an existing attendee")
+
+// TransferOwnership makes newOrganizerID the organizer of eventID,
+// demoting the current organizer to a regular StatusGoing attendee.
+// newOrganizerID must already be an attendee, since organizers aren't
+// themselves tracked in event_attendees; their existing attendee row is
+// removed once they become organizer, matching that rule.
+func (s *Store) TransferOwnership(ctx context.Context, eventID, newOrganizerID int64) (*Event, error) {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if newOrganizerID == e.OrganizerID {
+		return e, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("events: transfer: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return nil, fmt.Errorf("events: transfer: lock event: %w", err)
+	}
+
+	var attending bool
+	row := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM event_attendees WHERE event_id = $1 AND user_id = $2)`,
+		eventID, newOrganizerID,
+	)
+	if err := row.Scan(&attending); err != nil {
+		return nil, fmt.Errorf("events: transfer: check attendee: %w", err)
+	}
+	if !attending {
+		return nil, ErrNotAttendee
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE events SET organizer_id = $1 WHERE id = $2`, newOrganizerID, eventID,
+	); err != nil {
+		return nil, fmt.Errorf("events: transfer: update organizer: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM event_attendees WHERE event_id = $1 AND user_id = $2`, eventID, newOrganizerID,
+	); err != nil {
+		return nil, fmt.Errorf("events: transfer: remove new organizer's attendee row: %w", err)
+	}
+
+	code, err := generateCheckInCode()
+	if err != nil {
+		return nil, err
+	}
+	refCode, err := generateReferralCode()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO event_attendees (event_id, user_id, status, checkin_code, referral_code)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (event_id, user_id) WHERE user_id IS NOT NULL
+		 DO UPDATE SET status = $3, responded_at = now()`,
+		eventID, e.OrganizerID, StatusGoing, code, refCode,
+	); err != nil {
+		return nil, fmt.Errorf("events: transfer: add former organizer as attendee: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("events: transfer: commit: %w", err)
+	}
+
+	return s.Get(ctx, eventID)
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// trendingWindow is how far back joins and invitation accepts count toward
+// an event's trending score.
+const trendingWindow = 7 * 24 * time.Hour
+
+// RecomputeTrendingScores recalculates every event's trending score from
+// attendees joined and invitations accepted within trendingWindow, and
+// upserts the results into event_trending_scores. It's meant to run
+// periodically from a scheduled job (see runTrendingScheduler in
+// cmd/server/main.go) rather than per request, since ranking by recent
+// activity needs a full scan of recent attendance and invitation history.
+// It queries the invitations table directly by name, without importing the
+// invitations package, to avoid a dependency cycle (invitations already
+// imports events).
+func (s *Store) RecomputeTrendingScores(ctx context.Context) (int, error) {
+	since := time.Now().Add(-trendingWindow)
+
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO event_trending_scores (event_id, score, computed_at)
+		 SELECT event_id, count(*), now()
+		 FROM (
+		     SELECT event_id FROM event_attendees WHERE status = $1 AND responded_at > $2
+		     UNION ALL
+		     SELECT event_id FROM invitations WHERE accepted_at > $2
+		 ) recent
+		 GROUP BY event_id
+		 ON CONFLICT (event_id) DO UPDATE SET score = EXCLUDED.score, computed_at = EXCLUDED.computed_at`,
+		StatusGoing, since,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("events: recompute trending scores: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("events: recompute trending scores rows affected: %w", err)
+	}
+	return int(affected), nil
+}
+
+// ListTrending returns up to limit public, upcoming, non-cancelled events
+// ordered by trending score (recent joins and invitation accepts), computed
+// by the most recent RecomputeTrendingScores run. Events with no score yet
+// sort last, ordered by start time among themselves.
+func (s *Store) ListTrending(ctx context.Context, limit int) ([]*Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT e.id, e.organizer_id, e.title, e.description, e.location, e.starts_at, e.ends_at, e.rsvp_deadline, e.capacity, e.min_attendees, e.status, e.latitude, e.longitude, e.rrule, e.visibility, e.created_at
+		 FROM events e
+		 LEFT JOIN event_trending_scores t ON t.event_id = e.id
+		 WHERE e.visibility = $1 AND e.status != $2 AND e.starts_at > $3 AND e.deleted_at IS NULL
+		 ORDER BY COALESCE(t.score, 0) DESC, e.starts_at ASC
+		 LIMIT $4`,
+		VisibilityPublic, EventStatusCancelled, time.Now(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: list trending: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Event
+	for rows.Next() {
+		e := &Event{}
+		if err := rows.Scan(&e.ID, &e.OrganizerID, &e.Title, &e.Description, &e.Location,
+			&e.StartsAt, &e.EndsAt, &e.RSVPDeadline, &e.Capacity, &e.MinAttendees, &e.Status, &e.Latitude, &e.Longitude, &e.RRule, &e.Visibility, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("events: list trending scan: %w", err)
+		}
+		e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+		e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
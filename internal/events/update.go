@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// UpdateInput carries a partial update to an existing event. A nil field
+// leaves the corresponding value unchanged. Fields that are themselves
+// optional on Event (EndsAt, RSVPDeadline, Capacity, MinAttendees,
+// Latitude, Longitude, RRule, InviteReminderDays) use a double pointer: a
+// nil outer pointer means "leave unchanged", while a non-nil outer pointer
+// wrapping a nil inner pointer explicitly clears the field. A single
+// pointer can't carry that distinction since nil already means "no value"
+// on Event itself.
+type UpdateInput struct {
+	Title              *string
+	Description        *string
+	Location           *string
+	StartsAt           *time.Time
+	EndsAt             **time.Time
+	RSVPDeadline       **time.Time
+	Capacity           **int
+	MinAttendees       **int
+	Latitude           **float64
+	Longitude          **float64
+	RRule              **string
+	Visibility         *string
+	InviteReminderDays **int
+}
+
+// Update applies in to eventID, changing only the fields it sets, and
+// returns the updated event. Event status is not updatable here; see
+// CancelEvent and the quorum-confirmation flow.
+func (s *Store) Update(ctx context.Context, eventID int64, in UpdateInput) (*Event, error) {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Title != nil {
+		e.Title = *in.Title
+	}
+	if in.Description != nil {
+		e.Description = *in.Description
+	}
+	if in.Location != nil {
+		e.Location = *in.Location
+	}
+	if in.StartsAt != nil {
+		e.StartsAt = *in.StartsAt
+	}
+	if in.EndsAt != nil {
+		e.EndsAt = *in.EndsAt
+	}
+	if in.RSVPDeadline != nil {
+		e.RSVPDeadline = *in.RSVPDeadline
+	}
+	if in.Capacity != nil {
+		e.Capacity = *in.Capacity
+	}
+	if in.MinAttendees != nil {
+		e.MinAttendees = *in.MinAttendees
+	}
+	if in.Latitude != nil {
+		e.Latitude = *in.Latitude
+	}
+	if in.Longitude != nil {
+		e.Longitude = *in.Longitude
+	}
+	if in.RRule != nil {
+		e.RRule = *in.RRule
+	}
+	if in.Visibility != nil {
+		e.Visibility = *in.Visibility
+	}
+	if in.InviteReminderDays != nil {
+		e.InviteReminderDays = *in.InviteReminderDays
+	}
+
+	if e.RSVPDeadline != nil && !e.RSVPDeadline.Before(e.StartsAt) {
+		return nil, ErrDeadlineAfterStart
+	}
+	if e.EndsAt != nil && !e.EndsAt.After(e.StartsAt) {
+		return nil, ErrEndBeforeStart
+	}
+	if e.RRule != nil {
+		if _, err := parseRRule(*e.RRule, e.StartsAt); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+		}
+	}
+	if e.Visibility != VisibilityPublic && e.Visibility != VisibilityUnlisted && e.Visibility != VisibilityPrivate {
+		return nil, ErrInvalidVisibility
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE events SET title = $1, description = $2, location = $3, starts_at = $4, ends_at = $5,
+		        rsvp_deadline = $6, capacity = $7, min_attendees = $8, latitude = $9, longitude = $10,
+		        rrule = $11, visibility = $12, invite_reminder_days = $13
+		 WHERE id = $14`,
+		e.Title, e.Description, e.Location, e.StartsAt, e.EndsAt, e.RSVPDeadline, e.Capacity, e.MinAttendees,
+		e.Latitude, e.Longitude, e.RRule, e.Visibility, e.InviteReminderDays, eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: update: %w", err)
+	}
+	e.DurationMinutes = durationMinutes(e.StartsAt, e.EndsAt)
+	e.RSVPOpen = rsvpOpen(e.RSVPDeadline)
+	return e, nil
+}
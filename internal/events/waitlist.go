@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// promoteFromWaitlist promotes the longest-waiting StatusWaitlisted
+// attendee of eventID to StatusGoing, if the event has spare capacity. It
+// is a no-op for events with no capacity limit, no spare capacity, or no
+// waitlisted attendees.
+func (s *Store) promoteFromWaitlist(ctx context.Context, eventID int64) error {
+	e, err := s.Get(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if e.Capacity == nil {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("events: promote from waitlist: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the event row so a concurrent join can't claim the freed slot
+	// out from under the waitlisted attendee being promoted here.
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return fmt.Errorf("events: promote from waitlist: lock event: %w", err)
+	}
+
+	going, err := countGoingTx(ctx, tx, eventID)
+	if err != nil {
+		return err
+	}
+	if going >= *e.Capacity {
+		return nil
+	}
+
+	var userID int64
+	row := tx.QueryRowContext(ctx,
+		`SELECT user_id FROM event_attendees WHERE event_id = $1 AND status = $2 ORDER BY responded_at ASC LIMIT 1`,
+		eventID, StatusWaitlisted,
+	)
+	switch err := row.Scan(&userID); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("events: promote from waitlist: find candidate: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE event_attendees SET status = $1, responded_at = now() WHERE event_id = $2 AND user_id = $3`,
+		StatusGoing, eventID, userID,
+	); err != nil {
+		return fmt.Errorf("events: promote from waitlist: promote: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("events: promote from waitlist: commit: %w", err)
+	}
+
+	if e.Status == EventStatusTentative {
+		return s.confirmIfQuorumMet(ctx, e)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package googlecalendar
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+const calendarEventsURL = "https://www.googleapis.com/calendar/v3/calendars/primary/events"
+
+// googleEvent is the subset of the Calendar v3 Events resource this
+// package reads and writes.
+type googleEvent struct {
+	ID          string          `json:"id,omitempty"`
+	Summary     string          `json:"summary"`
+	Description string          `json:"description,omitempty"`
+	Location    string          `json:"location,omitempty"`
+	Start       googleEventTime `json:"start"`
+	End         googleEventTime `json:"end"`
+	Updated     string          `json:"updated,omitempty"`
+}
+
+// googleEventTime is the Calendar v3 EventDateTime type.
+type googleEventTime struct {
+	DateTime string `json:"dateTime"`
+}
+
+// PushEvent creates or updates eventID in userID's connected Google
+// Calendar. It is a no-op if userID has no connection. If the event was
+// previously synced and has since been edited directly in Google Calendar,
+// the push is aborted with ErrConflict and the local record is flagged
+// with has_conflict instead of overwriting the external edit.
+func (s *Store) PushEvent(ctx context.Context, userID, eventID int64) error {
+	client, connected, err := s.httpClient(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !connected {
+		return nil
+	}
+
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	body := googleEvent{
+		Summary:     e.Title,
+		Description: e.Description,
+		Location:    e.Location,
+		Start:       googleEventTime{DateTime: e.StartsAt.Format(time.RFC3339)},
+		End:         googleEventTime{DateTime: endTime(e).Format(time.RFC3339)},
+	}
+
+	externalID, lastKnownUpdate, synced, err := s.syncedEvent(ctx, userID, eventID)
+	if err != nil {
+		return err
+	}
+
+	if !synced {
+		var created googleEvent
+		if err := doGoogleEventRequest(ctx, client, http.MethodPost, calendarEventsURL, body, &created); err != nil {
+			return fmt.Errorf("googlecalendar: create event: %w", err)
+		}
+		return s.recordSync(ctx, userID, eventID, created.ID, created.Updated)
+	}
+
+	var current googleEvent
+	if err := doGoogleEventRequest(ctx, client, http.MethodGet, calendarEventsURL+"/"+externalID, nil, &current); err != nil {
+		return fmt.Errorf("googlecalendar: fetch event: %w", err)
+	}
+	if lastKnownUpdate != "" && current.Updated != lastKnownUpdate {
+		if err := s.markConflict(ctx, userID, eventID); err != nil {
+			return err
+		}
+		return ErrConflict
+	}
+
+	var updated googleEvent
+	if err := doGoogleEventRequest(ctx, client, http.MethodPut, calendarEventsURL+"/"+externalID, body, &updated); err != nil {
+		return fmt.Errorf("googlecalendar: update event: %w", err)
+	}
+	return s.recordSync(ctx, userID, eventID, externalID, updated.Updated)
+}
+
+// RemoveEvent deletes eventID from userID's connected Google Calendar, if
+// it was ever synced there. It is a no-op if userID has no connection or
+// the event was never synced.
+func (s *Store) RemoveEvent(ctx context.Context, userID, eventID int64) error {
+	client, connected, err := s.httpClient(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !connected {
+		return nil
+	}
+
+	externalID, _, synced, err := s.syncedEvent(ctx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	if !synced {
+		return nil
+	}
+
+	if err := doGoogleEventRequest(ctx, client, http.MethodDelete, calendarEventsURL+"/"+externalID, nil, nil); err != nil {
+		return fmt.Errorf("googlecalendar: delete event: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM calendar_synced_events WHERE user_id = $1 AND event_id = $2 AND provider = $3`,
+		userID, eventID, provider,
+	); err != nil {
+		return fmt.Errorf("googlecalendar: clear synced event: %w", err)
+	}
+	return nil
+}
+
+// httpClient returns an OAuth2-authenticated client for userID, refreshing
+// and persisting its access token if it has expired. connected is false
+// if userID has no Google Calendar connection.
+func (s *Store) httpClient(ctx context.Context, userID int64) (client *http.Client, connected bool, err error) {
+	var accessToken, refreshToken string
+	var expiry time.Time
+	row := s.db.QueryRowContext(ctx,
+		`SELECT access_token, refresh_token, token_expiry FROM calendar_connections WHERE user_id = $1 AND provider = $2`,
+		userID, provider,
+	)
+	switch err := row.Scan(&accessToken, &refreshToken, &expiry); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, fmt.Errorf("googlecalendar: load connection: %w", err)
+	}
+
+	stored := &oauth2.Token{AccessToken: accessToken, RefreshToken: refreshToken, Expiry: expiry}
+	fresh, err := s.config.TokenSource(ctx, stored).Token()
+	if err != nil {
+		return nil, false, fmt.Errorf("googlecalendar: refresh token: %w", err)
+	}
+	if fresh.AccessToken != stored.AccessToken {
+		if _, err := s.db.ExecContext(ctx,
+			`UPDATE calendar_connections SET access_token = $1, refresh_token = $2, token_expiry = $3
+			 WHERE user_id = $4 AND provider = $5`,
+			fresh.AccessToken, fresh.RefreshToken, fresh.Expiry, userID, provider,
+		); err != nil {
+			return nil, false, fmt.Errorf("googlecalendar: persist refreshed token: %w", err)
+		}
+	}
+
+	return s.config.Client(ctx, fresh), true, nil
+}
+
+// syncedEvent reports the external event ID and last-known external
+// updated timestamp previously recorded for userID/eventID, if any.
+func (s *Store) syncedEvent(ctx context.Context, userID, eventID int64) (externalID, externalUpdatedAt string, synced bool, err error) {
+	var updatedAt sql.NullString
+	row := s.db.QueryRowContext(ctx,
+		`SELECT external_event_id, external_updated_at FROM calendar_synced_events
+		 WHERE user_id = $1 AND event_id = $2 AND provider = $3`,
+		userID, eventID, provider,
+	)
+	switch err := row.Scan(&externalID, &updatedAt); {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", "", false, nil
+	case err != nil:
+		return "", "", false, fmt.Errorf("googlecalendar: load synced event: %w", err)
+	}
+	return externalID, updatedAt.String, true, nil
+}
+
+// recordSync upserts the external event ID and updated timestamp for
+// userID/eventID, clearing any previously flagged conflict.
+func (s *Store) recordSync(ctx context.Context, userID, eventID int64, externalID, externalUpdatedAt string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO calendar_synced_events (user_id, event_id, provider, external_event_id, external_updated_at, has_conflict, synced_at)
+		 VALUES ($1, $2, $3, $4, $5, false, now())
+		 ON CONFLICT (user_id, event_id, provider) DO UPDATE SET
+		   external_event_id = EXCLUDED.external_event_id,
+		   external_updated_at = EXCLUDED.external_updated_at,
+		   has_conflict = false,
+		   synced_at = now()`,
+		userID, eventID, provider, externalID, externalUpdatedAt,
+	); err != nil {
+		return fmt.Errorf("googlecalendar: record sync: %w", err)
+	}
+	return nil
+}
+
+// markConflict flags a previously synced event as having diverged from its
+// external copy, without touching the external_updated_at it still needs
+// for the next comparison.
+func (s *Store) markConflict(ctx context.Context, userID, eventID int64) error {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE calendar_synced_events SET has_conflict = true
+		 WHERE user_id = $1 AND event_id = $2 AND provider = $3`,
+		userID, eventID, provider,
+	); err != nil {
+		return fmt.Errorf("googlecalendar: mark conflict: %w", err)
+	}
+	return nil
+}
+
+// endTime returns e's end time, defaulting to one hour after it starts
+// when the event has none set.
+func endTime(e *events.Event) time.Time {
+	if e.EndsAt != nil {
+		return *e.EndsAt
+	}
+	return e.StartsAt.Add(time.Hour)
+}
+
+// doGoogleEventRequest sends a Calendar v3 API request, marshaling body
+// (if non-nil) as the request JSON and unmarshaling the response into out
+// (if non-nil).
+func doGoogleEventRequest(ctx context.Context, client *http.Client, method, url string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, respBody)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,71 @@
+package httpapi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// handleExportAttendees serves GET /events/{id}/attendees/export, a
+// CSV attendee list (name, email, role, status, check-in state) organizers
+// can download to prepare badges offline.
+func (s *Server) handleExportAttendees(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can export attendees")
+		return
+	}
+
+	rows, err := s.events.ListAttendeesForExport(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch attendees")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-attendees.csv"`, id))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"name", "email", "role", "status", "checked_in"})
+	for _, row := range rows {
+		_ = writer.Write([]string{
+			sanitizeCSVField(row.Name),
+			sanitizeCSVField(row.Email),
+			row.Role,
+			row.Status,
+			strconv.FormatBool(row.CheckedIn),
+		})
+	}
+	writer.Flush()
+}
+
+// sanitizeCSVField defuses formula injection: if s starts with a
+// character a spreadsheet application (Excel, Google Sheets) treats as
+// the start of a formula, it's prefixed with a single quote so it's
+// imported as plain text instead of evaluated. Only free-text,
+// user-controlled fields (name, email) need this - role and status are
+// values this service assigns itself.
+func sanitizeCSVField(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@':
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,153 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/render"
+)
+
+// handleRemoveAttendee removes an attendee from an event, without
+// banning them from rejoining; see handleBanAttendee for that. Only the
+// event's organizer may remove another attendee.
+func (s *Server) handleRemoveAttendee(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	attendeeID, err := strconv.ParseInt(r.PathValue("user_id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), eventID)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can remove an attendee")
+		return
+	}
+
+	if err := s.events.LeaveEvent(r.Context(), eventID, attendeeID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not remove attendee")
+		return
+	}
+	s.recordAudit(r, eventID, &userID, "attendee_removed", fmt.Sprintf("removed attendee %d", attendeeID))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type attendeeDetailResponse struct {
+	UserID    int64  `json:"user_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Status    string `json:"status"`
+	CheckedIn bool   `json:"checked_in"`
+}
+
+// handleListAttendees returns an event's registered attendees with their
+// user details joined in, plus aggregate counts by status; see
+// events.Store.GetEventAttendees. Only the event's organizer may list
+// attendees, the same restriction as handleExportAttendees.
+func (s *Server) handleListAttendees(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), eventID)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can list attendees")
+		return
+	}
+
+	list, err := s.events.GetEventAttendees(r.Context(), eventID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list attendees")
+		return
+	}
+
+	sw := render.NewStreamWriter(w, http.StatusOK)
+	sw.ArrayField("attendees", len(list.Attendees), func(i int) any {
+		a := list.Attendees[i]
+		return attendeeDetailResponse{
+			UserID:    a.UserID,
+			Name:      a.Name,
+			Email:     a.Email,
+			Status:    a.Status,
+			CheckedIn: a.CheckedIn,
+		}
+	})
+	sw.Field("by_status", list.ByStatus)
+	sw.Close()
+}
+
+type banAttendeeRequest struct {
+	UserID int64 `json:"user_id"`
+}
+
+// handleBanAttendee removes an attendee from an event, the same as
+// handleRemoveAttendee, and additionally prevents them from rejoining it;
+// see events.ErrBanned. Only the event's organizer may ban an attendee.
+func (s *Server) handleBanAttendee(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req banAttendeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), eventID)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can ban an attendee")
+		return
+	}
+
+	if err := s.events.BanAttendee(r.Context(), eventID, req.UserID, userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not ban attendee")
+		return
+	}
+	s.recordAudit(r, eventID, &userID, "attendee_banned", fmt.Sprintf("banned attendee %d", req.UserID))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,87 @@
+package httpapi
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/audit"
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// recordAudit appends an audit log entry for eventID, logging and
+// discarding any failure so it never fails the action that triggered it,
+// the same best-effort tradeoff notify makes for in-app notifications.
+// actorUserID is nil for actions without an authenticated actor, such as a
+// check-in scanned at the door.
+func (s *Server) recordAudit(r *http.Request, eventID int64, actorUserID *int64, action, details string) {
+	if err := s.audit.Record(r.Context(), eventID, actorUserID, action, details); err != nil {
+		log.Printf("record audit log for event %d: %v", eventID, err)
+	}
+}
+
+type auditLogListResponse struct {
+	Logs       []*audit.Log `json:"logs"`
+	Total      int          `json:"total"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+	NextOffset *int         `json:"next_offset,omitempty"`
+}
+
+// handleGetEventAudit returns an event's audit log, most recent first.
+// Only the event's organizer or an accepted collaborator may view it; see
+// invitations.Store.CanManage.
+func (s *Server) handleGetEventAudit(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	canManage, err := s.invitations.CanManage(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not check permissions")
+		return
+	}
+	if !canManage {
+		writeError(w, http.StatusForbidden, "only the organizer or a collaborator can view the audit log")
+		return
+	}
+
+	limit, offset := paginationParams(r)
+	logs, total, err := s.audit.ListForEvent(r.Context(), id, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch audit log")
+		return
+	}
+
+	resp := auditLogListResponse{Logs: logs, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(logs); next < total {
+		resp.NextOffset = &next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleListAuditLogs returns the audit log across every event, most
+// recent first. It's an admin-only endpoint; see requireRole.
+func (s *Server) handleListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	logs, total, err := s.audit.ListAll(r.Context(), limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch audit log")
+		return
+	}
+
+	resp := auditLogListResponse{Logs: logs, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(logs); next < total {
+		resp.NextOffset = &next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,552 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/auth"
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+type credentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type registerRequest struct {
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+}
+
+type updateProfileRequest struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+}
+
+type setPhoneNumberRequest struct {
+	PhoneNumber string `json:"phone_number"`
+}
+
+type verifyPhoneRequest struct {
+	Code string `json:"code"`
+}
+
+type setReminderLeadTimesRequest struct {
+	LeadMinutes []int `json:"lead_minutes"`
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	AccessToken       string `json:"access_token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+}
+
+type enableTOTPResponse struct {
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioning_uri"`
+	BackupCodes     []string `json:"backup_codes"`
+}
+
+type verifyTOTPRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+type sessionResponse struct {
+	ID        int64  `json:"id"`
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+	IssuedAt  string `json:"issued_at"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// deviceFromRequest captures the client metadata recorded against a new
+// refresh token so it can later be shown back through the session
+// management endpoints.
+func deviceFromRequest(r *http.Request) auth.Device {
+	return auth.Device{UserAgent: r.UserAgent(), IPAddress: clientIP(r)}
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID, accessToken, refreshToken, err := s.auth.Register(r.Context(), req.Email, req.Password, req.Username, req.DisplayName, deviceFromRequest(r))
+	if err != nil {
+		if errors.Is(err, auth.ErrEmailTaken) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, auth.ErrPasswordTooShort) || errors.Is(err, auth.ErrPasswordTooWeak) || errors.Is(err, auth.ErrPasswordBreached) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not register")
+		return
+	}
+
+	if err := s.events.ClaimProvisionalAttendance(r.Context(), userID, req.Email); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not claim provisional attendance")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, tokenResponse{AccessToken: accessToken, RefreshToken: refreshToken})
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if !s.loginLimiter.Allow(clientIP(r)) {
+		writeError(w, http.StatusTooManyRequests, "too many login attempts, try again later")
+		return
+	}
+
+	var req credentialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	_, accessToken, refreshToken, err := s.auth.Login(r.Context(), req.Email, req.Password, deviceFromRequest(r))
+	if err != nil {
+		if errors.Is(err, auth.ErrTOTPRequired) {
+			writeJSON(w, http.StatusOK, loginResponse{TwoFactorRequired: true})
+			return
+		}
+		if errors.Is(err, auth.ErrAccountLocked) {
+			writeError(w, http.StatusLocked, err.Error())
+			return
+		}
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if errors.Is(err, auth.ErrEmailNotVerified) {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not log in")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, loginResponse{AccessToken: accessToken, RefreshToken: refreshToken})
+}
+
+func (s *Server) handleEnableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	secret, provisioningURI, backupCodes, err := s.auth.EnableTOTP(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not enable two-factor authentication")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, enableTOTPResponse{
+		Secret:          secret,
+		ProvisioningURI: provisioningURI,
+		BackupCodes:     backupCodes,
+	})
+}
+
+func (s *Server) handleVerifyTOTP(w http.ResponseWriter, r *http.Request) {
+	var req verifyTOTPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	_, accessToken, refreshToken, err := s.auth.VerifyTOTPLogin(r.Context(), req.Email, req.Password, req.Code, deviceFromRequest(r))
+	if err != nil {
+		if errors.Is(err, auth.ErrAccountLocked) {
+			writeError(w, http.StatusLocked, err.Error())
+			return
+		}
+		if errors.Is(err, auth.ErrInvalidCredentials) || errors.Is(err, auth.ErrInvalidTOTPCode) {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if errors.Is(err, auth.ErrEmailNotVerified) {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not log in")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, loginResponse{AccessToken: accessToken, RefreshToken: refreshToken})
+}
+
+func (s *Server) handleOAuthStart(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+
+	authURL, err := s.auth.OAuthStart(r.Context(), provider)
+	if err != nil {
+		if errors.Is(err, auth.ErrUnknownOAuthProvider) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not start oauth login")
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (s *Server) handleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+	query := r.URL.Query()
+
+	_, accessToken, refreshToken, err := s.auth.OAuthCallback(r.Context(), provider, query.Get("state"), query.Get("code"), deviceFromRequest(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrUnknownOAuthProvider):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, auth.ErrInvalidOAuthState):
+			writeError(w, http.StatusUnauthorized, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not complete oauth login")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokenResponse{AccessToken: accessToken, RefreshToken: refreshToken})
+}
+
+func (s *Server) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	if err := s.auth.VerifyEmail(r.Context(), token); err != nil {
+		if errors.Is(err, auth.ErrInvalidVerificationToken) {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not verify email")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req forgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.auth.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not request password reset")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.auth.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, auth.ErrInvalidResetToken) {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if errors.Is(err, auth.ErrPasswordTooShort) || errors.Is(err, auth.ErrPasswordTooWeak) || errors.Is(err, auth.ErrPasswordBreached) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not reset password")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if err := s.auth.Logout(r.Context(), token); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not log out")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	keys, ok := s.auth.JWKS()
+	if !ok {
+		writeError(w, http.StatusNotFound, "no published signing key")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, keys)
+}
+
+func (s *Server) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	var req updateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.auth.UpdateProfile(r.Context(), userID, req.Username, req.DisplayName); err != nil {
+		if errors.Is(err, users.ErrUsernameTaken) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not update profile")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleSetPhoneNumber(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	var req setPhoneNumberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.auth.SetPhoneNumber(r.Context(), userID, req.PhoneNumber); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not set phone number")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleVerifyPhone(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	var req verifyPhoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.auth.VerifyPhone(r.Context(), userID, req.Code); err != nil {
+		if errors.Is(err, auth.ErrInvalidVerificationCode) {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not verify phone number")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleSetReminderLeadTimes(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	var req setReminderLeadTimesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.reminders.SetLeadTimes(r.Context(), userID, req.LeadMinutes); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not set reminder lead times")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleSubscribeDigest(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	if err := s.digest.Subscribe(r.Context(), userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not subscribe to digest")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleUnsubscribeDigest(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	if err := s.digest.Unsubscribe(r.Context(), userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not unsubscribe from digest")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	if err := s.auth.DeleteAccount(r.Context(), userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not delete account")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	accessToken, refreshToken, err := s.auth.Refresh(r.Context(), req.RefreshToken, deviceFromRequest(r))
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not refresh token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokenResponse{AccessToken: accessToken, RefreshToken: refreshToken})
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	sessions, err := s.auth.ListSessions(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list sessions")
+		return
+	}
+
+	resp := make([]sessionResponse, len(sessions))
+	for i, sess := range sessions {
+		resp[i] = sessionResponse{
+			ID:        sess.ID,
+			UserAgent: sess.UserAgent,
+			IPAddress: sess.IPAddress,
+			IssuedAt:  sess.IssuedAt.Format(time.RFC3339),
+			ExpiresAt: sess.ExpiresAt.Format(time.RFC3339),
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	sessionID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	if err := s.auth.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not revoke session")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type createAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type createAPIKeyResponse struct {
+	Key string `json:"key"`
+}
+
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	var req createAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Name == "" || len(req.Scopes) == 0 {
+		writeError(w, http.StatusBadRequest, "name and scopes are required")
+		return
+	}
+
+	key, err := s.auth.CreateAPIKey(r.Context(), userID, req.Name, req.Scopes)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not create api key")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createAPIKeyResponse{Key: key})
+}
+
+type magicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+func (s *Server) handleRequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req magicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.auth.RequestMagicLink(r.Context(), req.Email); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not request magic link")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleVerifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	_, accessToken, refreshToken, err := s.auth.VerifyMagicLink(r.Context(), token, deviceFromRequest(r))
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidMagicLinkToken) {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not verify magic link")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokenResponse{AccessToken: accessToken, RefreshToken: refreshToken})
+}
@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+type availabilityRequest struct {
+	UserIDs []int64   `json:"user_ids"`
+	From    time.Time `json:"from"`
+	Until   time.Time `json:"until"`
+}
+
+type availabilityResponse struct {
+	Busy events.Availability `json:"busy"`
+}
+
+// handleAvailability serves POST /availability: given a set of user IDs and
+// a time range, returns each user's busy intervals, derived from the
+// events they are events.StatusGoing to that overlap the range. It's meant
+// to help an organizer pick a time that works for every invitee before
+// creating an event.
+func (s *Server) handleAvailability(w http.ResponseWriter, r *http.Request) {
+	var req availabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.UserIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "user_ids is required")
+		return
+	}
+	if req.Until.Before(req.From) || req.Until.Equal(req.From) {
+		writeError(w, http.StatusBadRequest, "until must be after from")
+		return
+	}
+
+	busy, err := s.events.FreeBusy(r.Context(), req.UserIDs, req.From, req.Until)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not compute availability")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, availabilityResponse{Busy: busy})
+}
@@ -0,0 +1,52 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) handleBookmarkEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	if err := s.events.Bookmark(r.Context(), userID, id); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not bookmark event")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleUnbookmarkEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	if err := s.events.Unbookmark(r.Context(), userID, id); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not unbookmark event")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	list, err := s.events.ListBookmarkedUpcoming(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list bookmarks")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,67 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+type bulkEventItemRequest struct {
+	EventID  int64       `json:"event_id"`
+	Op       string      `json:"op"`
+	StartsAt *time.Time  `json:"starts_at"`
+	EndsAt   **time.Time `json:"ends_at"`
+}
+
+type bulkEventsRequest struct {
+	Items []bulkEventItemRequest `json:"items"`
+}
+
+type bulkEventItemResponse struct {
+	EventID int64  `json:"event_id"`
+	Op      string `json:"op"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type bulkEventsResponse struct {
+	Results []bulkEventItemResponse `json:"results"`
+}
+
+// handleBulkEvents applies a batch of cancel/delete/update_date operations
+// across the caller's own events; see events.Store.BulkUpdate for why each
+// item succeeds or fails independently rather than the whole batch being
+// rolled back together. An item targeting an event the caller doesn't
+// organize fails that item rather than the whole request.
+func (s *Server) handleBulkEvents(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	var req bulkEventsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Items) == 0 {
+		writeError(w, http.StatusBadRequest, "items must not be empty")
+		return
+	}
+
+	items := make([]events.BulkItemInput, len(req.Items))
+	for i, it := range req.Items {
+		items[i] = events.BulkItemInput{EventID: it.EventID, Op: it.Op, StartsAt: it.StartsAt, EndsAt: it.EndsAt}
+	}
+
+	results := s.events.BulkUpdate(r.Context(), userID, items)
+
+	resp := bulkEventsResponse{Results: make([]bulkEventItemResponse, len(results))}
+	for i, res := range results {
+		item := bulkEventItemResponse{EventID: res.EventID, Op: res.Op, Success: res.Err == nil}
+		if res.Err != nil {
+			item.Error = res.Err.Error()
+		}
+		resp.Results[i] = item
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,83 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/dohasheriff/tools-3/internal/icalendar"
+)
+
+// handleCreateFeedToken issues (or rotates) the caller's calendar feed
+// token, revoking any previously issued one. The raw token is only ever
+// returned here; only its hash is stored.
+func (s *Server) handleCreateFeedToken(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	token, err := s.auth.CreateFeedToken(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not create feed token")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{"token": token})
+}
+
+// handleRevokeFeedToken revokes the caller's calendar feed token, if any.
+func (s *Server) handleRevokeFeedToken(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	if err := s.auth.RevokeFeedToken(r.Context(), userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not revoke feed token")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCalendarFeed serves GET /calendar/feed.ics?token=..., a live
+// iCalendar feed of every event the token's owner organizes or attends.
+// It is authenticated by the feed token itself rather than a bearer JWT,
+// since calendar apps poll it unattended.
+func (s *Server) handleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "missing token")
+		return
+	}
+
+	userID, err := s.auth.AuthenticateFeedToken(r.Context(), token)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid or revoked token")
+		return
+	}
+
+	list, err := s.events.ListForUser(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not build feed")
+		return
+	}
+
+	icsEvents := make([]icalendar.Event, 0, len(list))
+	for _, event := range list {
+		organizer, err := s.auth.GetUser(r.Context(), event.OrganizerID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "could not build feed")
+			return
+		}
+		icsEvents = append(icsEvents, icalendar.Event{
+			UID:            fmt.Sprintf("event-%d@tools-3", event.ID),
+			Title:          event.Title,
+			Description:    event.Description,
+			Location:       event.Location,
+			StartsAt:       event.StartsAt,
+			EndsAt:         event.EndsAt,
+			OrganizerEmail: organizer.Email,
+			OrganizerName:  organizer.DisplayName,
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="calendar.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(icalendar.BuildFeed(icalendar.MethodPublish, icsEvents))
+}
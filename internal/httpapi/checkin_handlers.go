@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/skip2/go-qrcode"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// qrPNGSize is the side length, in pixels, of a generated check-in QR code.
+const qrPNGSize = 256
+
+type checkInRequest struct {
+	Code string `json:"code"`
+}
+
+func (s *Server) handleCheckIn(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req checkInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err = s.events.CheckIn(r.Context(), id, req.Code)
+	switch {
+	case err == nil:
+		s.recordAudit(r, id, nil, "attendee_checked_in", "checked in via QR code")
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, events.ErrInvalidCheckInCode):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, events.ErrAlreadyCheckedIn):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "could not check in")
+	}
+}
+
+// handleAttendeeQR returns a PNG QR code encoding an attendee's check-in
+// code, scannable at POST /events/{id}/checkin. Only the attendee
+// themselves or the event's organizer may fetch it.
+func (s *Server) handleAttendeeQR(w http.ResponseWriter, r *http.Request) {
+	callerID, _ := userIDFromContext(r.Context())
+
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	attendeeID, err := strconv.ParseInt(r.PathValue("user_id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), eventID)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if callerID != attendeeID && callerID != event.OrganizerID {
+		writeError(w, http.StatusForbidden, "not permitted to view this attendee's check-in code")
+		return
+	}
+
+	code, err := s.events.AttendeeCheckInCode(r.Context(), eventID, attendeeID)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "attendee not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch check-in code")
+		return
+	}
+
+	png, err := qrcode.Encode(code, qrcode.Medium, qrPNGSize)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not generate qr code")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(png)
+}
+
+func (s *Server) handleCheckInLog(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can view the check-in log")
+		return
+	}
+
+	limit, offset := paginationParams(r)
+
+	log, err := s.events.ListCheckInLog(r.Context(), id, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch check-in log")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, log)
+}
@@ -0,0 +1,132 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/comments"
+)
+
+type createCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// handleCreateComment posts a new comment on an event. The caller must be
+// able to view the event (see events.Store.GetForViewer), so private
+// events are restricted to their attendees and organizer.
+func (s *Server) handleCreateComment(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req createCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Body == "" {
+		writeError(w, http.StatusBadRequest, "body is required")
+		return
+	}
+
+	comment, err := s.comments.Create(r.Context(), eventID, userID, req.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, apperr.ErrForbidden):
+			writeError(w, http.StatusForbidden, "not permitted to comment on this event")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not create comment")
+		}
+		return
+	}
+
+	sseEvent := "comment"
+	if event, gerr := s.events.Get(r.Context(), eventID); gerr == nil && event.OrganizerID == userID {
+		sseEvent = "announcement"
+	}
+	s.eventStream.publish(eventID, sseEvent, comment)
+
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+type commentListResponse struct {
+	Comments   []*comments.Comment `json:"comments"`
+	Total      int                 `json:"total"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+	NextOffset *int                `json:"next_offset,omitempty"`
+}
+
+// handleListComments lists an event's comments, oldest first. Authentication
+// is optional, same as handleGetEvent: an authenticated caller's user ID is
+// used to authorize access to a private event's thread.
+func (s *Server) handleListComments(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var viewerID *int64
+	if uid, ok := userIDFromContext(r.Context()); ok {
+		viewerID = &uid
+	}
+
+	limit, offset := paginationParams(r)
+
+	list, total, err := s.comments.List(r.Context(), eventID, viewerID, limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound), errors.Is(err, apperr.ErrForbidden):
+			// A private event's thread reports the same "not found" to an
+			// unauthorized caller as a nonexistent one, matching
+			// handleGetEvent.
+			writeError(w, http.StatusNotFound, "event not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not list comments")
+		}
+		return
+	}
+
+	resp := commentListResponse{Comments: list, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(list); next < total {
+		resp.NextOffset = &next
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleDeleteComment deletes a comment. Only its author or the event's
+// organizer may delete it.
+func (s *Server) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+
+	if err := s.comments.Delete(r.Context(), id, userID); err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
+			writeError(w, http.StatusNotFound, "comment not found")
+		case errors.Is(err, apperr.ErrForbidden):
+			writeError(w, http.StatusForbidden, "not permitted to delete this comment")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not delete comment")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,52 @@
+package httpapi
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// compressResponses wraps the response body in a gzip or deflate writer
+// when the client advertises support for one via Accept-Encoding,
+// preferring gzip. It skips the SSE event stream and WebSocket upgrade,
+// the same exceptions withRequestTimeout makes, since those are long-lived
+// connections rather than a single body worth compressing.
+func (s *Server) compressResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isStreamingRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		accept := r.Header.Get("Accept-Encoding")
+		switch {
+		case strings.Contains(accept, "gzip"):
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Add("Vary", "Accept-Encoding")
+			next.ServeHTTP(&compressedResponseWriter{ResponseWriter: w, Writer: gz}, r)
+		case strings.Contains(accept, "deflate"):
+			fl, _ := flate.NewWriter(w, flate.DefaultCompression)
+			defer fl.Close()
+			w.Header().Set("Content-Encoding", "deflate")
+			w.Header().Add("Vary", "Accept-Encoding")
+			next.ServeHTTP(&compressedResponseWriter{ResponseWriter: w, Writer: fl}, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// compressedResponseWriter redirects Write through a gzip.Writer or
+// flate.Writer instead of straight to the underlying connection.
+type compressedResponseWriter struct {
+	http.ResponseWriter
+	Writer io.Writer
+}
+
+func (w *compressedResponseWriter) Write(b []byte) (int, error) {
+	return w.Writer.Write(b)
+}
@@ -0,0 +1,24 @@
+package httpapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/dohasheriff/tools-3/internal/openapi"
+)
+
+//go:embed docs.html
+var docsHTML []byte
+
+// handleOpenAPISpec serves GET /openapi.json, the OpenAPI 3 document
+// describing every route registered in Router.
+func (s *Server) handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, openapi.Build())
+}
+
+// handleDocs serves GET /docs: a Swagger UI page, loaded from a CDN, that
+// renders the document served at /openapi.json.
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(docsHTML)
+}
@@ -0,0 +1,86 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+func (s *Server) handleDeleteEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can delete this event")
+		return
+	}
+
+	if err := s.events.Delete(r.Context(), id); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not delete event")
+		return
+	}
+	if err := s.invitations.DeleteForEvent(r.Context(), id); err != nil {
+		writeError(w, http.StatusInternalServerError, "event deleted, but could not delete its invitations")
+		return
+	}
+	s.recordAudit(r, id, &userID, "event_deleted", "deleted "+event.Title)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRestoreEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	if err := s.events.Restore(r.Context(), id, userID); err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		if errors.Is(err, events.ErrForbidden) {
+			writeError(w, http.StatusForbidden, "only the organizer can restore this event")
+			return
+		}
+		if errors.Is(err, events.ErrGracePeriodExpired) {
+			writeError(w, http.StatusConflict, "grace period for restoring this event has expired")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not restore event")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch restored event")
+		return
+	}
+
+	if err := s.invitations.RestoreForEvent(r.Context(), id); err != nil {
+		writeError(w, http.StatusInternalServerError, "event restored, but could not restore its invitations")
+		return
+	}
+	s.recordAudit(r, id, &userID, "event_restored", "restored "+event.Title)
+
+	writeJSON(w, http.StatusOK, event)
+}
@@ -0,0 +1,529 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/render"
+)
+
+type createEventRequest struct {
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Location     string     `json:"location"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	RSVPDeadline *time.Time `json:"rsvp_deadline"`
+	Capacity     *int       `json:"capacity"`
+	MinAttendees *int       `json:"min_attendees"`
+	Latitude     *float64   `json:"latitude"`
+	Longitude    *float64   `json:"longitude"`
+	RRule        *string    `json:"rrule"`
+	Visibility   string     `json:"visibility"`
+	// InviteReminderDays is optional; see events.Event.InviteReminderDays.
+	InviteReminderDays *int `json:"invite_reminder_days"`
+}
+
+type joinEventRequest struct {
+	Status string `json:"status"`
+	// Force, when true, joins as StatusGoing even if it overlaps with an
+	// event the user is already StatusGoing to; see events.ErrScheduleConflict.
+	Force bool `json:"force"`
+}
+
+func (s *Server) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
+	organizerID, _ := userIDFromContext(r.Context())
+
+	var req createEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	event, err := s.events.Create(r.Context(), events.CreateInput{
+		OrganizerID:        organizerID,
+		Title:              req.Title,
+		Description:        req.Description,
+		Location:           req.Location,
+		StartsAt:           req.StartsAt,
+		EndsAt:             req.EndsAt,
+		RSVPDeadline:       req.RSVPDeadline,
+		Capacity:           req.Capacity,
+		MinAttendees:       req.MinAttendees,
+		Latitude:           req.Latitude,
+		Longitude:          req.Longitude,
+		RRule:              req.RRule,
+		Visibility:         req.Visibility,
+		InviteReminderDays: req.InviteReminderDays,
+	})
+	if err != nil {
+		if errors.Is(err, apperr.ErrValidation) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not create event")
+		return
+	}
+
+	s.recordAudit(r, event.ID, &organizerID, "event_created", "created "+event.Title)
+
+	writeJSON(w, http.StatusCreated, event)
+}
+
+type eventListResponse struct {
+	Events     []*events.Event `json:"events"`
+	Total      int             `json:"total"`
+	Limit      int             `json:"limit"`
+	Offset     int             `json:"offset"`
+	NextOffset *int            `json:"next_offset,omitempty"`
+}
+
+func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	includeArchived := r.URL.Query().Get("include") == "archived"
+
+	list, total, err := s.events.List(r.Context(), limit, offset, includeArchived)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list events")
+		return
+	}
+
+	etag, err := etagFromEventList(list, total, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not compute etag")
+		return
+	}
+	if checkETag(w, r, etag) {
+		return
+	}
+
+	sw := render.NewStreamWriter(w, http.StatusOK)
+	sw.ArrayField("events", len(list), func(i int) any { return list[i] })
+	sw.Field("total", total)
+	sw.Field("limit", limit)
+	sw.Field("offset", offset)
+	if next := offset + len(list); next < total {
+		sw.Field("next_offset", next)
+	}
+	sw.Close()
+}
+
+func (s *Server) handleSearchEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	limit, offset := paginationParams(r)
+
+	list, total, err := s.events.Search(r.Context(), q, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not search events")
+		return
+	}
+
+	resp := eventListResponse{Events: list, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(list); next < total {
+		resp.NextOffset = &next
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleGetEvent fetches a single event by ID. Authentication is optional:
+// an authenticated caller's user ID is used to authorize access to private
+// events, but public and unlisted events are reachable anonymously.
+func (s *Server) handleGetEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var viewerID *int64
+	if uid, ok := userIDFromContext(r.Context()); ok {
+		viewerID = &uid
+	}
+
+	event, err := s.events.GetForViewer(r.Context(), id, viewerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, events.ErrNotFound), errors.Is(err, events.ErrForbidden):
+			// A private event reports the same "not found" to an
+			// unauthorized caller as a nonexistent one, so its existence
+			// isn't leaked.
+			writeError(w, http.StatusNotFound, "event not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not fetch event")
+		}
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not encode event")
+		return
+	}
+	if checkETag(w, r, etagFromBytes(body)) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func (s *Server) handleJoinEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req joinEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Status == "" {
+		req.Status = events.StatusGoing
+	}
+
+	recorded, err := s.events.JoinEventWithReferral(r.Context(), id, userID, req.Status, r.URL.Query().Get("ref"), req.Force)
+	if err != nil {
+		switch {
+		case errors.Is(err, events.ErrNotFound), errors.Is(err, events.ErrForbidden):
+			// A private event reports the same "not found" to a caller
+			// without access as a nonexistent one, matching handleGetEvent.
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, events.ErrRSVPDeadlinePassed):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, events.ErrBanned):
+			writeError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, events.ErrScheduleConflict):
+			conflicts, cerr := s.events.ConflictingEvents(r.Context(), userID, id)
+			if cerr != nil {
+				writeError(w, http.StatusInternalServerError, "could not join event")
+				return
+			}
+			writeJSON(w, http.StatusConflict, scheduleConflictResponse{Error: err.Error(), Conflicts: conflicts})
+		default:
+			writeError(w, http.StatusInternalServerError, "could not join event")
+		}
+		return
+	}
+
+	if event, gerr := s.events.Get(r.Context(), id); gerr == nil && event.OrganizerID != userID {
+		s.notify(r.Context(), event.OrganizerID, "rsvp_changed", "New RSVP on "+event.Title, "Someone responded "+recorded+" to your event.", &id)
+		s.publishRealtime(event.OrganizerID, "attendee_joined", map[string]interface{}{"event_id": id, "user_id": userID, "status": recorded})
+		s.notifySlack(r.Context(), id, fmt.Sprintf(":wave: Someone responded *%s* to *%s*.", recorded, event.Title))
+	}
+	s.publishAttendeeCount(r.Context(), id)
+	s.recordAudit(r, id, &userID, "attendee_joined", "responded "+recorded)
+
+	if recorded == events.StatusGoing {
+		s.syncGoogleCalendar(r.Context(), userID, id)
+		s.syncOutlookCalendar(r.Context(), userID, id)
+	} else {
+		s.unsyncGoogleCalendar(r.Context(), userID, id)
+		s.unsyncOutlookCalendar(r.Context(), userID, id)
+	}
+
+	writeJSON(w, http.StatusOK, joinEventResponse{Status: recorded})
+}
+
+// scheduleConflictResponse is returned with StatusConflict when joining as
+// StatusGoing would overlap with an event the caller is already going to;
+// resubmitting with force=true joins anyway.
+type scheduleConflictResponse struct {
+	Error     string                    `json:"error"`
+	Conflicts []events.ScheduleConflict `json:"conflicts"`
+}
+
+type joinEventResponse struct {
+	Status string `json:"status"`
+}
+
+type updateEventRequest struct {
+	Title        *string     `json:"title"`
+	Description  *string     `json:"description"`
+	Location     *string     `json:"location"`
+	StartsAt     *time.Time  `json:"starts_at"`
+	EndsAt       **time.Time `json:"ends_at"`
+	RSVPDeadline **time.Time `json:"rsvp_deadline"`
+	Capacity     **int       `json:"capacity"`
+	MinAttendees **int       `json:"min_attendees"`
+	Latitude     **float64   `json:"latitude"`
+	Longitude    **float64   `json:"longitude"`
+	RRule        **string    `json:"rrule"`
+	Visibility   *string     `json:"visibility"`
+	// InviteReminderDays is optional; see events.Event.InviteReminderDays.
+	InviteReminderDays **int `json:"invite_reminder_days"`
+}
+
+// handleUpdateEvent applies a partial update to an event: a field absent
+// from the request body is left unchanged, and for fields that are
+// themselves optional (ends_at, rsvp_deadline, capacity, min_attendees,
+// latitude, longitude, rrule) an explicit JSON null clears it, distinct
+// from omitting the key. Only the organizer may update an event.
+func (s *Server) handleUpdateEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can update this event")
+		return
+	}
+
+	var req updateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	updated, err := s.events.Update(r.Context(), id, events.UpdateInput{
+		Title:              req.Title,
+		Description:        req.Description,
+		Location:           req.Location,
+		StartsAt:           req.StartsAt,
+		EndsAt:             req.EndsAt,
+		RSVPDeadline:       req.RSVPDeadline,
+		Capacity:           req.Capacity,
+		MinAttendees:       req.MinAttendees,
+		Latitude:           req.Latitude,
+		Longitude:          req.Longitude,
+		RRule:              req.RRule,
+		Visibility:         req.Visibility,
+		InviteReminderDays: req.InviteReminderDays,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrValidation):
+			writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not update event")
+		}
+		return
+	}
+
+	if attendees, aerr := s.events.GetEventAttendees(r.Context(), id); aerr == nil {
+		for _, a := range attendees.Attendees {
+			if a.Status == events.StatusGoing {
+				s.syncGoogleCalendar(r.Context(), a.UserID, id)
+			}
+			if a.UserID == userID {
+				continue
+			}
+			s.notify(r.Context(), a.UserID, "event_updated", updated.Title+" was updated", "The organizer updated details for an event you're attending.", &id)
+			s.publishRealtime(a.UserID, "event_updated", map[string]interface{}{"event_id": id, "title": updated.Title})
+		}
+	}
+	s.recordAudit(r, id, &userID, "event_updated", "updated "+updated.Title)
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// handleCancelEvent marks an event cancelled instead of deleting it,
+// keeping its history intact. Only the organizer may cancel an event.
+func (s *Server) handleCancelEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can cancel this event")
+		return
+	}
+
+	attendeeIDs, err := s.events.CancelEvent(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not cancel event")
+		return
+	}
+	for _, attendeeID := range attendeeIDs {
+		s.notify(r.Context(), attendeeID, "event_cancelled", event.Title+" was cancelled", "The organizer cancelled an event you were attending.", &id)
+		s.notifySMS(r.Context(), attendeeID, fmt.Sprintf("%s has been cancelled by the organizer.", event.Title))
+		s.unsyncGoogleCalendar(r.Context(), attendeeID, id)
+		s.unsyncOutlookCalendar(r.Context(), attendeeID, id)
+	}
+	s.notifySlack(r.Context(), id, fmt.Sprintf(":x: *%s* has been cancelled by the organizer.", event.Title))
+	s.recordAudit(r, id, &userID, "event_cancelled", "cancelled "+event.Title)
+
+	if err := s.tickets.RefundTicketsForEvent(r.Context(), id); err != nil {
+		writeError(w, http.StatusInternalServerError, "event cancelled, but could not refund all tickets")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type duplicateEventRequest struct {
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+// handleDuplicateEvent clones an event's title, description, location, and
+// settings into a new draft at a new date, carrying over no attendees.
+// Only the organizer may duplicate an event.
+func (s *Server) handleDuplicateEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can duplicate this event")
+		return
+	}
+
+	var req duplicateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	duplicate, err := s.events.Duplicate(r.Context(), id, req.StartsAt, req.EndsAt)
+	if err != nil {
+		if errors.Is(err, events.ErrEndBeforeStart) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not duplicate event")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, duplicate)
+}
+
+type transferEventRequest struct {
+	NewOrganizerID int64 `json:"new_organizer_id"`
+}
+
+// handleTransferEvent makes another attendee the organizer of an event.
+// Only the current organizer may transfer it, and the new organizer must
+// already be an attendee.
+func (s *Server) handleTransferEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can transfer this event")
+		return
+	}
+
+	var req transferEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	updated, err := s.events.TransferOwnership(r.Context(), id, req.NewOrganizerID)
+	if err != nil {
+		if errors.Is(err, events.ErrNotAttendee) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not transfer event")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *Server) handleFrequentCoattendees(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	ranked, err := s.events.FrequentCoattendees(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not compute frequent coattendees")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ranked)
+}
+
+func (s *Server) handleLeaveEvent(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	if err := s.events.LeaveEvent(r.Context(), id, userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not leave event")
+		return
+	}
+
+	if event, gerr := s.events.Get(r.Context(), id); gerr == nil && event.OrganizerID != userID {
+		s.notify(r.Context(), event.OrganizerID, "rsvp_changed", "An attendee left "+event.Title, "An attendee is no longer going to your event.", &id)
+	}
+	s.publishAttendeeCount(r.Context(), id)
+	s.unsyncGoogleCalendar(r.Context(), userID, id)
+	s.unsyncOutlookCalendar(r.Context(), userID, id)
+	s.recordAudit(r, id, &userID, "attendee_left", "left the event")
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/events/eventstest"
+)
+
+func TestHandleGetEvent_NotFound(t *testing.T) {
+	mock := &eventstest.MockRepository{
+		GetForViewerFunc: func(ctx context.Context, id int64, viewerID *int64) (*events.Event, error) {
+			return nil, events.ErrNotFound
+		},
+	}
+	s := &Server{events: mock}
+
+	req := httptest.NewRequest("GET", "/events/42", nil)
+	req.SetPathValue("id", "42")
+	rec := httptest.NewRecorder()
+
+	s.handleGetEvent(rec, req)
+
+	if rec.Code != 404 {
+		t.Fatalf("got status %d, want 404", rec.Code)
+	}
+}
+
+func TestHandleGetEvent_Found(t *testing.T) {
+	mock := &eventstest.MockRepository{
+		GetForViewerFunc: func(ctx context.Context, id int64, viewerID *int64) (*events.Event, error) {
+			return &events.Event{ID: id, Title: "Launch party"}, nil
+		},
+	}
+	s := &Server{events: mock}
+
+	req := httptest.NewRequest("GET", "/events/42", nil)
+	req.SetPathValue("id", "42")
+	rec := httptest.NewRecorder()
+
+	s.handleGetEvent(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("got status %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// handleEventStats serves GET /events/{id}/stats: attendee counts by
+// status, invitation acceptance rate, check-in count, and a day-by-day RSVP
+// timeline. Only the event's organizer or an accepted collaborator may view
+// it.
+func (s *Server) handleEventStats(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	canManage, err := s.invitations.CanManage(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not check permissions")
+		return
+	}
+	if !canManage {
+		writeError(w, http.StatusForbidden, "only the organizer or a collaborator can view stats")
+		return
+	}
+
+	stats, err := s.events.AttendanceStats(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not compute stats")
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
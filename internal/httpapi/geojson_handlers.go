@@ -0,0 +1,116 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+var errInvalidNear = errors.New("near must be \"lat,lng,radius_km\"")
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONPoint           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// handleEventsGeoJSON serves GET /events.geojson, a GeoJSON FeatureCollection
+// of every geocoded event, optionally filtered by ?near=lat,lng,radius_km.
+func (s *Server) handleEventsGeoJSON(w http.ResponseWriter, r *http.Request) {
+	near, err := parseNearFilter(r.URL.Query().Get("near"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list, err := s.events.ListGeocoded(r.Context(), near)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list events")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	writeJSON(w, http.StatusOK, buildFeatureCollection(list))
+}
+
+// buildFeatureCollection converts geocoded events into a GeoJSON
+// FeatureCollection. Callers are expected to have already filtered out
+// events without coordinates (see Store.ListGeocoded).
+func buildFeatureCollection(list []*events.Event) geoJSONFeatureCollection {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection"}
+	for _, e := range list {
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type: "Feature",
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: []float64{*e.Longitude, *e.Latitude},
+			},
+			Properties: map[string]interface{}{
+				"id":    e.ID,
+				"title": e.Title,
+				"date":  e.StartsAt,
+			},
+		})
+	}
+	return collection
+}
+
+// parseNearFilter parses a "lat,lng,radius_km" query value. An empty value
+// returns a nil filter.
+func parseNearFilter(raw string) (*events.NearFilter, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	if len(parts) != 3 {
+		return nil, errInvalidNear
+	}
+
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lng, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	radius, err3 := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, errInvalidNear
+	}
+
+	return &events.NearFilter{Latitude: lat, Longitude: lng, RadiusKm: radius}, nil
+}
+
+type nearbyEventsResponse struct {
+	Events []events.NearbyEvent `json:"events"`
+}
+
+// handleNearbyEvents serves GET /events/nearby?lat=&lng=&radius_km=, a
+// plain JSON list of geocoded events within radius_km of (lat, lng),
+// nearest first, each annotated with its distance.
+func (s *Server) handleNearbyEvents(w http.ResponseWriter, r *http.Request) {
+	lat, latErr := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(r.URL.Query().Get("lng"), 64)
+	radiusKm, radiusErr := strconv.ParseFloat(r.URL.Query().Get("radius_km"), 64)
+	if latErr != nil || lngErr != nil || radiusErr != nil || radiusKm <= 0 {
+		writeError(w, http.StatusBadRequest, "lat, lng, and a positive radius_km are required")
+		return
+	}
+
+	list, err := s.events.ListNearby(r.Context(), lat, lng, radiusKm)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list nearby events")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, nearbyEventsResponse{Events: list})
+}
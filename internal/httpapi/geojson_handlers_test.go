@@ -0,0 +1,33 @@
+package httpapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+func TestBuildFeatureCollection_ValidGeoJSON(t *testing.T) {
+	lat, lng := 40.7128, -74.0060
+	e := &events.Event{ID: 1, Title: "Launch party", StartsAt: time.Now(), Latitude: &lat, Longitude: &lng}
+
+	collection := buildFeatureCollection([]*events.Event{e})
+
+	if collection.Type != "FeatureCollection" {
+		t.Fatalf("got type %q, want FeatureCollection", collection.Type)
+	}
+	if len(collection.Features) != 1 {
+		t.Fatalf("got %d features, want 1", len(collection.Features))
+	}
+
+	f := collection.Features[0]
+	if f.Type != "Feature" || f.Geometry.Type != "Point" {
+		t.Fatalf("got feature %+v, want a Point feature", f)
+	}
+	if f.Geometry.Coordinates[0] != lng || f.Geometry.Coordinates[1] != lat {
+		t.Fatalf("got coordinates %v, want [lng, lat] = [%f, %f]", f.Geometry.Coordinates, lng, lat)
+	}
+	if f.Properties["id"] != e.ID || f.Properties["title"] != e.Title {
+		t.Fatalf("got properties %+v, missing expected id/title", f.Properties)
+	}
+}
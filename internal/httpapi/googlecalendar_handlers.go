@@ -0,0 +1,78 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/dohasheriff/tools-3/internal/googlecalendar"
+)
+
+// handleConnectGoogleCalendar returns the URL the caller's browser should
+// be sent to in order to grant this account access to their Google
+// Calendar. Unlike the login OAuth start endpoint, the caller is already
+// authenticated, so this returns JSON rather than redirecting: the client
+// holds a bearer token it cannot attach to a plain browser navigation, so
+// it fetches the URL via XHR and navigates there itself.
+func (s *Server) handleConnectGoogleCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	authURL, err := s.googleCalendar.StartConnect(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not start google calendar connection")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"authorize_url": authURL})
+}
+
+// handleGoogleCalendarCallback completes a connection started by
+// handleConnectGoogleCalendar. It doesn't issue any session tokens, so it
+// just reports success or failure.
+func (s *Server) handleGoogleCalendarCallback(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	code := r.URL.Query().Get("code")
+
+	err := s.googleCalendar.CompleteConnect(r.Context(), state, code)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, googlecalendar.ErrInvalidState):
+		writeError(w, http.StatusBadRequest, "invalid or expired state")
+	case errors.Is(err, googlecalendar.ErrNoRefreshToken):
+		writeError(w, http.StatusBadRequest, "google did not grant offline access; try again")
+	default:
+		writeError(w, http.StatusInternalServerError, "could not complete google calendar connection")
+	}
+}
+
+// handleDisconnectGoogleCalendar removes the caller's Google Calendar
+// connection.
+func (s *Server) handleDisconnectGoogleCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	if err := s.googleCalendar.Disconnect(r.Context(), userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not disconnect google calendar")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// syncGoogleCalendar pushes eventID into userID's connected Google
+// Calendar, best-effort: a sync failure (including a detected conflict)
+// never fails the RSVP or edit that triggered it.
+func (s *Server) syncGoogleCalendar(ctx context.Context, userID, eventID int64) {
+	if err := s.googleCalendar.PushEvent(ctx, userID, eventID); err != nil {
+		log.Printf("sync google calendar event %d for user %d: %v", eventID, userID, err)
+	}
+}
+
+// unsyncGoogleCalendar removes eventID from userID's connected Google
+// Calendar, best-effort.
+func (s *Server) unsyncGoogleCalendar(ctx context.Context, userID, eventID int64) {
+	if err := s.googleCalendar.RemoveEvent(ctx, userID, eventID); err != nil {
+		log.Printf("remove google calendar event %d for user %d: %v", eventID, userID, err)
+	}
+}
@@ -0,0 +1,63 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/db/migrations"
+)
+
+// healthCheckTimeout bounds how long a readiness check waits on the
+// database before reporting unready, so a stalled connection doesn't hang
+// the probe indefinitely.
+const healthCheckTimeout = 3 * time.Second
+
+type healthStatus struct {
+	Status     string            `json:"status"`
+	Components map[string]string `json:"components"`
+}
+
+// handleLiveness serves GET /healthz: whether the process is up and
+// serving requests at all, with no dependency checks. An orchestrator
+// uses this to decide whether to restart the container.
+func (s *Server) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthStatus{Status: "ok", Components: map[string]string{"server": "ok"}})
+}
+
+// handleReadiness serves GET /readyz: whether the process is ready to
+// serve traffic, i.e. its database is reachable and its schema is fully
+// migrated. An orchestrator uses this to decide whether to route traffic
+// to the instance.
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	components := map[string]string{}
+	ready := true
+
+	if err := s.db.PingContext(ctx); err != nil {
+		components["database"] = err.Error()
+		ready = false
+	} else {
+		components["database"] = "ok"
+	}
+
+	if pending, err := migrations.Pending(ctx, s.db); err != nil {
+		components["migrations"] = err.Error()
+		ready = false
+	} else if pending > 0 {
+		components["migrations"] = "pending migrations not applied"
+		ready = false
+	} else {
+		components["migrations"] = "ok"
+	}
+
+	status := healthStatus{Status: "ok", Components: components}
+	if !ready {
+		status.Status = "unhealthy"
+		writeJSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,217 @@
+// Package httpapi wires the domain services into HTTP handlers.
+package httpapi
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/audit"
+	"github.com/dohasheriff/tools-3/internal/auth"
+	"github.com/dohasheriff/tools-3/internal/comments"
+	"github.com/dohasheriff/tools-3/internal/digest"
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/googlecalendar"
+	"github.com/dohasheriff/tools-3/internal/invitations"
+	"github.com/dohasheriff/tools-3/internal/msgraphcalendar"
+	"github.com/dohasheriff/tools-3/internal/notifications"
+	"github.com/dohasheriff/tools-3/internal/polls"
+	"github.com/dohasheriff/tools-3/internal/realtime"
+	"github.com/dohasheriff/tools-3/internal/reminders"
+	"github.com/dohasheriff/tools-3/internal/render"
+	"github.com/dohasheriff/tools-3/internal/slack"
+	"github.com/dohasheriff/tools-3/internal/tickets"
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// Server holds the dependencies shared by HTTP handlers.
+type Server struct {
+	db              *sql.DB
+	auth            *auth.Service
+	audit           *audit.Store
+	events          events.Repository
+	comments        *comments.Store
+	tickets         *tickets.Store
+	invitations     invitations.Repository
+	polls           *polls.Store
+	notifications   *notifications.Store
+	reminders       *reminders.Store
+	digest          *digest.Store
+	realtime        *realtime.Hub
+	eventStream     *sseHub
+	slack           *slack.Store
+	googleCalendar  *googlecalendar.Store
+	outlookCalendar *msgraphcalendar.Store
+	loginLimiter    *ipRateLimiter
+	searchLimiter   *ipRateLimiter
+	requestTimeout  time.Duration
+}
+
+// NewServer returns a Server wiring the given services into routes.
+// requestTimeout bounds how long a non-streaming request may run; see
+// withRequestTimeout.
+func NewServer(db *sql.DB, authSvc *auth.Service, auditStore *audit.Store, eventStore events.Repository, commentStore *comments.Store, ticketStore *tickets.Store, invitationStore invitations.Repository, pollStore *polls.Store, notificationStore *notifications.Store, reminderStore *reminders.Store, digestStore *digest.Store, realtimeHub *realtime.Hub, slackStore *slack.Store, googleCalendarStore *googlecalendar.Store, outlookCalendarStore *msgraphcalendar.Store, requestTimeout time.Duration) *Server {
+	return &Server{db: db, auth: authSvc, audit: auditStore, events: eventStore, comments: commentStore, tickets: ticketStore, invitations: invitationStore, polls: pollStore, notifications: notificationStore, reminders: reminderStore, digest: digestStore, realtime: realtimeHub, eventStream: newSSEHub(), slack: slackStore, googleCalendar: googleCalendarStore, outlookCalendar: outlookCalendarStore, loginLimiter: newIPRateLimiter(loginRateLimitWindow, loginRateLimitMax), searchLimiter: newIPRateLimiter(searchRateLimitWindow, searchRateLimitMax), requestTimeout: requestTimeout}
+}
+
+// Router builds the HTTP handler for the whole API.
+func (s *Server) Router() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /register", s.handleRegister)
+	mux.HandleFunc("POST /login", s.handleLogin)
+	mux.HandleFunc("POST /auth/refresh", s.handleRefresh)
+	mux.HandleFunc("POST /auth/logout", s.requireAuth(s.handleLogout))
+	mux.HandleFunc("POST /auth/forgot-password", s.handleForgotPassword)
+	mux.HandleFunc("POST /auth/reset-password", s.handleResetPassword)
+	mux.HandleFunc("GET /auth/verify", s.handleVerifyEmail)
+	mux.HandleFunc("GET /auth/oauth/{provider}/start", s.handleOAuthStart)
+	mux.HandleFunc("GET /auth/oauth/{provider}/callback", s.handleOAuthCallback)
+	mux.HandleFunc("GET /.well-known/jwks.json", s.handleJWKS)
+	mux.HandleFunc("GET /healthz", s.handleLiveness)
+	mux.HandleFunc("GET /readyz", s.handleReadiness)
+	mux.HandleFunc("GET /openapi.json", s.handleOpenAPISpec)
+	mux.HandleFunc("GET /docs", s.handleDocs)
+	mux.HandleFunc("POST /auth/2fa/enable", s.requireAuth(s.handleEnableTOTP))
+	mux.HandleFunc("POST /auth/2fa/verify", s.handleVerifyTOTP)
+	mux.HandleFunc("DELETE /users/me", s.requireAuth(s.handleDeleteAccount))
+	mux.HandleFunc("PATCH /users/me", s.requireAuth(s.handleUpdateProfile))
+	mux.HandleFunc("PUT /users/me/avatar", s.requireAuth(s.handleUploadAvatar))
+	mux.HandleFunc("PUT /users/me/phone", s.requireAuth(s.handleSetPhoneNumber))
+	mux.HandleFunc("POST /users/me/phone/verify", s.requireAuth(s.handleVerifyPhone))
+	mux.HandleFunc("PUT /users/me/reminder-lead-times", s.requireAuth(s.handleSetReminderLeadTimes))
+	mux.HandleFunc("POST /users/me/digest-subscription", s.requireAuth(s.handleSubscribeDigest))
+	mux.HandleFunc("DELETE /users/me/digest-subscription", s.requireAuth(s.handleUnsubscribeDigest))
+	mux.HandleFunc("GET /auth/sessions", s.requireAuth(s.handleListSessions))
+	mux.HandleFunc("DELETE /auth/sessions/{id}", s.requireAuth(s.handleRevokeSession))
+	mux.HandleFunc("POST /auth/api-keys", s.requireAuth(s.handleCreateAPIKey))
+	mux.HandleFunc("GET /api/events", s.requireAPIKey(auth.ScopeEventsRead, s.handleListEvents))
+	mux.HandleFunc("POST /auth/magic-link", s.handleRequestMagicLink)
+	mux.HandleFunc("GET /auth/magic-link/verify", s.handleVerifyMagicLink)
+
+	mux.HandleFunc("POST /availability", s.requireAuth(s.handleAvailability))
+	mux.HandleFunc("POST /events", s.requireAuth(s.handleCreateEvent))
+	mux.HandleFunc("POST /events/bulk", s.requireAuth(s.handleBulkEvents))
+	mux.HandleFunc("GET /events", s.handleListEvents)
+	mux.HandleFunc("GET /events.geojson", s.handleEventsGeoJSON)
+	mux.HandleFunc("GET /events/nearby", s.handleNearbyEvents)
+	mux.HandleFunc("GET /events/trending", s.handleTrendingEvents)
+	mux.HandleFunc("GET /events/search", s.handleSearchEvents)
+	mux.HandleFunc("GET /events/{id}", s.optionalAuth(s.handleGetEvent))
+	mux.HandleFunc("GET /events/{id}/stream", s.handleEventStream)
+	mux.HandleFunc("GET /events/{id}/export.ics", s.optionalAuth(s.handleExportICS))
+	mux.HandleFunc("PATCH /events/{id}", s.requireAuth(s.handleUpdateEvent))
+	mux.HandleFunc("DELETE /events/{id}", s.requireAuth(s.handleDeleteEvent))
+	mux.HandleFunc("POST /events/{id}/restore", s.requireAuth(s.handleRestoreEvent))
+	mux.HandleFunc("POST /events/{id}/cancel", s.requireAuth(s.handleCancelEvent))
+	mux.HandleFunc("POST /events/{id}/duplicate", s.requireAuth(s.handleDuplicateEvent))
+	mux.HandleFunc("POST /events/{id}/transfer", s.requireAuth(s.handleTransferEvent))
+	mux.HandleFunc("POST /events/{id}/join", s.requireAuth(s.handleJoinEvent))
+	mux.HandleFunc("POST /events/{id}/leave", s.requireAuth(s.handleLeaveEvent))
+	mux.HandleFunc("GET /events/{id}/attendees", s.requireAuth(s.handleListAttendees))
+	mux.HandleFunc("DELETE /events/{id}/attendees/{user_id}", s.requireAuth(s.handleRemoveAttendee))
+	mux.HandleFunc("POST /events/{id}/bans", s.requireAuth(s.handleBanAttendee))
+	mux.HandleFunc("GET /me/frequent-coattendees", s.requireAuth(s.handleFrequentCoattendees))
+	mux.HandleFunc("POST /events/{id}/checkin", s.requireAuth(s.handleCheckIn))
+	mux.HandleFunc("GET /events/{id}/checkin-log", s.requireAuth(s.handleCheckInLog))
+	mux.HandleFunc("GET /events/{id}/attendees/{user_id}/qr", s.requireAuth(s.handleAttendeeQR))
+	mux.HandleFunc("GET /events/{id}/attendees/export", s.requireAuth(s.handleExportAttendees))
+	mux.HandleFunc("GET /events/{id}/stats", s.requireAuth(s.handleEventStats))
+	mux.HandleFunc("GET /events/{id}/audit", s.requireAuth(s.handleGetEventAudit))
+	mux.HandleFunc("GET /events/{id}/referrals", s.requireAuth(s.handleEventReferrals))
+	mux.HandleFunc("POST /events/{id}/share-link", s.requireAuth(s.handleGenerateShareLink))
+	mux.HandleFunc("DELETE /events/{id}/share-link", s.requireAuth(s.handleDisableShareLink))
+	mux.HandleFunc("GET /share/{code}", s.handleGetEventByShareCode)
+	mux.HandleFunc("POST /share/{code}/join", s.requireAuth(s.handleJoinViaShareCode))
+	mux.HandleFunc("POST /events/{id}/bookmark", s.requireAuth(s.handleBookmarkEvent))
+	mux.HandleFunc("DELETE /events/{id}/bookmark", s.requireAuth(s.handleUnbookmarkEvent))
+	mux.HandleFunc("GET /events/my/bookmarks", s.requireAuth(s.handleListBookmarks))
+	mux.HandleFunc("GET /events/my/duration-stats", s.requireAuth(s.handleDurationStats))
+	mux.HandleFunc("GET /events/{id}/occurrences", s.handleListOccurrences)
+	mux.HandleFunc("POST /events/{id}/occurrences/cancel", s.requireAuth(s.handleCancelOccurrence))
+	mux.HandleFunc("POST /events/{id}/occurrences/reschedule", s.requireAuth(s.handleRescheduleOccurrence))
+	mux.HandleFunc("POST /events/{id}/comments", s.requireAuth(s.handleCreateComment))
+	mux.HandleFunc("GET /events/{id}/comments", s.optionalAuth(s.handleListComments))
+	mux.HandleFunc("DELETE /comments/{id}", s.requireAuth(s.handleDeleteComment))
+
+	mux.HandleFunc("POST /events/{id}/invitations", s.requireAuth(s.handleCreateInvitation))
+	mux.HandleFunc("GET /events/{id}/invitations", s.requireAuth(s.handleListEventInvitations))
+	mux.HandleFunc("GET /invitations", s.requireAuth(s.handleListMyInvitations))
+	mux.HandleFunc("GET /invitations/my/count", s.requireAuth(s.handleMyInvitationCount))
+	mux.HandleFunc("GET /invitations/accept", s.handleAcceptInvitationByToken)
+	mux.HandleFunc("GET /invitations/decline", s.handleDeclineInvitationByToken)
+	mux.HandleFunc("POST /invitations/{id}/accept", s.requireAuth(s.handleAcceptInvitation))
+	mux.HandleFunc("POST /invitations/{id}/decline", s.requireAuth(s.handleDeclineInvitation))
+	mux.HandleFunc("DELETE /invitations/{id}", s.requireAuth(s.handleRevokeInvitation))
+	mux.HandleFunc("PUT /events/{id}/invitation-template", s.requireAuth(s.handleSetInvitationTemplate))
+	mux.HandleFunc("PUT /events/{id}/slack-webhook", s.requireAuth(s.handleSetSlackWebhook))
+
+	mux.HandleFunc("GET /integrations/google-calendar/connect", s.requireAuth(s.handleConnectGoogleCalendar))
+	mux.HandleFunc("GET /integrations/google-calendar/callback", s.handleGoogleCalendarCallback)
+	mux.HandleFunc("DELETE /integrations/google-calendar", s.requireAuth(s.handleDisconnectGoogleCalendar))
+
+	mux.HandleFunc("GET /integrations/outlook-calendar/connect", s.requireAuth(s.handleConnectOutlookCalendar))
+	mux.HandleFunc("GET /integrations/outlook-calendar/callback", s.handleOutlookCalendarCallback)
+	mux.HandleFunc("DELETE /integrations/outlook-calendar", s.requireAuth(s.handleDisconnectOutlookCalendar))
+
+	mux.HandleFunc("GET /notifications", s.requireAuth(s.handleListNotifications))
+	mux.HandleFunc("POST /notifications/{id}/read", s.requireAuth(s.handleMarkNotificationRead))
+	mux.HandleFunc("POST /notifications/read-all", s.requireAuth(s.handleMarkAllNotificationsRead))
+	mux.HandleFunc("POST /notifications/devices", s.requireAuth(s.handleRegisterDevice))
+	mux.HandleFunc("GET /ws", s.handleWebSocket)
+
+	mux.HandleFunc("POST /events/{id}/ticket-types", s.requireAuth(s.handleCreateTicketType))
+	mux.HandleFunc("GET /events/{id}/ticket-types", s.handleListTicketTypes)
+	mux.HandleFunc("POST /events/{id}/tickets", s.requireAuth(s.handleClaimTicket))
+	mux.HandleFunc("GET /events/{id}/tickets", s.requireAuth(s.handleListTickets))
+	mux.HandleFunc("POST /events/{id}/tickets/validate", s.requireAuth(s.handleValidateTicket))
+	mux.HandleFunc("POST /webhooks/stripe", s.handleStripeWebhook)
+
+	mux.HandleFunc("POST /polls", s.requireAuth(s.handleCreatePoll))
+	mux.HandleFunc("GET /polls/{id}", s.handleGetPoll)
+	mux.HandleFunc("POST /polls/{id}/vote", s.requireAuth(s.handleVotePoll))
+	mux.HandleFunc("POST /polls/{id}/finalize", s.requireAuth(s.handleFinalizePoll))
+
+	mux.HandleFunc("GET /users/search", s.requireAuth(s.handleSearchUsers))
+
+	mux.HandleFunc("POST /calendar/feed-token", s.requireAuth(s.handleCreateFeedToken))
+	mux.HandleFunc("DELETE /calendar/feed-token", s.requireAuth(s.handleRevokeFeedToken))
+	mux.HandleFunc("GET /calendar/feed.ics", s.handleCalendarFeed)
+
+	mux.HandleFunc("GET /admin/audit-logs", s.requireRole(users.RoleAdmin, s.handleListAuditLogs))
+
+	return s.logRequests(s.withRequestTimeout(s.compressResponses(mux)))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	render.JSON(w, status, v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	render.Err(w, status, msg)
+}
+
+// paginationParams reads "limit" and "offset" query parameters, applying
+// sane defaults and an upper bound on limit.
+func paginationParams(r *http.Request) (limit, offset int) {
+	limit = defaultPageLimit
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+		limit = v
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	offset = 0
+	if v, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && v >= 0 {
+		offset = v
+	}
+
+	return limit, offset
+}
@@ -0,0 +1,59 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/icalendar"
+)
+
+// handleExportICS serves GET /events/{id}/export.ics, a single-event
+// iCalendar feed an attendee can add directly to Apple/Google/Outlook.
+func (s *Server) handleExportICS(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var viewerID *int64
+	if uid, ok := userIDFromContext(r.Context()); ok {
+		viewerID = &uid
+	}
+
+	event, err := s.events.GetForViewer(r.Context(), id, viewerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound), errors.Is(err, apperr.ErrForbidden):
+			writeError(w, http.StatusNotFound, "event not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not fetch event")
+		}
+		return
+	}
+
+	organizer, err := s.auth.GetUser(r.Context(), event.OrganizerID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+
+	ics := icalendar.Build(icalendar.MethodPublish, icalendar.Event{
+		UID:            fmt.Sprintf("event-%d@tools-3", event.ID),
+		Title:          event.Title,
+		Description:    event.Description,
+		Location:       event.Location,
+		StartsAt:       event.StartsAt,
+		EndsAt:         event.EndsAt,
+		OrganizerEmail: organizer.Email,
+		OrganizerName:  organizer.DisplayName,
+	})
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.ics"`, event.ID))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(ics)
+}
@@ -0,0 +1,376 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/auth"
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/invitations"
+)
+
+type createInvitationRequest struct {
+	InviteeEmail    string `json:"invitee_email"`
+	InviteeUserID   int64  `json:"invitee_user_id"`
+	InviteeUsername string `json:"invitee_username"`
+	Role            string `json:"role"`
+}
+
+// handleCreateInvitation invites a user to an event, identifying the
+// invitee by exactly one of invitee_email (an external address),
+// invitee_user_id, or invitee_username (a registered account looked up
+// server-side); see invitations.Store.InviteUserToEvent,
+// InviteRegisteredUser, and InviteByUsername respectively. The caller must
+// be the event's organizer or an accepted collaborator; see
+// invitations.Store.CanManage.
+func (s *Server) handleCreateInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req createInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	given := 0
+	for _, v := range []bool{req.InviteeEmail != "", req.InviteeUserID != 0, req.InviteeUsername != ""} {
+		if v {
+			given++
+		}
+	}
+	if given != 1 {
+		writeError(w, http.StatusBadRequest, "exactly one of invitee_email, invitee_user_id, or invitee_username is required")
+		return
+	}
+	if req.Role == "" {
+		req.Role = invitations.RoleAttendee
+	}
+
+	var inv *invitations.Invitation
+	switch {
+	case req.InviteeUserID != 0:
+		inv, err = s.invitations.InviteRegisteredUser(r.Context(), eventID, userID, req.InviteeUserID, req.Role)
+	case req.InviteeUsername != "":
+		inv, err = s.invitations.InviteByUsername(r.Context(), eventID, userID, req.InviteeUsername, req.Role)
+	default:
+		inv, err = s.invitations.InviteUserToEvent(r.Context(), eventID, userID, req.InviteeEmail, req.Role)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, invitations.ErrUserNotFound):
+			writeError(w, http.StatusNotFound, "user not found")
+		case errors.Is(err, apperr.ErrNotFound):
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, invitations.ErrForbidden):
+			writeError(w, http.StatusForbidden, "not permitted to invite attendees to this event")
+		case errors.Is(err, invitations.ErrInvalidRole):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, invitations.ErrAtCapacity):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, invitations.ErrEventInvitationQuotaExceeded), errors.Is(err, invitations.ErrInviterInvitationQuotaExceeded):
+			writeError(w, http.StatusTooManyRequests, err.Error())
+		case errors.Is(err, events.ErrRSVPDeadlinePassed):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not create invitation")
+		}
+		return
+	}
+
+	if inv.InviteeUserID != nil {
+		s.notify(r.Context(), *inv.InviteeUserID, "invitation", "You've been invited to an event", "You have a new event invitation awaiting your response.", &inv.EventID)
+		s.publishRealtime(*inv.InviteeUserID, "invitation_received", map[string]interface{}{"event_id": inv.EventID, "invitation_id": inv.ID})
+	}
+	s.recordAudit(r, inv.EventID, &userID, "invitation_sent", "invited "+inv.InviteeEmail)
+
+	writeJSON(w, http.StatusCreated, inv)
+}
+
+type setInvitationTemplateRequest struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// handleSetInvitationTemplate sets eventID's custom invitation email
+// template, or clears it (reverting to the default) if subject and body
+// are both empty; see invitations.Store.SetInvitationTemplate. The caller
+// must be the event's organizer or an accepted collaborator; see
+// invitations.Store.CanManage.
+func (s *Server) handleSetInvitationTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req setInvitationTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.invitations.SetInvitationTemplate(r.Context(), eventID, userID, req.Subject, req.Body); err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, invitations.ErrForbidden):
+			writeError(w, http.StatusForbidden, "not permitted to set this event's invitation template")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not set invitation template")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type invitationListResponse struct {
+	Invitations []*invitations.Invitation `json:"invitations"`
+	Total       int                       `json:"total"`
+	Limit       int                       `json:"limit"`
+	Offset      int                       `json:"offset"`
+	NextOffset  *int                      `json:"next_offset,omitempty"`
+}
+
+// handleListEventInvitations lists the invitations sent for an event,
+// newest first. The caller must be the event's organizer or an accepted
+// collaborator; see invitations.Store.CanManage.
+func (s *Server) handleListEventInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	eventID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	canManage, err := s.invitations.CanManage(r.Context(), eventID, userID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not check permissions")
+		return
+	}
+	if !canManage {
+		writeError(w, http.StatusForbidden, "only the organizer or a collaborator can view invitations")
+		return
+	}
+
+	limit, offset := paginationParams(r)
+	list, total, err := s.invitations.GetInvitationsByEventID(r.Context(), eventID, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list invitations")
+		return
+	}
+
+	resp := invitationListResponse{Invitations: list, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(list); next < total {
+		resp.NextOffset = &next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleListMyInvitations lists the invitations sent to the caller's email
+// address, newest first.
+func (s *Server) handleListMyInvitations(w http.ResponseWriter, r *http.Request) {
+	email, ok := auth.GetUserEmail(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing bearer token")
+		return
+	}
+
+	limit, offset := paginationParams(r)
+	list, total, err := s.invitations.GetInvitationsByEmail(r.Context(), email, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list invitations")
+		return
+	}
+
+	resp := invitationListResponse{Invitations: list, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(list); next < total {
+		resp.NextOffset = &next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleMyInvitationCount returns how many invitations sent to the
+// caller's email address are pending, accepted, or declined; see
+// invitations.Store.CountByEmail.
+func (s *Server) handleMyInvitationCount(w http.ResponseWriter, r *http.Request) {
+	email, ok := auth.GetUserEmail(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing bearer token")
+		return
+	}
+
+	counts, err := s.invitations.CountByEmail(r.Context(), email)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not count invitations")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, counts)
+}
+
+type acceptInvitationRequest struct {
+	Force bool `json:"force"`
+}
+
+// handleAcceptInvitation accepts an invitation on behalf of the caller,
+// adding them as a "going" attendee of the associated event; see
+// invitations.Store.Accept. Force bypasses the schedule-conflict check, the
+// same as handleJoinEvent.
+func (s *Server) handleAcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid invitation id")
+		return
+	}
+
+	var req acceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.invitations.Accept(r.Context(), id, userID, req.Force); err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
+			writeError(w, http.StatusNotFound, "invitation not found")
+		case errors.Is(err, invitations.ErrInvitationExpired):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, events.ErrRSVPDeadlinePassed):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, events.ErrScheduleConflict):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not accept invitation")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAcceptInvitationByToken accepts an invitation via the single-use
+// token sent in its invitation email, without requiring the invitee to be
+// signed in; see invitations.Store.AcceptWithToken.
+func (s *Server) handleAcceptInvitationByToken(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := s.invitations.AcceptWithToken(r.Context(), token); err != nil {
+		switch {
+		case errors.Is(err, invitations.ErrInvalidToken):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, invitations.ErrInvitationExpired):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, events.ErrRSVPDeadlinePassed):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not accept invitation")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeclineInvitationByToken declines an invitation via the single-use
+// token sent in its invitation email, without requiring the invitee to be
+// signed in; see invitations.Store.DeclineWithToken.
+func (s *Server) handleDeclineInvitationByToken(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := s.invitations.DeclineWithToken(r.Context(), token); err != nil {
+		switch {
+		case errors.Is(err, invitations.ErrInvalidToken):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, invitations.ErrInvitationExpired):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not decline invitation")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeclineInvitation declines an invitation sent to the caller's
+// email address; see invitations.Store.Decline.
+func (s *Server) handleDeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	email, ok := auth.GetUserEmail(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing bearer token")
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid invitation id")
+		return
+	}
+
+	if err := s.invitations.Decline(r.Context(), id, email); err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
+			writeError(w, http.StatusNotFound, "invitation not found")
+		case errors.Is(err, invitations.ErrInvitationExpired):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not decline invitation")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRevokeInvitation withdraws a still-pending invitation. The caller
+// must be the event's organizer or an accepted collaborator; see
+// invitations.Store.Revoke.
+func (s *Server) handleRevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid invitation id")
+		return
+	}
+
+	if err := s.invitations.Revoke(r.Context(), id, userID); err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
+			writeError(w, http.StatusNotFound, "invitation not found")
+		case errors.Is(err, invitations.ErrForbidden):
+			writeError(w, http.StatusForbidden, "not permitted to revoke this invitation")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not revoke invitation")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,146 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/dohasheriff/tools-3/internal/auth"
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+// withRequestTimeout cancels a request's context after s.requestTimeout,
+// so a slow downstream call (typically a database query; see
+// db.WithQueryTimeout) can't pin a connection indefinitely. It skips
+// requests that are expected to run long by design: the SSE event stream
+// and the WebSocket upgrade, identified by isStreamingRequest.
+func (s *Server) withRequestTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.requestTimeout <= 0 || isStreamingRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isStreamingRequest reports whether r is a long-lived connection that
+// withRequestTimeout shouldn't bound: the WebSocket upgrade at /ws, or an
+// SSE event stream at /events/{id}/stream.
+func isStreamingRequest(r *http.Request) bool {
+	return r.URL.Path == "/ws" || strings.HasSuffix(r.URL.Path, "/stream")
+}
+
+type contextKey string
+
+const (
+	userIDContextKey contextKey = "userID"
+	roleContextKey   contextKey = "role"
+	scopesContextKey contextKey = "scopes"
+)
+
+// requireAuth parses the bearer token from the Authorization header and
+// attaches the resulting user ID, email, and role to the request context.
+func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		userID, email, role, err := s.auth.ParseAccessToken(r.Context(), token)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid or expired token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		ctx = context.WithValue(ctx, roleContextKey, role)
+		ctx = auth.ContextWithEmail(ctx, email)
+		recordUserIDForLogging(ctx, userID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// optionalAuth behaves like requireAuth but, when no bearer token is
+// present (or it fails to parse), calls next anonymously instead of
+// rejecting the request. Handlers behind it use userIDFromContext's ok
+// return value to tell the two cases apart.
+func (s *Server) optionalAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			next(w, r)
+			return
+		}
+
+		userID, email, role, err := s.auth.ParseAccessToken(r.Context(), token)
+		if err != nil {
+			next(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		ctx = context.WithValue(ctx, roleContextKey, role)
+		ctx = auth.ContextWithEmail(ctx, email)
+		recordUserIDForLogging(ctx, userID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// requireRole wraps an already requireAuth-protected handler, rejecting
+// requests from users whose role is not role.
+func (s *Server) requireRole(role users.Role, next http.HandlerFunc) http.HandlerFunc {
+	return s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		actual, ok := roleFromContext(r.Context())
+		if !ok || actual != role {
+			writeError(w, http.StatusForbidden, "insufficient permissions")
+			return
+		}
+		next(w, r)
+	})
+}
+
+// requireAPIKey validates the X-API-Key header instead of a bearer JWT,
+// rejecting keys that are missing, unknown, revoked, or not granted scope,
+// and attaches the resulting user ID and scopes to the request context.
+func (s *Server) requireAPIKey(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if key == "" {
+			writeError(w, http.StatusUnauthorized, "missing api key")
+			return
+		}
+
+		keyAuth, err := s.auth.AuthenticateAPIKey(r.Context(), key)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid or revoked api key")
+			return
+		}
+		if !keyAuth.HasScope(scope) {
+			writeError(w, http.StatusForbidden, "api key lacks required scope")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, keyAuth.UserID)
+		ctx = context.WithValue(ctx, scopesContextKey, keyAuth.Scopes)
+		recordUserIDForLogging(ctx, keyAuth.UserID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func userIDFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(userIDContextKey).(int64)
+	return id, ok
+}
+
+func roleFromContext(ctx context.Context) (users.Role, bool) {
+	role, ok := ctx.Value(roleContextKey).(users.Role)
+	return role, ok
+}
@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/dohasheriff/tools-3/internal/msgraphcalendar"
+)
+
+// handleConnectOutlookCalendar returns the URL the caller's browser should
+// be sent to in order to grant this account access to their Outlook
+// calendar via Microsoft Graph; see handleConnectGoogleCalendar for why
+// this returns JSON rather than redirecting.
+func (s *Server) handleConnectOutlookCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	authURL, err := s.outlookCalendar.StartConnect(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not start outlook calendar connection")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"authorize_url": authURL})
+}
+
+// handleOutlookCalendarCallback completes a connection started by
+// handleConnectOutlookCalendar.
+func (s *Server) handleOutlookCalendarCallback(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	code := r.URL.Query().Get("code")
+
+	err := s.outlookCalendar.CompleteConnect(r.Context(), state, code)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, msgraphcalendar.ErrInvalidState):
+		writeError(w, http.StatusBadRequest, "invalid or expired state")
+	case errors.Is(err, msgraphcalendar.ErrNoRefreshToken):
+		writeError(w, http.StatusBadRequest, "microsoft did not grant offline access; try again")
+	default:
+		writeError(w, http.StatusInternalServerError, "could not complete outlook calendar connection")
+	}
+}
+
+// handleDisconnectOutlookCalendar removes the caller's Outlook calendar
+// connection.
+func (s *Server) handleDisconnectOutlookCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	if err := s.outlookCalendar.Disconnect(r.Context(), userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not disconnect outlook calendar")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// syncOutlookCalendar pushes eventID into userID's connected Outlook
+// calendar, best-effort.
+func (s *Server) syncOutlookCalendar(ctx context.Context, userID, eventID int64) {
+	if err := s.outlookCalendar.PushEvent(ctx, userID, eventID); err != nil {
+		log.Printf("sync outlook calendar event %d for user %d: %v", eventID, userID, err)
+	}
+}
+
+// unsyncOutlookCalendar removes eventID from userID's connected Outlook
+// calendar, best-effort.
+func (s *Server) unsyncOutlookCalendar(ctx context.Context, userID, eventID int64) {
+	if err := s.outlookCalendar.RemoveEvent(ctx, userID, eventID); err != nil {
+		log.Printf("remove outlook calendar event %d for user %d: %v", eventID, userID, err)
+	}
+}
@@ -0,0 +1,124 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/notifications"
+)
+
+type registerDeviceRequest struct {
+	Platform string `json:"platform"`
+	Token    string `json:"token"`
+	Endpoint string `json:"endpoint"`
+	P256dh   string `json:"p256dh"`
+	Auth     string `json:"auth"`
+}
+
+// handleRegisterDevice registers a device to receive push notifications:
+// an FCM registration token (platform "fcm", token set) or a Web Push
+// subscription (platform "web", endpoint/p256dh/auth set); see
+// notifications.Store.RegisterDevice.
+func (s *Server) handleRegisterDevice(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	var req registerDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err := s.notifications.RegisterDevice(r.Context(), userID, notifications.RegisterDeviceInput{
+		Platform: req.Platform,
+		Token:    req.Token,
+		Endpoint: req.Endpoint,
+		P256dh:   req.P256dh,
+		Auth:     req.Auth,
+	})
+	if err != nil {
+		if errors.Is(err, notifications.ErrInvalidPlatform) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not register device")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListNotifications returns the caller's notifications, most recent
+// first.
+func (s *Server) handleListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	limit, offset := paginationParams(r)
+	list, err := s.notifications.ListForUser(r.Context(), userID, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list notifications")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// handleMarkNotificationRead marks a single notification as read. The
+// caller must own it.
+func (s *Server) handleMarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	if err := s.notifications.MarkRead(r.Context(), id, userID); err != nil {
+		switch {
+		case errors.Is(err, notifications.ErrNotFound):
+			writeError(w, http.StatusNotFound, "notification not found")
+		case errors.Is(err, notifications.ErrForbidden):
+			writeError(w, http.StatusForbidden, "not permitted to read this notification")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not mark notification as read")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleMarkAllNotificationsRead marks every one of the caller's unread
+// notifications as read.
+func (s *Server) handleMarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	if _, err := s.notifications.MarkAllRead(r.Context(), userID); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not mark notifications as read")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// notify creates an in-app notification for userID, logging and discarding
+// any failure so it never fails the action that triggered it, the same
+// best-effort tradeoff geocodeAsync makes for event geocoding.
+func (s *Server) notify(ctx context.Context, userID int64, kind, title, body string, eventID *int64) {
+	if err := s.notifications.Notify(ctx, userID, kind, title, body, eventID); err != nil {
+		log.Printf("notify user %d: %v", userID, err)
+	}
+}
+
+// notifySMS texts body to userID's verified phone number, best-effort, for
+// notifications urgent enough to warrant SMS on top of the in-app/push
+// channel notify handles, such as an event cancellation.
+func (s *Server) notifySMS(ctx context.Context, userID int64, body string) {
+	if err := s.auth.NotifySMS(ctx, userID, body); err != nil {
+		log.Printf("notify sms user %d: %v", userID, err)
+	}
+}
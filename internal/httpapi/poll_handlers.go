@@ -0,0 +1,171 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/polls"
+)
+
+type pollOptionRequest struct {
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+type createPollRequest struct {
+	EventID     *int64              `json:"event_id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Location    string              `json:"location"`
+	Options     []pollOptionRequest `json:"options"`
+}
+
+// handleCreatePoll proposes a poll with one or more candidate slots. The
+// caller becomes the poll's organizer. Set event_id to tie the poll to an
+// existing event whose time Finalize will update; leave it unset to have
+// Finalize create the event from the winning slot instead.
+func (s *Server) handleCreatePoll(w http.ResponseWriter, r *http.Request) {
+	organizerID, _ := userIDFromContext(r.Context())
+
+	var req createPollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	options := make([]polls.CandidateSlot, len(req.Options))
+	for i, o := range req.Options {
+		options[i] = polls.CandidateSlot{StartsAt: o.StartsAt, EndsAt: o.EndsAt}
+	}
+
+	poll, err := s.polls.Create(r.Context(), polls.CreateInput{
+		OrganizerID: organizerID,
+		EventID:     req.EventID,
+		Title:       req.Title,
+		Description: req.Description,
+		Location:    req.Location,
+		Options:     options,
+	})
+	if err != nil {
+		if errors.Is(err, polls.ErrNoOptions) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not create poll")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, poll)
+}
+
+type pollResponse struct {
+	*polls.Poll
+	Options []polls.OptionResult `json:"options"`
+}
+
+// handleGetPoll fetches a poll along with each candidate slot's current
+// vote count.
+func (s *Server) handleGetPoll(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid poll id")
+		return
+	}
+
+	poll, err := s.polls.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, polls.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "poll not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch poll")
+		return
+	}
+
+	results, err := s.polls.Results(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch poll")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pollResponse{Poll: poll, Options: results})
+}
+
+type votePollRequest struct {
+	OptionID int64 `json:"option_id"`
+}
+
+// handleVotePoll records the caller's availability for one of pollID's
+// candidate slots. Voting for several slots on the same poll is allowed.
+func (s *Server) handleVotePoll(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid poll id")
+		return
+	}
+
+	var req votePollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.polls.Vote(r.Context(), id, req.OptionID, userID); err != nil {
+		switch {
+		case errors.Is(err, polls.ErrNotFound):
+			writeError(w, http.StatusNotFound, "poll or option not found")
+		case errors.Is(err, polls.ErrAlreadyFinalized):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not record vote")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type finalizePollRequest struct {
+	OptionID int64 `json:"option_id"`
+}
+
+// handleFinalizePoll locks in one of pollID's candidate slots, creating or
+// updating the poll's event with it. Only the poll's organizer may
+// finalize it.
+func (s *Server) handleFinalizePoll(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid poll id")
+		return
+	}
+
+	var req finalizePollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	event, err := s.polls.Finalize(r.Context(), id, userID, req.OptionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, polls.ErrNotFound):
+			writeError(w, http.StatusNotFound, "poll or option not found")
+		case errors.Is(err, polls.ErrForbidden):
+			writeError(w, http.StatusForbidden, "not permitted to finalize this poll")
+		case errors.Is(err, polls.ErrAlreadyFinalized):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not finalize poll")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, event)
+}
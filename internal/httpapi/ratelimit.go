@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	loginRateLimitWindow = time.Minute
+	loginRateLimitMax    = 10
+
+	searchRateLimitWindow = time.Minute
+	searchRateLimitMax    = 20
+)
+
+// ipRateLimiter throttles requests per client IP to max within a trailing
+// window, independently of any other limit (such as the per-account lockout
+// applied by auth.Service) so a single IP cannot hammer an endpoint across
+// many accounts. loginLimiter and searchLimiter are both instances of this
+// with different windows/max.
+//
+// attempts entries are only trimmed when that same IP makes another
+// request, so an IP that calls once and never comes back keeps a
+// one-element slice in the map for the life of the process. Acceptable for
+// now given how small each entry is, but a real periodic sweep would be
+// needed if this ever needs to bound memory under a wide IP-spoofing
+// attack.
+type ipRateLimiter struct {
+	window time.Duration
+	max    int
+
+	mu       sync.Mutex
+	attempts map[string][]time.Time
+}
+
+func newIPRateLimiter(window time.Duration, max int) *ipRateLimiter {
+	return &ipRateLimiter{window: window, max: max, attempts: make(map[string][]time.Time)}
+}
+
+// Allow reports whether ip may make another request within the current
+// window, recording the attempt if so.
+func (l *ipRateLimiter) Allow(ip string) bool {
+	now := time.Now()
+	cutoff := now.Add(-l.window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.attempts[ip][:0]
+	for _, t := range l.attempts[ip] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= l.max {
+		l.attempts[ip] = recent
+		return false
+	}
+
+	l.attempts[ip] = append(recent, now)
+	return true
+}
+
+// clientIP returns the request's source IP, stripping the port from
+// RemoteAddr.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,175 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// errInvalidOccurrenceWindow is returned by occurrenceWindow when "from" or
+// "until" cannot be parsed as RFC 3339 timestamps.
+var errInvalidOccurrenceWindow = errors.New("from/until must be RFC 3339 timestamps")
+
+const defaultOccurrenceWindow = 90 * 24 * time.Hour
+
+type occurrenceResponse struct {
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	Cancelled bool       `json:"cancelled"`
+}
+
+// handleListOccurrences expands a recurring event's RRule into concrete
+// occurrences between the "from" and "until" query parameters (RFC 3339),
+// defaulting to a 90-day window starting now, applying any per-occurrence
+// cancellations or reschedules.
+func (s *Server) handleListOccurrences(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	from, until, err := occurrenceWindow(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	occurrences, err := s.events.ExpandOccurrences(r.Context(), id, from, until)
+	if err != nil {
+		switch {
+		case errors.Is(err, events.ErrNotFound):
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, events.ErrNotRecurring):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, events.ErrInvalidRRule):
+			writeError(w, http.StatusInternalServerError, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not expand occurrences")
+		}
+		return
+	}
+
+	resp := make([]occurrenceResponse, len(occurrences))
+	for i, occ := range occurrences {
+		resp[i] = occurrenceResponse{StartsAt: occ.StartsAt, EndsAt: occ.EndsAt, Cancelled: occ.Cancelled}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// occurrenceWindow parses the "from"/"until" query parameters, defaulting
+// to [now, now+defaultOccurrenceWindow).
+func occurrenceWindow(r *http.Request) (from, until time.Time, err error) {
+	from = time.Now()
+	until = from.Add(defaultOccurrenceWindow)
+
+	if v := r.URL.Query().Get("from"); v != "" {
+		from, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errInvalidOccurrenceWindow
+		}
+		until = from.Add(defaultOccurrenceWindow)
+	}
+	if v := r.URL.Query().Get("until"); v != "" {
+		until, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errInvalidOccurrenceWindow
+		}
+	}
+	return from, until, nil
+}
+
+type cancelOccurrenceRequest struct {
+	OccurrenceStartsAt time.Time `json:"occurrence_starts_at"`
+}
+
+// handleCancelOccurrence cancels a single occurrence of a recurring event,
+// identified by its original start time, leaving the rest of the series
+// intact. Only the organizer may cancel an occurrence.
+func (s *Server) handleCancelOccurrence(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can cancel an occurrence")
+		return
+	}
+
+	var req cancelOccurrenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.events.CancelOccurrence(r.Context(), id, req.OccurrenceStartsAt); err != nil {
+		if errors.Is(err, events.ErrNotRecurring) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not cancel occurrence")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type rescheduleOccurrenceRequest struct {
+	OccurrenceStartsAt time.Time  `json:"occurrence_starts_at"`
+	NewStartsAt        time.Time  `json:"new_starts_at"`
+	NewEndsAt          *time.Time `json:"new_ends_at"`
+}
+
+// handleRescheduleOccurrence moves a single occurrence of a recurring event
+// to a new time, leaving the rest of the series intact. Only the organizer
+// may reschedule an occurrence.
+func (s *Server) handleRescheduleOccurrence(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can reschedule an occurrence")
+		return
+	}
+
+	var req rescheduleOccurrenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.events.RescheduleOccurrence(r.Context(), id, req.OccurrenceStartsAt, req.NewStartsAt, req.NewEndsAt); err != nil {
+		if errors.Is(err, events.ErrNotRecurring) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not reschedule occurrence")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,34 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) handleEventReferrals(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	event, err := s.events.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if event.OrganizerID != userID {
+		writeError(w, http.StatusForbidden, "only the organizer can view referrals")
+		return
+	}
+
+	summary, err := s.events.ReferralSummary(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch referral summary")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, summary)
+}
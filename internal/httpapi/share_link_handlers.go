@@ -0,0 +1,121 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+type shareLinkResponse struct {
+	Code string `json:"code"`
+}
+
+// handleGenerateShareLink issues (or rotates, if eventID already has one) a
+// public join link; see events.Store.GenerateShareLink. Only the organizer
+// may call this.
+func (s *Server) handleGenerateShareLink(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	code, err := s.events.GenerateShareLink(r.Context(), id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, events.ErrNotFound):
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, events.ErrForbidden):
+			writeError(w, http.StatusForbidden, "only the organizer can generate a share link for this event")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not generate share link")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, shareLinkResponse{Code: code})
+}
+
+// handleDisableShareLink removes eventID's public join link, if any; see
+// events.Store.DisableShareLink. Only the organizer may call this.
+func (s *Server) handleDisableShareLink(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	if err := s.events.DisableShareLink(r.Context(), id, userID); err != nil {
+		switch {
+		case errors.Is(err, events.ErrNotFound):
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, events.ErrForbidden):
+			writeError(w, http.StatusForbidden, "only the organizer can disable this event's share link")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not disable share link")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetEventByShareCode looks up an event by its public share code,
+// bypassing Visibility; see events.Store.GetByShareCode. Anyone with the
+// code can reach this, signed in or not.
+func (s *Server) handleGetEventByShareCode(w http.ResponseWriter, r *http.Request) {
+	event, err := s.events.GetByShareCode(r.Context(), r.PathValue("code"))
+	if err != nil {
+		if errors.Is(err, events.ErrShareLinkDisabled) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not fetch event")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, event)
+}
+
+// handleJoinViaShareCode joins the caller to the event behind a public
+// share code; see events.Store.JoinViaShareCode. Capacity, RSVP deadline,
+// and schedule-conflict handling are the same as handleJoinEvent.
+func (s *Server) handleJoinViaShareCode(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+	code := r.PathValue("code")
+
+	var req joinEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Status == "" {
+		req.Status = events.StatusGoing
+	}
+
+	recorded, err := s.events.JoinViaShareCode(r.Context(), code, userID, req.Status, req.Force)
+	if err != nil {
+		switch {
+		case errors.Is(err, events.ErrShareLinkDisabled):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, events.ErrRSVPDeadlinePassed):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, events.ErrBanned):
+			writeError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, events.ErrScheduleConflict):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not join event")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, joinEventResponse{Status: recorded})
+}
@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/slack"
+)
+
+type setSlackWebhookRequest struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+// handleSetSlackWebhook connects (or, with an empty webhook_url,
+// disconnects) eventID's Slack incoming webhook. The caller must be the
+// event's organizer.
+func (s *Server) handleSetSlackWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req setSlackWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := s.slack.SetWebhook(r.Context(), id, userID, req.WebhookURL); err != nil {
+		switch {
+		case errors.Is(err, events.ErrNotFound):
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, slack.ErrForbidden):
+			writeError(w, http.StatusForbidden, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not set slack webhook")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// notifySlack posts text to eventID's connected Slack channel,
+// best-effort, the same tradeoff notify makes for in-app notifications.
+func (s *Server) notifySlack(ctx context.Context, eventID int64, text string) {
+	if err := s.slack.Notify(ctx, eventID, text); err != nil {
+		log.Printf("notify slack event %d: %v", eventID, err)
+	}
+}
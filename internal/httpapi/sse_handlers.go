@@ -0,0 +1,145 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+)
+
+// sseHub fans out live updates to clients streaming a single event's page,
+// keyed by event ID. It's the Server-Sent Events counterpart to
+// realtime.Hub, which pushes per-user WebSocket updates instead; SSE is
+// one-way and needs no framing or handshake beyond http.Flusher, so it
+// doesn't share that package's Conn type.
+type sseHub struct {
+	mu      sync.Mutex
+	clients map[int64]map[chan []byte]struct{}
+}
+
+func newSSEHub() *sseHub {
+	return &sseHub{clients: make(map[int64]map[chan []byte]struct{})}
+}
+
+// subscribe registers a new client for eventID's stream and returns the
+// channel it should read formatted SSE messages from.
+func (h *sseHub) subscribe(eventID int64) chan []byte {
+	ch := make(chan []byte, 8)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[eventID] == nil {
+		h.clients[eventID] = make(map[chan []byte]struct{})
+	}
+	h.clients[eventID][ch] = struct{}{}
+	return ch
+}
+
+func (h *sseHub) unsubscribe(eventID int64, ch chan []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients[eventID], ch)
+	if len(h.clients[eventID]) == 0 {
+		delete(h.clients, eventID)
+	}
+}
+
+// publish sends data, marshaled as JSON, to every client currently
+// streaming eventID under the given SSE event name. A client whose buffer
+// is full is skipped rather than blocking the publisher.
+func (h *sseHub) publish(eventID int64, sseEvent string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	msg := []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", sseEvent, payload))
+
+	h.mu.Lock()
+	clients := make([]chan []byte, 0, len(h.clients[eventID]))
+	for c := range h.clients[eventID] {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		select {
+		case c <- msg:
+		default:
+		}
+	}
+}
+
+// handleEventStream streams GET /events/{id}/stream as Server-Sent
+// Events: attendee count changes, new comments, and organizer
+// announcements (comments authored by the organizer), so an event page
+// can update live without polling. Authentication is optional, same as
+// handleGetEvent, since access to a private event's stream is granted by
+// attendee/organizer status rather than by being logged in at all.
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var viewerID *int64
+	if uid, ok := userIDFromContext(r.Context()); ok {
+		viewerID = &uid
+	}
+
+	if _, err := s.events.GetForViewer(r.Context(), id, viewerID); err != nil {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound), errors.Is(err, apperr.ErrForbidden):
+			writeError(w, http.StatusNotFound, "event not found")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not fetch event")
+		}
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ch := s.eventStream.subscribe(id)
+	defer s.eventStream.unsubscribe(id, ch)
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(msg); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// publishAttendeeCount pushes eventID's current going-attendee count to
+// its stream, best-effort, so it never fails the join/leave action that
+// triggered it.
+func (s *Server) publishAttendeeCount(ctx context.Context, eventID int64) {
+	list, err := s.events.GetEventAttendees(ctx, eventID)
+	if err != nil {
+		return
+	}
+	s.eventStream.publish(eventID, "attendee_count", map[string]interface{}{"going": list.ByStatus["going"]})
+}
@@ -0,0 +1,15 @@
+package httpapi
+
+import "net/http"
+
+func (s *Server) handleDurationStats(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	stats, err := s.events.DurationStatsForOrganizer(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not compute duration stats")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
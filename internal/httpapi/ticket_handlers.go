@@ -0,0 +1,174 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dohasheriff/tools-3/internal/tickets"
+)
+
+type createTicketTypeRequest struct {
+	Name       string `json:"name"`
+	Quantity   int    `json:"quantity"`
+	PriceCents int    `json:"price_cents"`
+}
+
+// handleCreateTicketType adds a ticket type to an event. Only the
+// organizer may do this.
+func (s *Server) handleCreateTicketType(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req createTicketTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	tt, err := s.tickets.CreateTicketType(r.Context(), id, userID, req.Name, req.Quantity, req.PriceCents)
+	if err != nil {
+		switch {
+		case errors.Is(err, tickets.ErrForbidden):
+			writeError(w, http.StatusForbidden, "only the organizer can add ticket types")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not create ticket type")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, tt)
+}
+
+// handleListTicketTypes returns an event's ticket types so prospective
+// attendees can see what's available before claiming one.
+func (s *Server) handleListTicketTypes(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	types, err := s.tickets.ListTicketTypes(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not fetch ticket types")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, types)
+}
+
+type claimTicketRequest struct {
+	TicketTypeID int64  `json:"ticket_type_id"`
+	SuccessURL   string `json:"success_url"`
+	CancelURL    string `json:"cancel_url"`
+}
+
+type claimTicketResponse struct {
+	Ticket      *tickets.Ticket `json:"ticket"`
+	CheckoutURL string          `json:"checkout_url,omitempty"`
+}
+
+// handleClaimTicket issues the caller a ticket of the requested type. For a
+// paid ticket type, SuccessURL/CancelURL are required and the response's
+// CheckoutURL must be completed before the ticket is issued.
+func (s *Server) handleClaimTicket(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req claimTicketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	session, err := s.tickets.Claim(r.Context(), id, req.TicketTypeID, userID, req.SuccessURL, req.CancelURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, tickets.ErrNotFound):
+			writeError(w, http.StatusNotFound, "ticket type not found")
+		case errors.Is(err, tickets.ErrSoldOut):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not claim ticket")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, claimTicketResponse{Ticket: session.Ticket, CheckoutURL: session.CheckoutURL})
+}
+
+// handleListTickets returns every issued ticket for an event. Only the
+// organizer may view the list.
+func (s *Server) handleListTickets(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	list, err := s.tickets.ListTickets(r.Context(), id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, tickets.ErrForbidden):
+			writeError(w, http.StatusForbidden, "only the organizer can view issued tickets")
+		default:
+			writeError(w, http.StatusInternalServerError, "could not fetch tickets")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+type validateTicketRequest struct {
+	Code string `json:"code"`
+}
+
+// handleValidateTicket marks a ticket validated at the door. Only the
+// organizer may validate tickets.
+func (s *Server) handleValidateTicket(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	var req validateTicketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ticket, err := s.tickets.Validate(r.Context(), id, userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, tickets.ErrForbidden):
+			writeError(w, http.StatusForbidden, "only the organizer can validate tickets")
+		case errors.Is(err, tickets.ErrNotFound):
+			writeError(w, http.StatusNotFound, "ticket not found")
+		case errors.Is(err, tickets.ErrAlreadyValidated), errors.Is(err, tickets.ErrPaymentPending):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "could not validate ticket")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ticket)
+}
@@ -0,0 +1,27 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+type trendingEventsResponse struct {
+	Events []*events.Event `json:"events"`
+}
+
+// handleTrendingEvents serves GET /events/trending?limit=, public upcoming
+// events ranked by recent joins and invitation accepts. Rankings come from
+// the last scheduled recomputation (see runTrendingScheduler), not a
+// per-request scan.
+func (s *Server) handleTrendingEvents(w http.ResponseWriter, r *http.Request) {
+	limit, _ := paginationParams(r)
+
+	list, err := s.events.ListTrending(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not list trending events")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, trendingEventsResponse{Events: list})
+}
@@ -0,0 +1,83 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dohasheriff/tools-3/internal/auth"
+)
+
+type userSearchResult struct {
+	ID          int64  `json:"id"`
+	Email       string `json:"email"`
+	Username    string `json:"username,omitempty"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+}
+
+// handleSearchUsers lets an authenticated caller look up accounts by a
+// prefix match on email, username, or display name, so an inviter can find
+// the numeric user ID an invitation needs.
+func (s *Server) handleSearchUsers(w http.ResponseWriter, r *http.Request) {
+	if !s.searchLimiter.Allow(clientIP(r)) {
+		writeError(w, http.StatusTooManyRequests, "too many search requests, try again later")
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	results, err := s.auth.SearchUsers(r.Context(), query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not search users")
+		return
+	}
+
+	resp := make([]userSearchResult, len(results))
+	for i, u := range results {
+		resp[i] = userSearchResult{ID: u.ID, Email: u.Email, Username: u.Username.String, DisplayName: u.DisplayName, AvatarURL: u.AvatarURL}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type avatarResponse struct {
+	AvatarURL string `json:"avatar_url"`
+}
+
+// handleUploadAvatar accepts a multipart/form-data upload with the image in
+// the "avatar" field, saves it through the configured storage backend, and
+// records the resulting URL on the caller's account.
+func (s *Server) handleUploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, auth.MaxAvatarSize)
+	if err := r.ParseMultipartForm(auth.MaxAvatarSize); err != nil {
+		writeError(w, http.StatusBadRequest, "avatar upload is too large or malformed")
+		return
+	}
+
+	file, header, err := r.FormFile("avatar")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "avatar file is required")
+		return
+	}
+	defer file.Close()
+
+	contentType := header.Header.Get("Content-Type")
+
+	url, err := s.auth.UploadAvatar(r.Context(), userID, file, contentType)
+	if err != nil {
+		if errors.Is(err, auth.ErrUnsupportedAvatarType) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not upload avatar")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, avatarResponse{AvatarURL: url})
+}
@@ -0,0 +1,24 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+)
+
+// handleStripeWebhook receives Stripe webhook deliveries confirming ticket
+// payments. It is unauthenticated; the payload's signature is verified by
+// the configured PaymentProvider instead.
+func (s *Server) handleStripeWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "could not read request body")
+		return
+	}
+
+	if err := s.tickets.HandleWebhook(r.Context(), payload, r.Header.Get("Stripe-Signature")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid webhook payload")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
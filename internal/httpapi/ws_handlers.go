@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dohasheriff/tools-3/internal/realtime"
+)
+
+// handleWebSocket upgrades GET /ws to a WebSocket connection that pushes
+// the caller's real-time events (invitations received, attendees joining
+// their events, event updates) for as long as the connection stays open.
+// Browsers can't set an Authorization header on the request that opens a
+// WebSocket, so the access token is accepted as a query parameter too,
+// the same accommodation handleCalendarFeed makes for unattended clients.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("access_token")
+	if token == "" {
+		token, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "missing access token")
+		return
+	}
+
+	userID, _, _, err := s.auth.ParseAccessToken(r.Context(), token)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid or expired token")
+		return
+	}
+
+	conn, err := realtime.Upgrade(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "could not upgrade to websocket")
+		return
+	}
+
+	s.realtime.Serve(userID, conn)
+}
+
+// publishRealtime pushes a best-effort real-time event to userID's open
+// WebSocket connections, the same best-effort tradeoff notify makes for
+// in-app notifications.
+func (s *Server) publishRealtime(userID int64, eventType string, data interface{}) {
+	s.realtime.Publish(userID, eventType, data)
+}
@@ -0,0 +1,125 @@
+// Package icalendar renders RFC 5545 iCalendar documents: the VEVENT
+// blocks served by the plain .ics export endpoint, and the
+// METHOD:REQUEST/CANCEL invites attached to invitation and reminder
+// emails so mail clients render native accept/decline buttons.
+package icalendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Method is the iCalendar METHOD property, which tells the recipient's
+// mail client how to treat the attached VEVENT.
+type Method string
+
+const (
+	// MethodPublish marks a standalone event with no RSVP semantics, for
+	// the plain .ics export a user downloads or subscribes to.
+	MethodPublish Method = "PUBLISH"
+	// MethodRequest marks an invite the recipient can accept or decline,
+	// attached to invitation and reminder emails.
+	MethodRequest Method = "REQUEST"
+	// MethodCancel withdraws a previously sent REQUEST.
+	MethodCancel Method = "CANCEL"
+)
+
+// Event is the event detail needed to render a VEVENT.
+type Event struct {
+	UID            string
+	Title          string
+	Description    string
+	Location       string
+	StartsAt       time.Time
+	EndsAt         *time.Time
+	OrganizerEmail string
+	OrganizerName  string
+	// AttendeeEmail addresses the VEVENT to a single recipient with an
+	// ATTENDEE property, as invitation and reminder emails do. Left empty
+	// for the organizer-less plain .ics export.
+	AttendeeEmail string
+	AttendeeName  string
+	// Sequence is the iCalendar SEQUENCE property: it must increase each
+	// time a REQUEST is resent for the same UID (e.g. a reminder sent
+	// after the original invite) so mail clients know which copy is
+	// newest.
+	Sequence int
+}
+
+// Build renders event as a single-event iCalendar document with the given
+// METHOD.
+func Build(method Method, event Event) []byte {
+	return BuildFeed(method, []Event{event})
+}
+
+// BuildFeed renders events as a single iCalendar document containing one
+// VEVENT per event, for a calendar feed covering more than one event.
+func BuildFeed(method Method, events []Event) []byte {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//tools-3//events//EN\r\n")
+	fmt.Fprintf(&b, "METHOD:%s\r\n", method)
+	for _, event := range events {
+		appendVEVENT(&b, method, event)
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return []byte(b.String())
+}
+
+// appendVEVENT writes a single VEVENT block for event to b.
+func appendVEVENT(b *strings.Builder, method Method, event Event) {
+	b.WriteString("BEGIN:VEVENT\r\n")
+	fmt.Fprintf(b, "UID:%s\r\n", event.UID)
+	fmt.Fprintf(b, "SEQUENCE:%d\r\n", event.Sequence)
+	fmt.Fprintf(b, "DTSTAMP:%s\r\n", icsTime(time.Now().UTC()))
+	fmt.Fprintf(b, "DTSTART:%s\r\n", icsTime(event.StartsAt.UTC()))
+	if event.EndsAt != nil {
+		fmt.Fprintf(b, "DTEND:%s\r\n", icsTime(event.EndsAt.UTC()))
+	}
+	fmt.Fprintf(b, "SUMMARY:%s\r\n", icsEscape(event.Title))
+	if event.Description != "" {
+		fmt.Fprintf(b, "DESCRIPTION:%s\r\n", icsEscape(event.Description))
+	}
+	if event.Location != "" {
+		fmt.Fprintf(b, "LOCATION:%s\r\n", icsEscape(event.Location))
+	}
+	if event.OrganizerName != "" {
+		fmt.Fprintf(b, "ORGANIZER;CN=%s:mailto:%s\r\n", icsEscape(event.OrganizerName), event.OrganizerEmail)
+	} else {
+		fmt.Fprintf(b, "ORGANIZER:mailto:%s\r\n", event.OrganizerEmail)
+	}
+	if event.AttendeeEmail != "" {
+		partstat := "NEEDS-ACTION"
+		if method == MethodCancel {
+			partstat = "DECLINED"
+		}
+		if event.AttendeeName != "" {
+			fmt.Fprintf(b, "ATTENDEE;CN=%s;RSVP=TRUE;PARTSTAT=%s:mailto:%s\r\n", icsEscape(event.AttendeeName), partstat, event.AttendeeEmail)
+		} else {
+			fmt.Fprintf(b, "ATTENDEE;RSVP=TRUE;PARTSTAT=%s:mailto:%s\r\n", partstat, event.AttendeeEmail)
+		}
+	}
+	if method == MethodCancel {
+		b.WriteString("STATUS:CANCELLED\r\n")
+	}
+	b.WriteString("END:VEVENT\r\n")
+}
+
+// icsTime formats t as an iCalendar UTC DATE-TIME value.
+func icsTime(t time.Time) string {
+	return t.Format("20060102T150405Z")
+}
+
+// icsEscape escapes text for use in an iCalendar content value, per RFC
+// 5545 section 3.3.11.
+func icsEscape(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
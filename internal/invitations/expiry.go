@@ -0,0 +1,29 @@
+package invitations
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ExpireStaleInvitations marks every pending invitation whose ExpiresAt has
+// passed as expired, and returns the number of invitations expired. It's
+// meant to run periodically from a scheduled job (see
+// runInvitationExpiryScheduler in cmd/server/main.go) rather than per
+// request, the same as events.Store.ArchiveEndedEvents.
+func (s *Store) ExpireStaleInvitations(ctx context.Context) (int, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE invitations SET status = $1
+		 WHERE status = $2 AND expires_at IS NOT NULL AND expires_at <= $3`,
+		StatusExpired, StatusPending, time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("invitations: expire stale invitations: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("invitations: expire stale invitations rows affected: %w", err)
+	}
+	return int(affected), nil
+}
@@ -0,0 +1,687 @@
+// Package invitations manages event invitations sent to prospective
+// attendees.
+package invitations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/db"
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/icalendar"
+	"github.com/dohasheriff/tools-3/internal/users"
+)
+
+// Invitation statuses.
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusDeclined = "declined"
+	// StatusExpired is set by ExpireStaleInvitations on pending invitations
+	// whose ExpiresAt has passed.
+	StatusExpired = "expired"
+	// StatusRevoked is set by Revoke on a pending invitation the inviter
+	// has withdrawn.
+	StatusRevoked = "revoked"
+)
+
+// Invitation roles. RoleAttendee is a plain invite to attend; RoleCollaborator
+// and RoleOrganizer both grant edit access to the event once accepted, see
+// CanManage. RoleOrganizer is for co-organizer invitations; it carries the
+// same authority as RoleCollaborator today, since the event model tracks a
+// single OrganizerID and so cannot grant sole-organizer actions like delete
+// or transfer to more than one user, see CanManage.
+const (
+	RoleAttendee     = "attendee"
+	RoleCollaborator = "collaborator"
+	RoleOrganizer    = "organizer"
+)
+
+// ErrNotFound is returned when an invitation lookup finds no matching row.
+var ErrNotFound = apperr.Wrap(apperr.ErrNotFound, "invitations: not found")
+
+// ErrAtCapacity is returned by InviteUserToEvent in CapacityPolicyBlock mode
+// when the event has no remaining spots for pending and going attendees.
+var ErrAtCapacity = apperr.Wrap(apperr.ErrConflict, "invitations: event is at capacity")
+
+// ErrInvalidRole is returned when a role isn't RoleAttendee,
+// RoleCollaborator, or RoleOrganizer.
+var ErrInvalidRole = apperr.Wrap(apperr.ErrValidation, "invitations: invalid role")
+
+// ErrForbidden is returned by InviteUserToEvent when inviterID is neither
+// the event's organizer nor an accepted collaborator.
+var ErrForbidden = apperr.Wrap(apperr.ErrForbidden, "invitations: not permitted to invite attendees to this event")
+
+// CapacityPolicy controls what happens when inviting would push an event's
+// pending-plus-going count to or past its capacity.
+type CapacityPolicy string
+
+const (
+	// CapacityPolicyWarn allows the invite and annotates it with the
+	// remaining spots (which may be zero or negative).
+	CapacityPolicyWarn CapacityPolicy = "warn"
+	// CapacityPolicyBlock rejects the invite with ErrAtCapacity instead.
+	CapacityPolicyBlock CapacityPolicy = "block"
+)
+
+// Invitation is a pending or resolved invite to an event.
+type Invitation struct {
+	ID            int64
+	EventID       int64
+	InviterID     int64
+	InviteeEmail  string
+	InviteeUserID *int64
+	Status        string
+	// Role is RoleAttendee, RoleCollaborator, or RoleOrganizer; see CanManage.
+	Role       string
+	CreatedAt  time.Time
+	AcceptedAt *time.Time
+	// ExpiresAt is when a still-pending invitation stops being acceptable;
+	// see ExpireStaleInvitations. nil means it never expires, which is only
+	// possible for invitations created before expiration was added.
+	ExpiresAt *time.Time
+
+	// CapacityRemaining is the number of spots left after this invite, or
+	// nil if the event has no capacity limit. It is computed at invite time
+	// and is not persisted.
+	CapacityRemaining *int
+
+	// DeletedAt is set by DeleteForEvent when the invitation's event is
+	// soft-deleted, and cleared by RestoreForEvent; see events.Store.Delete.
+	// A soft-deleted invitation is excluded from every lookup and listing as
+	// though it didn't exist.
+	DeletedAt *time.Time
+}
+
+// DefaultInvitationTTL is how long an invitation remains acceptable when
+// the caller doesn't configure a different TTL in NewStore.
+const DefaultInvitationTTL = 7 * 24 * time.Hour
+
+// ErrInvitationExpired is returned by Accept, Decline, AcceptWithToken, and
+// DeclineWithToken when the invitation's ExpiresAt has passed.
+var ErrInvitationExpired = apperr.Wrap(apperr.ErrConflict, "invitations: invitation has expired")
+
+// ErrInvitationRevoked is returned by Accept and AcceptWithToken when the
+// invitation was withdrawn by the inviter; see Revoke.
+var ErrInvitationRevoked = apperr.Wrap(apperr.ErrConflict, "invitations: invitation has been revoked")
+
+// InvitationQuotas bounds how many invitations may be sent, to curb spam.
+// A zero field is replaced with the matching DefaultInvitationQuotas value
+// by NewStore, the same as ttl.
+type InvitationQuotas struct {
+	// MaxPerEvent caps the total number of invitations ever sent for a
+	// single event, regardless of their current status.
+	MaxPerEvent int
+	// MaxPerInviterPerHour caps the number of invitations a single
+	// inviter can send, across all of their events, within a trailing
+	// hour.
+	MaxPerInviterPerHour int
+}
+
+// DefaultInvitationQuotas are applied to any InvitationQuotas field left
+// zero when NewStore is called.
+var DefaultInvitationQuotas = InvitationQuotas{MaxPerEvent: 200, MaxPerInviterPerHour: 50}
+
+// ErrEventInvitationQuotaExceeded is returned by InviteUserToEvent,
+// InviteRegisteredUser, and InviteByUsername once an event has reached
+// InvitationQuotas.MaxPerEvent.
+var ErrEventInvitationQuotaExceeded = errors.New("invitations: event has reached its invitation limit")
+
+// ErrInviterInvitationQuotaExceeded is returned by InviteUserToEvent,
+// InviteRegisteredUser, and InviteByUsername once an inviter has reached
+// InvitationQuotas.MaxPerInviterPerHour.
+var ErrInviterInvitationQuotaExceeded = errors.New("invitations: inviter has reached their hourly invitation limit")
+
+// Store persists invitations and applies acceptance to event attendance.
+type Store struct {
+	db             *sql.DB
+	events         *events.Store
+	users          *users.Store
+	capacityPolicy CapacityPolicy
+	mailer         Mailer
+	// baseURL is the app's externally reachable base URL, used to build the
+	// accept/decline links sent in invitation emails. No link is sent when
+	// it's empty.
+	baseURL string
+	// ttl is how long a new invitation remains acceptable before
+	// ExpireStaleInvitations marks it expired.
+	ttl time.Duration
+	// quotas bounds how many invitations may be sent; see InvitationQuotas.
+	quotas InvitationQuotas
+	// notifier delivers in-app/push notifications for reminders sent by
+	// SendDueReminders, which runs from a scheduled job with no httpapi
+	// request to notify through; see Notifier.
+	notifier Notifier
+}
+
+// NewStore returns a Store backed by db, enforcing capacityPolicy when
+// invitations would exceed an event's capacity, resolving invitees passed
+// by user ID or username through userStore (see InviteRegisteredUser),
+// emailing invitees through mailer with accept/decline links rooted at
+// baseURL, expiring new invitations after ttl (DefaultInvitationTTL if ttl
+// is zero), capping how many can be sent per quotas (any zero field is
+// replaced with the matching DefaultInvitationQuotas value), and notifying
+// registered invitees of reminders through notifier.
+func NewStore(db *sql.DB, eventStore *events.Store, userStore *users.Store, capacityPolicy CapacityPolicy, mailer Mailer, baseURL string, ttl time.Duration, quotas InvitationQuotas, notifier Notifier) *Store {
+	if ttl == 0 {
+		ttl = DefaultInvitationTTL
+	}
+	if quotas.MaxPerEvent == 0 {
+		quotas.MaxPerEvent = DefaultInvitationQuotas.MaxPerEvent
+	}
+	if quotas.MaxPerInviterPerHour == 0 {
+		quotas.MaxPerInviterPerHour = DefaultInvitationQuotas.MaxPerInviterPerHour
+	}
+	return &Store{db: db, events: eventStore, users: userStore, capacityPolicy: capacityPolicy, mailer: mailer, baseURL: baseURL, ttl: ttl, quotas: quotas, notifier: notifier}
+}
+
+// ErrUserNotFound is returned by InviteRegisteredUser and InviteByUsername
+// when the given ID or username matches no account.
+var ErrUserNotFound = apperr.Wrap(apperr.ErrNotFound, "invitations: user not found")
+
+// InviteUserToEvent creates a pending invitation from inviterID to
+// inviteeEmail for eventID with the given role (RoleAttendee,
+// RoleCollaborator, or RoleOrganizer). inviterID must be the event's organizer or an
+// accepted collaborator; see CanManage. Invitations are rejected once the
+// event's RSVP deadline has passed, matching the rule enforced on joining.
+// If the event has a capacity and is already at or past it, the invite is
+// either annotated with the remaining spots (CapacityPolicyWarn) or
+// rejected with ErrAtCapacity (CapacityPolicyBlock). Once stored, inviteeEmail
+// is sent an HTML email with the event's details and accept/decline links;
+// see sendInvitationEmail.
+func (s *Store) InviteUserToEvent(ctx context.Context, eventID, inviterID int64, inviteeEmail, role string) (*Invitation, error) {
+	return s.invite(ctx, eventID, inviterID, inviteeEmail, nil, role)
+}
+
+// InviteRegisteredUser invites the registered account identified by userID
+// to eventID, the same as InviteUserToEvent but resolving the invitee's
+// email server-side instead of trusting a caller-supplied address. Because
+// the account is already known, the invitation's InviteeUserID is recorded
+// immediately rather than waiting for Accept, and acceptance never falls
+// through to the email-only provisional-attendee path used for unregistered
+// invitees; see AcceptWithToken. ErrUserNotFound is returned if userID
+// matches no account.
+func (s *Store) InviteRegisteredUser(ctx context.Context, eventID, inviterID, userID int64, role string) (*Invitation, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return s.invite(ctx, eventID, inviterID, u.Email, &u.ID, role)
+}
+
+// InviteByUsername invites the registered account with the given username
+// to eventID, the same as InviteRegisteredUser but looking the account up
+// by username instead of ID. ErrUserNotFound is returned if username
+// matches no account.
+func (s *Store) InviteByUsername(ctx context.Context, eventID, inviterID int64, username, role string) (*Invitation, error) {
+	u, err := s.users.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return s.invite(ctx, eventID, inviterID, u.Email, &u.ID, role)
+}
+
+// invite is the shared implementation behind InviteUserToEvent,
+// InviteRegisteredUser, and InviteByUsername. inviteeUserID is non-nil only
+// when the invitee is already a registered account.
+func (s *Store) invite(ctx context.Context, eventID, inviterID int64, inviteeEmail string, inviteeUserID *int64, role string) (*Invitation, error) {
+	if role != RoleAttendee && role != RoleCollaborator && role != RoleOrganizer {
+		return nil, ErrInvalidRole
+	}
+
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.RSVPDeadline != nil && time.Now().After(*e.RSVPDeadline) {
+		return nil, events.ErrRSVPDeadlinePassed
+	}
+
+	canManage, err := s.CanManage(ctx, eventID, inviterID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, ErrForbidden
+	}
+
+	if err := s.checkQuotas(ctx, eventID, inviterID); err != nil {
+		return nil, err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	inv := &Invitation{
+		EventID:       eventID,
+		InviterID:     inviterID,
+		InviteeEmail:  inviteeEmail,
+		InviteeUserID: inviteeUserID,
+		Status:        StatusPending,
+		Role:          role,
+		ExpiresAt:     &expiresAt,
+	}
+
+	err = db.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		if e.Capacity != nil {
+			// Lock the event row so a concurrent invite (or join) can't
+			// both observe spare capacity and both be admitted.
+			if err := s.events.LockForUpdate(ctx, tx, eventID); err != nil {
+				return err
+			}
+			committed, err := s.countPendingAndGoingInTx(ctx, tx, eventID)
+			if err != nil {
+				return err
+			}
+			r := *e.Capacity - committed - 1
+			inv.CapacityRemaining = &r
+			if s.capacityPolicy == CapacityPolicyBlock && r < 0 {
+				return fmt.Errorf("%w: %d spots remaining", ErrAtCapacity, r+1)
+			}
+		}
+
+		row := tx.QueryRowContext(ctx,
+			`INSERT INTO invitations (event_id, inviter_id, invitee_email, invitee_user_id, status, role, token_hash, expires_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			 RETURNING id, created_at`,
+			eventID, inviterID, inviteeEmail, inviteeUserID, StatusPending, role, hashInvitationToken(token), expiresAt,
+		)
+		if err := row.Scan(&inv.ID, &inv.CreatedAt); err != nil {
+			return fmt.Errorf("invitations: invite: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sendInvitationEmail(ctx, inv, e, token); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// sendInvitationEmail emails inv.InviteeEmail an HTML notification with
+// e's details and accept/decline links carrying token, the same tradeoff
+// auth.Register takes with sendVerificationEmail: the invite is only as
+// good as the caller's retry if the email bounces, since there's no
+// outbox to redeliver from. No email is sent if baseURL wasn't configured,
+// so invitations keep working in local development without an SMTP
+// provider wired in. If e's organizer has set a custom InvitationTemplate
+// (see SetInvitationTemplate), its subject and body are used instead of
+// the default, with placeholders substituted by templatePlaceholders.
+func (s *Store) sendInvitationEmail(ctx context.Context, inv *Invitation, e *events.Event, token string) error {
+	if s.baseURL == "" {
+		return nil
+	}
+
+	acceptURL := fmt.Sprintf("%s/invitations/accept?token=%s", s.baseURL, token)
+	declineURL := fmt.Sprintf("%s/invitations/decline?token=%s", s.baseURL, token)
+
+	subject := fmt.Sprintf("You're invited to %s", e.Title)
+	body := fmt.Sprintf(
+		`<p>You've been invited to <strong>%s</strong>, starting %s at %s.</p>
+<p><a href="%s">Accept</a> &middot; <a href="%s">Decline</a></p>`,
+		e.Title, e.StartsAt.Format(time.RFC1123), e.Location, acceptURL, declineURL,
+	)
+
+	tmpl, err := s.invitationTemplate(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+	if tmpl != nil {
+		replacer := templatePlaceholders(e, acceptURL, declineURL)
+		subject = replacer.Replace(tmpl.Subject)
+		body = replacer.Replace(tmpl.Body)
+	}
+
+	organizer, err := s.users.GetByID(ctx, e.OrganizerID)
+	if err != nil {
+		return fmt.Errorf("invitations: load organizer for invitation email: %w", err)
+	}
+
+	ics := icalendar.Build(icalendar.MethodRequest, icalendar.Event{
+		UID:            fmt.Sprintf("event-%d@tools-3", e.ID),
+		Title:          e.Title,
+		Description:    e.Description,
+		Location:       e.Location,
+		StartsAt:       e.StartsAt,
+		EndsAt:         e.EndsAt,
+		OrganizerEmail: organizer.Email,
+		OrganizerName:  organizer.DisplayName,
+		AttendeeEmail:  inv.InviteeEmail,
+	})
+
+	if err := s.mailer.SendICS(ctx, inv.InviteeEmail, subject, body, string(icalendar.MethodRequest), "invite.ics", ics); err != nil {
+		return fmt.Errorf("invitations: send invitation email: %w", err)
+	}
+	return nil
+}
+
+// countPendingAndGoingInTx returns the number of pending invitations plus
+// "going" attendees for eventID, used to evaluate capacity before inviting.
+// It runs against tx so the count is consistent with the event-row lock
+// invite takes earlier in the same transaction.
+func (s *Store) countPendingAndGoingInTx(ctx context.Context, tx *sql.Tx, eventID int64) (int, error) {
+	var pending int
+	row := tx.QueryRowContext(ctx,
+		`SELECT count(*) FROM invitations WHERE event_id = $1 AND status = $2`,
+		eventID, StatusPending,
+	)
+	if err := row.Scan(&pending); err != nil {
+		return 0, fmt.Errorf("invitations: count pending: %w", err)
+	}
+
+	going, err := s.events.CountGoingInTx(ctx, tx, eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	return pending + going, nil
+}
+
+// checkQuotas enforces InvitationQuotas against eventID and inviterID
+// before a new invitation is created.
+func (s *Store) checkQuotas(ctx context.Context, eventID, inviterID int64) error {
+	var eventCount int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM invitations WHERE event_id = $1`, eventID,
+	).Scan(&eventCount); err != nil {
+		return fmt.Errorf("invitations: count event invitations: %w", err)
+	}
+	if eventCount >= s.quotas.MaxPerEvent {
+		return ErrEventInvitationQuotaExceeded
+	}
+
+	var inviterCount int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM invitations WHERE inviter_id = $1 AND created_at >= $2`,
+		inviterID, time.Now().Add(-time.Hour),
+	).Scan(&inviterCount); err != nil {
+		return fmt.Errorf("invitations: count inviter invitations: %w", err)
+	}
+	if inviterCount >= s.quotas.MaxPerInviterPerHour {
+		return ErrInviterInvitationQuotaExceeded
+	}
+	return nil
+}
+
+// Accept marks invitationID accepted and adds userID as a "going" attendee
+// of the associated event, committing both writes in a single transaction
+// so a failure on either side leaves neither applied; see
+// events.Store.JoinEventInTx. Acceptance is rejected with
+// ErrInvitationRevoked if the inviter withdrew the invitation (see Revoke),
+// with ErrInvitationExpired once the invitation's ExpiresAt has passed, and
+// with events.ErrRSVPDeadlinePassed once the event's RSVP deadline has
+// passed. Unless force is true, it is also rejected with
+// events.ErrScheduleConflict if the invitee already has an overlapping
+// "going" event; see events.ConflictingEvents.
+func (s *Store) Accept(ctx context.Context, invitationID, userID int64, force bool) error {
+	inv, err := s.get(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if inv.Status == StatusRevoked {
+		return ErrInvitationRevoked
+	}
+	if inv.ExpiresAt != nil && time.Now().After(*inv.ExpiresAt) {
+		return ErrInvitationExpired
+	}
+
+	e, err := s.events.Get(ctx, inv.EventID)
+	if err != nil {
+		return err
+	}
+	if e.RSVPDeadline != nil && time.Now().After(*e.RSVPDeadline) {
+		return events.ErrRSVPDeadlinePassed
+	}
+	if !force {
+		conflicts, err := s.events.ConflictingEvents(ctx, userID, inv.EventID)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return events.ErrScheduleConflict
+		}
+	}
+
+	var status string
+	err = db.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		var txErr error
+		status, txErr = s.events.JoinEventInTx(ctx, tx, inv.EventID, userID, events.StatusGoing)
+		if txErr != nil {
+			return txErr
+		}
+
+		if _, txErr = tx.ExecContext(ctx,
+			`UPDATE invitations SET status = $1, invitee_user_id = $2, accepted_at = now() WHERE id = $3`,
+			StatusAccepted, userID, invitationID,
+		); txErr != nil {
+			return fmt.Errorf("invitations: accept: %w", txErr)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if status == events.StatusGoing && e.Status == events.EventStatusTentative {
+		if err := s.events.ConfirmQuorumIfMet(ctx, inv.EventID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decline marks invitationID declined. email must match the invitation's
+// InviteeEmail case-insensitively; a mismatch is reported as ErrNotFound
+// rather than ErrForbidden so a guessed invitation ID doesn't reveal who it
+// was sent to. Declining an already-resolved invitation is a no-op.
+// Declining is rejected with ErrInvitationExpired once the invitation's
+// ExpiresAt has passed.
+func (s *Store) Decline(ctx context.Context, invitationID int64, email string) error {
+	inv, err := s.get(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(inv.InviteeEmail, email) {
+		return ErrNotFound
+	}
+	if inv.ExpiresAt != nil && time.Now().After(*inv.ExpiresAt) {
+		return ErrInvitationExpired
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE invitations SET status = $1 WHERE id = $2 AND status = $3`,
+		StatusDeclined, invitationID, StatusPending,
+	)
+	if err != nil {
+		return fmt.Errorf("invitations: decline: %w", err)
+	}
+	return nil
+}
+
+// Revoke withdraws a still-pending invitation, marking it revoked and
+// invalidating its accept/decline token so neither the by-id flows (Accept,
+// Decline) nor the by-token flows (AcceptWithToken, DeclineWithToken) can
+// resolve it afterwards. actorID must be the event's organizer or an
+// accepted collaborator; see CanManage. Revoking an invitation that has
+// already been accepted, declined, or revoked is a no-op.
+func (s *Store) Revoke(ctx context.Context, invitationID, actorID int64) error {
+	inv, err := s.get(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+
+	canManage, err := s.CanManage(ctx, inv.EventID, actorID)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return ErrForbidden
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE invitations SET status = $1, token_used_at = now() WHERE id = $2 AND status = $3`,
+		StatusRevoked, invitationID, StatusPending,
+	)
+	if err != nil {
+		return fmt.Errorf("invitations: revoke: %w", err)
+	}
+	return nil
+}
+
+// GetInvitationsByEventID returns invitations sent for eventID, newest
+// first, along with the total number of matching rows.
+func (s *Store) GetInvitationsByEventID(ctx context.Context, eventID int64, limit, offset int) ([]*Invitation, int, error) {
+	return s.listInvitations(ctx, "event_id = $1", []interface{}{eventID}, limit, offset)
+}
+
+// GetInvitationsByEmail returns invitations sent to inviteeEmail ("my
+// invitations"), newest first, along with the total number of matching rows.
+func (s *Store) GetInvitationsByEmail(ctx context.Context, inviteeEmail string, limit, offset int) ([]*Invitation, int, error) {
+	return s.listInvitations(ctx, "invitee_email = $1", []interface{}{inviteeEmail}, limit, offset)
+}
+
+// GetSentByInviter returns invitations sent by inviterID across all events,
+// newest first, along with the total number of matching rows.
+func (s *Store) GetSentByInviter(ctx context.Context, inviterID int64, limit, offset int) ([]*Invitation, int, error) {
+	return s.listInvitations(ctx, "inviter_id = $1", []interface{}{inviterID}, limit, offset)
+}
+
+// GetPendingByEventID returns the still-pending invitations for eventID,
+// newest first, along with the total number of matching rows.
+func (s *Store) GetPendingByEventID(ctx context.Context, eventID int64, limit, offset int) ([]*Invitation, int, error) {
+	return s.listInvitations(ctx, "event_id = $1 AND status = $2", []interface{}{eventID, StatusPending}, limit, offset)
+}
+
+// InvitationCounts summarizes how many invitations an invitee has in each
+// resolved state; see CountByEmail.
+type InvitationCounts struct {
+	Pending  int
+	Accepted int
+	Declined int
+}
+
+// CountByEmail returns how many invitations sent to inviteeEmail are
+// pending, accepted, or declined, computed with a single aggregate query
+// so a client can render a pending-invitations badge without paging
+// through GetInvitationsByEmail. Expired and revoked invitations aren't
+// counted in any of the three.
+func (s *Store) CountByEmail(ctx context.Context, inviteeEmail string) (InvitationCounts, error) {
+	var counts InvitationCounts
+	row := s.db.QueryRowContext(ctx,
+		`SELECT
+			count(*) FILTER (WHERE status = $2),
+			count(*) FILTER (WHERE status = $3),
+			count(*) FILTER (WHERE status = $4)
+		 FROM invitations WHERE invitee_email = $1`,
+		inviteeEmail, StatusPending, StatusAccepted, StatusDeclined,
+	)
+	if err := row.Scan(&counts.Pending, &counts.Accepted, &counts.Declined); err != nil {
+		return InvitationCounts{}, fmt.Errorf("invitations: count by email: %w", err)
+	}
+	return counts, nil
+}
+
+// pagedListQuery builds the shared SELECT used by every invitation listing
+// method: the same deterministic ORDER BY with limit/offset placeholders
+// numbered after whatever positional args the WHERE clause already uses.
+func pagedListQuery(where string, whereArgCount int) string {
+	return fmt.Sprintf(
+		`SELECT id, event_id, inviter_id, invitee_email, invitee_user_id, status, role, created_at, accepted_at, expires_at
+		 FROM invitations WHERE (%s) AND deleted_at IS NULL
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT $%d OFFSET $%d`,
+		where, whereArgCount+1, whereArgCount+2,
+	)
+}
+
+// listInvitations is the shared paging and ordering implementation behind
+// every invitation listing method: a deterministic ORDER BY (created_at
+// DESC, id DESC), limit/offset, and a total count of matching rows.
+func (s *Store) listInvitations(ctx context.Context, where string, args []interface{}, limit, offset int) ([]*Invitation, int, error) {
+	var total int
+	countRow := s.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT count(*) FROM invitations WHERE (%s) AND deleted_at IS NULL`, where), args...,
+	)
+	if err := countRow.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("invitations: count: %w", err)
+	}
+
+	pageArgs := append(append([]interface{}{}, args...), limit, offset)
+	rows, err := s.db.QueryContext(ctx, pagedListQuery(where, len(args)), pageArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invitations: list: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Invitation
+	for rows.Next() {
+		inv := &Invitation{}
+		if err := rows.Scan(&inv.ID, &inv.EventID, &inv.InviterID, &inv.InviteeEmail,
+			&inv.InviteeUserID, &inv.Status, &inv.Role, &inv.CreatedAt, &inv.AcceptedAt, &inv.ExpiresAt); err != nil {
+			return nil, 0, fmt.Errorf("invitations: list scan: %w", err)
+		}
+		out = append(out, inv)
+	}
+	return out, total, rows.Err()
+}
+
+func (s *Store) get(ctx context.Context, id int64) (*Invitation, error) {
+	inv := &Invitation{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, event_id, inviter_id, invitee_email, invitee_user_id, status, role, created_at, accepted_at, expires_at
+		 FROM invitations WHERE id = $1 AND deleted_at IS NULL`, id,
+	)
+	if err := row.Scan(&inv.ID, &inv.EventID, &inv.InviterID, &inv.InviteeEmail,
+		&inv.InviteeUserID, &inv.Status, &inv.Role, &inv.CreatedAt, &inv.AcceptedAt, &inv.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("invitations: get: %w", err)
+	}
+	return inv, nil
+}
+
+// CanManage reports whether userID may edit eventID's details and invite
+// attendees to it: either because they organize it, or because they hold
+// an accepted RoleCollaborator or RoleOrganizer invitation to it. Only the
+// sole recorded organizer may delete or transfer the event; that is not
+// granted here, even to an accepted RoleOrganizer co-organizer.
+func (s *Store) CanManage(ctx context.Context, eventID, userID int64) (bool, error) {
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return false, err
+	}
+	if e.OrganizerID == userID {
+		return true, nil
+	}
+
+	var exists bool
+	row := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM invitations
+			WHERE event_id = $1 AND invitee_user_id = $2 AND status = $3 AND role IN ($4, $5)
+		 )`,
+		eventID, userID, StatusAccepted, RoleCollaborator, RoleOrganizer,
+	)
+	if err := row.Scan(&exists); err != nil {
+		return false, fmt.Errorf("invitations: can manage: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,173 @@
+// Package invitationstest provides an in-memory invitations.Repository for
+// tests that exercise code depending on the interface without a live
+// Postgres database, in the style of http.RoundTripper test doubles: each
+// method delegates to an optional function field, falling back to zero
+// values when that field is left nil.
+package invitationstest
+
+import (
+	"context"
+
+	"github.com/dohasheriff/tools-3/internal/invitations"
+)
+
+// MockRepository implements invitations.Repository. Set only the *Func
+// fields a test needs; calling an unset method returns zero values
+// rather than panicking, so tests that don't care about a dependency can
+// ignore it.
+type MockRepository struct {
+	ExpireStaleInvitationsFunc  func(ctx context.Context) (int, error)
+	InviteUserToEventFunc       func(ctx context.Context, eventID, inviterID int64, inviteeEmail, role string) (*invitations.Invitation, error)
+	InviteRegisteredUserFunc    func(ctx context.Context, eventID, inviterID, userID int64, role string) (*invitations.Invitation, error)
+	InviteByUsernameFunc        func(ctx context.Context, eventID, inviterID int64, username, role string) (*invitations.Invitation, error)
+	AcceptFunc                  func(ctx context.Context, invitationID, userID int64, force bool) error
+	DeclineFunc                 func(ctx context.Context, invitationID int64, email string) error
+	RevokeFunc                  func(ctx context.Context, invitationID, actorID int64) error
+	GetInvitationsByEventIDFunc func(ctx context.Context, eventID int64, limit, offset int) ([]*invitations.Invitation, int, error)
+	GetInvitationsByEmailFunc   func(ctx context.Context, inviteeEmail string, limit, offset int) ([]*invitations.Invitation, int, error)
+	GetSentByInviterFunc        func(ctx context.Context, inviterID int64, limit, offset int) ([]*invitations.Invitation, int, error)
+	GetPendingByEventIDFunc     func(ctx context.Context, eventID int64, limit, offset int) ([]*invitations.Invitation, int, error)
+	CountByEmailFunc            func(ctx context.Context, inviteeEmail string) (invitations.InvitationCounts, error)
+	CanManageFunc               func(ctx context.Context, eventID, userID int64) (bool, error)
+	SendDueRemindersFunc        func(ctx context.Context) (int, error)
+	SetInvitationTemplateFunc   func(ctx context.Context, eventID, actorID int64, subject, body string) error
+	AcceptWithTokenFunc         func(ctx context.Context, token string) error
+	DeclineWithTokenFunc        func(ctx context.Context, token string) error
+	DeleteForEventFunc          func(ctx context.Context, eventID int64) error
+	RestoreForEventFunc         func(ctx context.Context, eventID int64) error
+}
+
+var _ invitations.Repository = (*MockRepository)(nil)
+
+func (m *MockRepository) ExpireStaleInvitations(ctx context.Context) (int, error) {
+	if m.ExpireStaleInvitationsFunc != nil {
+		return m.ExpireStaleInvitationsFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockRepository) InviteUserToEvent(ctx context.Context, eventID, inviterID int64, inviteeEmail, role string) (*invitations.Invitation, error) {
+	if m.InviteUserToEventFunc != nil {
+		return m.InviteUserToEventFunc(ctx, eventID, inviterID, inviteeEmail, role)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) InviteRegisteredUser(ctx context.Context, eventID, inviterID, userID int64, role string) (*invitations.Invitation, error) {
+	if m.InviteRegisteredUserFunc != nil {
+		return m.InviteRegisteredUserFunc(ctx, eventID, inviterID, userID, role)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) InviteByUsername(ctx context.Context, eventID, inviterID int64, username, role string) (*invitations.Invitation, error) {
+	if m.InviteByUsernameFunc != nil {
+		return m.InviteByUsernameFunc(ctx, eventID, inviterID, username, role)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Accept(ctx context.Context, invitationID, userID int64, force bool) error {
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(ctx, invitationID, userID, force)
+	}
+	return nil
+}
+
+func (m *MockRepository) Decline(ctx context.Context, invitationID int64, email string) error {
+	if m.DeclineFunc != nil {
+		return m.DeclineFunc(ctx, invitationID, email)
+	}
+	return nil
+}
+
+func (m *MockRepository) Revoke(ctx context.Context, invitationID, actorID int64) error {
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(ctx, invitationID, actorID)
+	}
+	return nil
+}
+
+func (m *MockRepository) GetInvitationsByEventID(ctx context.Context, eventID int64, limit, offset int) ([]*invitations.Invitation, int, error) {
+	if m.GetInvitationsByEventIDFunc != nil {
+		return m.GetInvitationsByEventIDFunc(ctx, eventID, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockRepository) GetInvitationsByEmail(ctx context.Context, inviteeEmail string, limit, offset int) ([]*invitations.Invitation, int, error) {
+	if m.GetInvitationsByEmailFunc != nil {
+		return m.GetInvitationsByEmailFunc(ctx, inviteeEmail, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockRepository) GetSentByInviter(ctx context.Context, inviterID int64, limit, offset int) ([]*invitations.Invitation, int, error) {
+	if m.GetSentByInviterFunc != nil {
+		return m.GetSentByInviterFunc(ctx, inviterID, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockRepository) GetPendingByEventID(ctx context.Context, eventID int64, limit, offset int) ([]*invitations.Invitation, int, error) {
+	if m.GetPendingByEventIDFunc != nil {
+		return m.GetPendingByEventIDFunc(ctx, eventID, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockRepository) CountByEmail(ctx context.Context, inviteeEmail string) (invitations.InvitationCounts, error) {
+	if m.CountByEmailFunc != nil {
+		return m.CountByEmailFunc(ctx, inviteeEmail)
+	}
+	return invitations.InvitationCounts{}, nil
+}
+
+func (m *MockRepository) CanManage(ctx context.Context, eventID, userID int64) (bool, error) {
+	if m.CanManageFunc != nil {
+		return m.CanManageFunc(ctx, eventID, userID)
+	}
+	return false, nil
+}
+
+func (m *MockRepository) SendDueReminders(ctx context.Context) (int, error) {
+	if m.SendDueRemindersFunc != nil {
+		return m.SendDueRemindersFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockRepository) SetInvitationTemplate(ctx context.Context, eventID, actorID int64, subject, body string) error {
+	if m.SetInvitationTemplateFunc != nil {
+		return m.SetInvitationTemplateFunc(ctx, eventID, actorID, subject, body)
+	}
+	return nil
+}
+
+func (m *MockRepository) AcceptWithToken(ctx context.Context, token string) error {
+	if m.AcceptWithTokenFunc != nil {
+		return m.AcceptWithTokenFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockRepository) DeclineWithToken(ctx context.Context, token string) error {
+	if m.DeclineWithTokenFunc != nil {
+		return m.DeclineWithTokenFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockRepository) DeleteForEvent(ctx context.Context, eventID int64) error {
+	if m.DeleteForEventFunc != nil {
+		return m.DeleteForEventFunc(ctx, eventID)
+	}
+	return nil
+}
+
+func (m *MockRepository) RestoreForEvent(ctx context.Context, eventID int64) error {
+	if m.RestoreForEventFunc != nil {
+		return m.RestoreForEventFunc(ctx, eventID)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package invitations
+
+import (
+	"context"
+	"log"
+)
+
+// Mailer delivers a single email. It is an interface, the same as
+// auth.Mailer, so tests and local development can swap in a no-op or
+// logging implementation without a real mail provider.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+	// SendICS delivers an email with a calendar invite attached: icsData
+	// is a full iCalendar document (see internal/icalendar) with the
+	// given METHOD, attached as filename.
+	SendICS(ctx context.Context, to, subject, body, method, filename string, icsData []byte) error
+}
+
+// LogMailer logs emails instead of sending them. It is the default Mailer
+// until a real provider is wired in.
+type LogMailer struct{}
+
+// Send logs the email and always succeeds.
+func (LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("invitations: mail to=%s subject=%q body=%q", to, subject, body)
+	return nil
+}
+
+// SendICS logs the email and its attachment and always succeeds.
+func (LogMailer) SendICS(ctx context.Context, to, subject, body, method, filename string, icsData []byte) error {
+	log.Printf("invitations: mail to=%s subject=%q body=%q ics=%s method=%s (%d bytes)", to, subject, body, filename, method, len(icsData))
+	return nil
+}
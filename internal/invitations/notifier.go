@@ -0,0 +1,20 @@
+package invitations
+
+import "context"
+
+// Notifier creates an in-app/push notification for a user, the same
+// pluggable-dependency pattern as Mailer. It's declared here so SendDueReminders
+// can notify invitees without this package depending on the notifications
+// package directly; notifications.Store.Notify satisfies it.
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, kind, title, body string, eventID *int64) error
+}
+
+// NoopNotifier discards every notification. It is the default Notifier
+// until a real implementation is wired in.
+type NoopNotifier struct{}
+
+// Notify always succeeds without recording anything.
+func (NoopNotifier) Notify(ctx context.Context, userID int64, kind, title, body string, eventID *int64) error {
+	return nil
+}
@@ -0,0 +1,36 @@
+package invitations
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPagedListQuery_ConsistentOrderingAndPlaceholders(t *testing.T) {
+	cases := []struct {
+		name          string
+		where         string
+		whereArgCount int
+	}{
+		{"by event", "event_id = $1", 1},
+		{"by email", "invitee_email = $1", 1},
+		{"by inviter", "inviter_id = $1", 1},
+		{"pending by event", "event_id = $1 AND status = $2", 2},
+	}
+
+	const wantOrderBy = "ORDER BY created_at DESC, id DESC"
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query := pagedListQuery(c.where, c.whereArgCount)
+			if !strings.Contains(query, wantOrderBy) {
+				t.Fatalf("query %q missing deterministic ordering %q", query, wantOrderBy)
+			}
+
+			wantLimitOffset := "LIMIT $" + strconv.Itoa(c.whereArgCount+1) + " OFFSET $" + strconv.Itoa(c.whereArgCount+2)
+			if !strings.Contains(query, wantLimitOffset) {
+				t.Fatalf("query %q does not place limit/offset after the WHERE args (want %q)", query, wantLimitOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package invitations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/icalendar"
+)
+
+// DefaultInviteReminderDays is how many days after an invite goes
+// unanswered SendDueReminders sends a first reminder, when the event
+// doesn't set its own events.Event.InviteReminderDays.
+const DefaultInviteReminderDays = 3
+
+// DeadlineReminderWindow is how far ahead of an event's RSVP deadline
+// SendDueReminders sends a second reminder to invitees who still haven't
+// responded.
+const DeadlineReminderWindow = 24 * time.Hour
+
+// dueReminder is a pending invitation, joined with the event fields its
+// reminder email needs.
+type dueReminder struct {
+	id            int64
+	eventID       int64
+	inviteeEmail  string
+	inviteeUserID *int64
+	eventTitle    string
+	startsAt      time.Time
+	endsAt        *time.Time
+	location      string
+	organizerID   int64
+}
+
+// SendDueReminders emails every still-pending invitee who is due a
+// reminder: a first nudge once invite_reminder_days (DefaultInviteReminderDays
+// if the event doesn't configure one) has passed since the invite was
+// sent, and a second nudge once the event's RSVP deadline is within
+// DeadlineReminderWindow. Each kind of reminder is sent at most once per
+// invitation, tracked by reminder_sent_at and deadline_reminder_sent_at.
+// It's meant to run periodically from a scheduled job, the same as
+// ExpireStaleInvitations, and returns the number of reminders sent.
+func (s *Store) SendDueReminders(ctx context.Context) (int, error) {
+	sent, err := s.sendFollowupReminders(ctx)
+	if err != nil {
+		return sent, err
+	}
+
+	deadlineSent, err := s.sendDeadlineReminders(ctx)
+	if err != nil {
+		return sent, err
+	}
+	return sent + deadlineSent, nil
+}
+
+// sendFollowupReminders emails invitees whose invite has gone unanswered
+// for at least the event's configured invite_reminder_days. Events and
+// invitations are joined directly by table name, the same tradeoff
+// events.viewerHasPrivateAccess takes to query invitations without
+// importing the package both ways.
+func (s *Store) sendFollowupReminders(ctx context.Context) (int, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT i.id, i.event_id, i.invitee_email, i.invitee_user_id, e.title, e.starts_at, e.ends_at, e.location, e.organizer_id
+		 FROM invitations i JOIN events e ON e.id = i.event_id
+		 WHERE i.status = $1 AND i.reminder_sent_at IS NULL
+		   AND i.created_at <= now() - make_interval(days => COALESCE(e.invite_reminder_days, $2))`,
+		StatusPending, DefaultInviteReminderDays,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("invitations: due followup reminders: %w", err)
+	}
+	due, err := scanDueReminders(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.sendReminders(ctx, due, "reminder_sent_at", func(e *dueReminder) (string, string) {
+		return fmt.Sprintf("You haven't responded to %s yet", e.eventTitle), fmt.Sprintf(
+			"<p>You were invited to <strong>%s</strong>, starting %s at %s, and haven't responded yet.</p>",
+			e.eventTitle, e.startsAt.Format(time.RFC1123), e.location,
+		)
+	})
+}
+
+// sendDeadlineReminders emails invitees who still haven't responded as an
+// event's RSVP deadline approaches within DeadlineReminderWindow.
+func (s *Store) sendDeadlineReminders(ctx context.Context) (int, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT i.id, i.event_id, i.invitee_email, i.invitee_user_id, e.title, e.starts_at, e.ends_at, e.location, e.organizer_id
+		 FROM invitations i JOIN events e ON e.id = i.event_id
+		 WHERE i.status = $1 AND i.deadline_reminder_sent_at IS NULL
+		   AND e.rsvp_deadline IS NOT NULL AND e.rsvp_deadline > $2 AND e.rsvp_deadline <= $3`,
+		StatusPending, time.Now(), time.Now().Add(DeadlineReminderWindow),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("invitations: due deadline reminders: %w", err)
+	}
+	due, err := scanDueReminders(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.sendReminders(ctx, due, "deadline_reminder_sent_at", func(e *dueReminder) (string, string) {
+		return fmt.Sprintf("RSVP deadline approaching for %s", e.eventTitle), fmt.Sprintf(
+			"<p>The RSVP deadline for <strong>%s</strong>, starting %s at %s, is coming up and you haven't responded yet.</p>",
+			e.eventTitle, e.startsAt.Format(time.RFC1123), e.location,
+		)
+	})
+}
+
+// sendReminders emails each of due through s.mailer using subjectAndBody,
+// notifies it through s.notifier if it belongs to a registered account,
+// and stamps stampColumn on every invitation it successfully emails, so a
+// later run doesn't resend it. It returns the number of reminders sent.
+// Rows are collected before any writes so a row isn't held open across the
+// emails and updates that follow.
+func (s *Store) sendReminders(ctx context.Context, due []*dueReminder, stampColumn string, subjectAndBody func(*dueReminder) (string, string)) (int, error) {
+	sent := 0
+	for _, r := range due {
+		subject, body := subjectAndBody(r)
+
+		organizer, err := s.users.GetByID(ctx, r.organizerID)
+		if err != nil {
+			return sent, fmt.Errorf("invitations: load organizer for reminder email: %w", err)
+		}
+
+		ics := icalendar.Build(icalendar.MethodRequest, icalendar.Event{
+			UID:            fmt.Sprintf("event-%d@tools-3", r.eventID),
+			Title:          r.eventTitle,
+			Location:       r.location,
+			StartsAt:       r.startsAt,
+			EndsAt:         r.endsAt,
+			OrganizerEmail: organizer.Email,
+			OrganizerName:  organizer.DisplayName,
+			AttendeeEmail:  r.inviteeEmail,
+		})
+
+		if err := s.mailer.SendICS(ctx, r.inviteeEmail, subject, body, string(icalendar.MethodRequest), "reminder.ics", ics); err != nil {
+			return sent, fmt.Errorf("invitations: send reminder: %w", err)
+		}
+		if r.inviteeUserID != nil {
+			if err := s.notifier.Notify(ctx, *r.inviteeUserID, "invitation_reminder", subject, body, &r.eventID); err != nil {
+				return sent, fmt.Errorf("invitations: notify reminder: %w", err)
+			}
+		}
+		if _, err := s.db.ExecContext(ctx,
+			fmt.Sprintf(`UPDATE invitations SET %s = now() WHERE id = $1`, stampColumn),
+			r.id,
+		); err != nil {
+			return sent, fmt.Errorf("invitations: stamp reminder: %w", err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// scanDueReminders collects rows into dueReminder values and closes rows.
+func scanDueReminders(rows *sql.Rows) ([]*dueReminder, error) {
+	defer rows.Close()
+
+	var due []*dueReminder
+	for rows.Next() {
+		r := &dueReminder{}
+		if err := rows.Scan(&r.id, &r.eventID, &r.inviteeEmail, &r.inviteeUserID, &r.eventTitle, &r.startsAt, &r.endsAt, &r.location, &r.organizerID); err != nil {
+			return nil, fmt.Errorf("invitations: scan due reminder: %w", err)
+		}
+		due = append(due, r)
+	}
+	return due, rows.Err()
+}
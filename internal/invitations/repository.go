@@ -0,0 +1,33 @@
+package invitations
+
+import "context"
+
+// Repository is the persistence interface invitations' own HTTP-facing
+// callers (internal/httpapi) depend on. *Store is the Postgres-backed
+// implementation used in production; tests can substitute an in-memory
+// implementation instead of requiring a live database. The method set
+// mirrors Store's full exported API, so Repository and Store are expected
+// to evolve together.
+type Repository interface {
+	ExpireStaleInvitations(ctx context.Context) (int, error)
+	InviteUserToEvent(ctx context.Context, eventID, inviterID int64, inviteeEmail, role string) (*Invitation, error)
+	InviteRegisteredUser(ctx context.Context, eventID, inviterID, userID int64, role string) (*Invitation, error)
+	InviteByUsername(ctx context.Context, eventID, inviterID int64, username, role string) (*Invitation, error)
+	Accept(ctx context.Context, invitationID, userID int64, force bool) error
+	Decline(ctx context.Context, invitationID int64, email string) error
+	Revoke(ctx context.Context, invitationID, actorID int64) error
+	GetInvitationsByEventID(ctx context.Context, eventID int64, limit, offset int) ([]*Invitation, int, error)
+	GetInvitationsByEmail(ctx context.Context, inviteeEmail string, limit, offset int) ([]*Invitation, int, error)
+	GetSentByInviter(ctx context.Context, inviterID int64, limit, offset int) ([]*Invitation, int, error)
+	GetPendingByEventID(ctx context.Context, eventID int64, limit, offset int) ([]*Invitation, int, error)
+	CountByEmail(ctx context.Context, inviteeEmail string) (InvitationCounts, error)
+	CanManage(ctx context.Context, eventID, userID int64) (bool, error)
+	SendDueReminders(ctx context.Context) (int, error)
+	SetInvitationTemplate(ctx context.Context, eventID, actorID int64, subject, body string) error
+	AcceptWithToken(ctx context.Context, token string) error
+	DeclineWithToken(ctx context.Context, token string) error
+	DeleteForEvent(ctx context.Context, eventID int64) error
+	RestoreForEvent(ctx context.Context, eventID int64) error
+}
+
+var _ Repository = (*Store)(nil)
@@ -0,0 +1,53 @@
+package invitations
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPMailer sends email through an SMTP relay authenticated with PLAIN
+// credentials, such as a transactional email provider's SMTP endpoint.
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer returns an SMTPMailer that authenticates to addr
+// ("host:port") as username/password and sends mail as from.
+func NewSMTPMailer(addr, username, password, from string) *SMTPMailer {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return &SMTPMailer{addr: addr, from: from, auth: smtp.PlainAuth("", username, password, host)}
+}
+
+// Send delivers an email to "to" over SMTP. ctx is accepted to satisfy
+// Mailer, but net/smtp has no context support to cancel the dial with.
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	msg := fmt.Sprintf("To: %s\r\nFrom: %s\r\nSubject: %s\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s",
+		to, m.from, subject, body)
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("invitations: send mail: %w", err)
+	}
+	return nil
+}
+
+// SendICS delivers an email to "to" with icsData attached as a calendar
+// invite, over SMTP.
+func (m *SMTPMailer) SendICS(ctx context.Context, to, subject, body, method, filename string, icsData []byte) error {
+	const boundary = "tools3-ics-boundary"
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "To: %s\r\nFrom: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%s\r\n\r\n", to, m.from, subject, boundary)
+	fmt.Fprintf(&msg, "--%s\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s\r\n\r\n", boundary, body)
+	fmt.Fprintf(&msg, "--%s\r\nContent-Type: text/calendar; method=%s; charset=UTF-8; name=%q\r\nContent-Disposition: attachment; filename=%q\r\n\r\n%s\r\n\r\n", boundary, method, filename, filename, icsData)
+	fmt.Fprintf(&msg, "--%s--\r\n", boundary)
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("invitations: send mail with ics: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package invitations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// InvitationTemplate is an organizer-defined subject and body used instead
+// of the default when sendInvitationEmail emails an invitee; see
+// SetInvitationTemplate. Subject and Body may reference {{event_title}},
+// {{event_location}}, {{event_starts_at}}, {{accept_url}}, and
+// {{decline_url}}, substituted verbatim by templatePlaceholders rather
+// than parsed as Go templates.
+type InvitationTemplate struct {
+	EventID int64
+	Subject string
+	Body    string
+}
+
+// SetInvitationTemplate sets eventID's custom invitation email template,
+// or clears it (reverting to the default) if subject and body are both
+// empty. The caller must be the event's organizer or an accepted
+// collaborator; see CanManage.
+func (s *Store) SetInvitationTemplate(ctx context.Context, eventID, actorID int64, subject, body string) error {
+	canManage, err := s.CanManage(ctx, eventID, actorID)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return ErrForbidden
+	}
+
+	if subject == "" && body == "" {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM invitation_templates WHERE event_id = $1`, eventID); err != nil {
+			return fmt.Errorf("invitations: clear invitation template: %w", err)
+		}
+		return nil
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO invitation_templates (event_id, subject, body) VALUES ($1, $2, $3)
+		 ON CONFLICT (event_id) DO UPDATE SET subject = $2, body = $3`,
+		eventID, subject, body,
+	)
+	if err != nil {
+		return fmt.Errorf("invitations: set invitation template: %w", err)
+	}
+	return nil
+}
+
+// invitationTemplate returns eventID's custom invitation template, or nil
+// if it hasn't set one.
+func (s *Store) invitationTemplate(ctx context.Context, eventID int64) (*InvitationTemplate, error) {
+	t := &InvitationTemplate{EventID: eventID}
+	row := s.db.QueryRowContext(ctx, `SELECT subject, body FROM invitation_templates WHERE event_id = $1`, eventID)
+	if err := row.Scan(&t.Subject, &t.Body); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invitations: get invitation template: %w", err)
+	}
+	return t, nil
+}
+
+// templatePlaceholders returns the replacer used to substitute event and
+// link details into a custom invitation template's subject and body.
+func templatePlaceholders(e *events.Event, acceptURL, declineURL string) *strings.Replacer {
+	return strings.NewReplacer(
+		"{{event_title}}", e.Title,
+		"{{event_location}}", e.Location,
+		"{{event_starts_at}}", e.StartsAt.Format(time.RFC1123),
+		"{{accept_url}}", acceptURL,
+		"{{decline_url}}", declineURL,
+	)
+}
@@ -0,0 +1,134 @@
+package invitations
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// ErrInvalidToken is returned by AcceptWithToken and DeclineWithToken when
+// the presented token is unknown or already used.
+var ErrInvalidToken = apperr.Wrap(apperr.ErrNotFound, "invitations: invalid or already-used token")
+
+// generateToken returns a random token for InviteUserToEvent to send in an
+// invitation email, the same way auth generates magic link tokens.
+func generateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("invitations: generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashInvitationToken returns the value stored alongside a token so the raw
+// token never needs to be kept at rest, the same as auth.hashToken.
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// invitationByToken looks up the unused invitation matching token's hash.
+func (s *Store) invitationByToken(ctx context.Context, token string) (*Invitation, error) {
+	inv := &Invitation{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, event_id, inviter_id, invitee_email, invitee_user_id, status, role, created_at, accepted_at, expires_at
+		 FROM invitations WHERE token_hash = $1 AND token_used_at IS NULL`,
+		hashInvitationToken(token),
+	)
+	if err := row.Scan(&inv.ID, &inv.EventID, &inv.InviterID, &inv.InviteeEmail,
+		&inv.InviteeUserID, &inv.Status, &inv.Role, &inv.CreatedAt, &inv.AcceptedAt, &inv.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("invitations: lookup by token: %w", err)
+	}
+	return inv, nil
+}
+
+// AcceptWithToken accepts the invitation carrying token without requiring
+// the invitee to be signed in. If the invitation names a registered
+// account (InviteeUserID is set, as InviteRegisteredUser and
+// InviteByUsername do), that account is joined directly; see
+// events.Store.JoinEventInTx. Otherwise, since there's no userID yet, the
+// invitee is recorded as a provisional attendee by email, the same as an
+// organizer adding one directly; see events.Store.AddAttendeeByEmailInTx.
+// The provisional attendance is attached to their account automatically
+// once they register or log in with this email, through the existing
+// events.Store.ClaimProvisionalAttendance flow. The token is single-use
+// regardless of whether the invitation was already accepted or declined.
+// Both writes commit in a single transaction, the same as Accept.
+// Acceptance is rejected with ErrInvitationExpired once the invitation's
+// ExpiresAt has passed.
+func (s *Store) AcceptWithToken(ctx context.Context, token string) error {
+	inv, err := s.invitationByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if inv.ExpiresAt != nil && time.Now().After(*inv.ExpiresAt) {
+		return ErrInvitationExpired
+	}
+
+	e, err := s.events.Get(ctx, inv.EventID)
+	if err != nil {
+		return err
+	}
+	if !e.RSVPOpen {
+		return events.ErrRSVPDeadlinePassed
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("invitations: accept with token: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if inv.InviteeUserID != nil {
+		if _, err := s.events.JoinEventInTx(ctx, tx, inv.EventID, *inv.InviteeUserID, events.StatusGoing); err != nil {
+			return err
+		}
+	} else if err := s.events.AddAttendeeByEmailInTx(ctx, tx, inv.EventID, inv.InviteeEmail, events.StatusGoing); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE invitations SET status = $1, accepted_at = now(), token_used_at = now() WHERE id = $2`,
+		StatusAccepted, inv.ID,
+	); err != nil {
+		return fmt.Errorf("invitations: accept with token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("invitations: accept with token: commit: %w", err)
+	}
+	return nil
+}
+
+// DeclineWithToken declines the invitation carrying token without requiring
+// the invitee to be signed in. It is rejected with ErrInvitationExpired
+// once the invitation's ExpiresAt has passed.
+func (s *Store) DeclineWithToken(ctx context.Context, token string) error {
+	inv, err := s.invitationByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if inv.ExpiresAt != nil && time.Now().After(*inv.ExpiresAt) {
+		return ErrInvitationExpired
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE invitations SET status = $1, token_used_at = now() WHERE id = $2`,
+		StatusDeclined, inv.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("invitations: decline with token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Package logging provides a request-scoped structured logger, threaded
+// through context.Context so handlers, services, and repositories can log
+// with the same request ID and attributes without passing a logger as an
+// explicit parameter everywhere.
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey int
+
+const loggerContextKey contextKey = 0
+
+// NewContext returns a copy of ctx carrying logger, retrievable with
+// FromContext.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, logger)
+}
+
+// FromContext returns the logger attached to ctx by NewContext, or
+// slog.Default() if none was attached.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,165 @@
+// Package msgraphcalendar connects a user's account to their Outlook
+// calendar via Microsoft Graph, mirroring internal/googlecalendar: tokens
+// are stored in the same provider-discriminated calendar_oauth_states and
+// calendar_connections tables rather than in internal/auth's login-only
+// oauth_states, since a calendar connection needs long-lived, per-user
+// offline access.
+package msgraphcalendar
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/microsoft"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// provider identifies Microsoft Graph connections in the shared
+// calendar_* tables.
+const provider = "microsoft"
+
+const oauthStateTTL = 10 * time.Minute
+
+// ErrInvalidState is returned by CompleteConnect when the state parameter
+// does not match one issued by StartConnect.
+var ErrInvalidState = errors.New("msgraphcalendar: invalid oauth state")
+
+// ErrNoRefreshToken is returned by CompleteConnect when Microsoft did not
+// issue a refresh token.
+var ErrNoRefreshToken = errors.New("msgraphcalendar: no refresh token issued")
+
+// Store manages per-user Microsoft Graph calendar connections and syncs
+// events into them.
+type Store struct {
+	db     *sql.DB
+	events *events.Store
+	config *oauth2.Config
+	client *http.Client
+}
+
+// NewStore returns a Store that authenticates against Microsoft Graph
+// using the given OAuth2 client credentials and Azure AD tenant.
+func NewStore(db *sql.DB, eventStore *events.Store, clientID, clientSecret, redirectURL, tenantID string) *Store {
+	if tenantID == "" {
+		tenantID = "common"
+	}
+	return &Store{
+		db:     db,
+		events: eventStore,
+		config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"https://graph.microsoft.com/Calendars.ReadWrite", "offline_access"},
+			Endpoint:     microsoft.AzureADEndpoint(tenantID),
+		},
+		client: http.DefaultClient,
+	}
+}
+
+// StartConnect begins connecting userID's Outlook calendar, returning the
+// URL the caller should send the user's browser to.
+func (s *Store) StartConnect(ctx context.Context, userID int64) (string, error) {
+	state, err := generateState()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO calendar_oauth_states (state, user_id, provider, expires_at) VALUES ($1, $2, $3, $4)`,
+		state, userID, provider, time.Now().Add(oauthStateTTL),
+	); err != nil {
+		return "", fmt.Errorf("msgraphcalendar: store oauth state: %w", err)
+	}
+
+	return s.config.AuthCodeURL(state, oauth2.AccessTypeOffline), nil
+}
+
+// CompleteConnect finishes a connection started by StartConnect, exchanging
+// code for a token pair and persisting it against the user who started the
+// flow.
+func (s *Store) CompleteConnect(ctx context.Context, state, code string) error {
+	userID, valid, err := s.consumeState(ctx, state)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidState
+	}
+
+	token, err := s.config.Exchange(ctx, code)
+	if err != nil {
+		return fmt.Errorf("msgraphcalendar: oauth exchange: %w", err)
+	}
+	if token.RefreshToken == "" {
+		return ErrNoRefreshToken
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO calendar_connections (user_id, provider, access_token, refresh_token, token_expiry)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id, provider) DO UPDATE SET
+		   access_token = EXCLUDED.access_token,
+		   refresh_token = EXCLUDED.refresh_token,
+		   token_expiry = EXCLUDED.token_expiry`,
+		userID, provider, token.AccessToken, token.RefreshToken, token.Expiry,
+	); err != nil {
+		return fmt.Errorf("msgraphcalendar: store connection: %w", err)
+	}
+	return nil
+}
+
+// Disconnect removes userID's Microsoft Graph calendar connection and any
+// record of events synced to it. It is not an error to disconnect an
+// account that was never connected.
+func (s *Store) Disconnect(ctx context.Context, userID int64) error {
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM calendar_synced_events WHERE user_id = $1 AND provider = $2`,
+		userID, provider,
+	); err != nil {
+		return fmt.Errorf("msgraphcalendar: clear synced events: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM calendar_connections WHERE user_id = $1 AND provider = $2`,
+		userID, provider,
+	); err != nil {
+		return fmt.Errorf("msgraphcalendar: delete connection: %w", err)
+	}
+	return nil
+}
+
+// consumeState deletes and validates a one-time state value, returning the
+// user ID it was issued for and whether it was a live, unexpired state.
+func (s *Store) consumeState(ctx context.Context, state string) (int64, bool, error) {
+	row := s.db.QueryRowContext(ctx,
+		`DELETE FROM calendar_oauth_states WHERE state = $1 AND provider = $2 RETURNING user_id, expires_at`,
+		state, provider,
+	)
+	var userID int64
+	var expiresAt time.Time
+	switch err := row.Scan(&userID, &expiresAt); {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("msgraphcalendar: consume oauth state: %w", err)
+	}
+	return userID, time.Now().Before(expiresAt), nil
+}
+
+// generateState returns a short random token used as the OAuth2 state
+// parameter to defend against CSRF on the callback.
+func generateState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("msgraphcalendar: generate oauth state: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
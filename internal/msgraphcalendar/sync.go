@@ -0,0 +1,236 @@
+package msgraphcalendar
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+const calendarEventsURL = "https://graph.microsoft.com/v1.0/me/events"
+
+// graphEvent is the subset of the Microsoft Graph event resource this
+// package reads and writes.
+type graphEvent struct {
+	ID       string              `json:"id,omitempty"`
+	Subject  string              `json:"subject"`
+	Body     *graphEventBody     `json:"body,omitempty"`
+	Location *graphEventLocation `json:"location,omitempty"`
+	Start    graphDateTime       `json:"start"`
+	End      graphDateTime       `json:"end"`
+}
+
+type graphEventBody struct {
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+type graphEventLocation struct {
+	DisplayName string `json:"displayName"`
+}
+
+// graphDateTime is the Graph DateTimeTimeZone type.
+type graphDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// PushEvent creates or updates eventID in userID's connected Outlook
+// calendar. It is a no-op if userID has no connection.
+func (s *Store) PushEvent(ctx context.Context, userID, eventID int64) error {
+	client, connected, err := s.httpClient(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !connected {
+		return nil
+	}
+
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	body := graphEvent{
+		Subject:  e.Title,
+		Body:     &graphEventBody{ContentType: "text", Content: e.Description},
+		Location: &graphEventLocation{DisplayName: e.Location},
+		Start:    graphDateTime{DateTime: e.StartsAt.UTC().Format("2006-01-02T15:04:05.0000000"), TimeZone: "UTC"},
+		End:      graphDateTime{DateTime: endTime(e).UTC().Format("2006-01-02T15:04:05.0000000"), TimeZone: "UTC"},
+	}
+
+	externalID, synced, err := s.syncedEvent(ctx, userID, eventID)
+	if err != nil {
+		return err
+	}
+
+	if !synced {
+		var created graphEvent
+		if err := doGraphEventRequest(ctx, client, http.MethodPost, calendarEventsURL, body, &created); err != nil {
+			return fmt.Errorf("msgraphcalendar: create event: %w", err)
+		}
+		return s.recordSync(ctx, userID, eventID, created.ID)
+	}
+
+	var updated graphEvent
+	if err := doGraphEventRequest(ctx, client, http.MethodPatch, calendarEventsURL+"/"+externalID, body, &updated); err != nil {
+		return fmt.Errorf("msgraphcalendar: update event: %w", err)
+	}
+	return s.recordSync(ctx, userID, eventID, externalID)
+}
+
+// RemoveEvent deletes eventID from userID's connected Outlook calendar, if
+// it was ever synced there. It is a no-op if userID has no connection or
+// the event was never synced. Call this when a user declines an event
+// they previously RSVP'd going to, or when the organizer cancels it.
+func (s *Store) RemoveEvent(ctx context.Context, userID, eventID int64) error {
+	client, connected, err := s.httpClient(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !connected {
+		return nil
+	}
+
+	externalID, synced, err := s.syncedEvent(ctx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	if !synced {
+		return nil
+	}
+
+	if err := doGraphEventRequest(ctx, client, http.MethodDelete, calendarEventsURL+"/"+externalID, nil, nil); err != nil {
+		return fmt.Errorf("msgraphcalendar: delete event: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM calendar_synced_events WHERE user_id = $1 AND event_id = $2 AND provider = $3`,
+		userID, eventID, provider,
+	); err != nil {
+		return fmt.Errorf("msgraphcalendar: clear synced event: %w", err)
+	}
+	return nil
+}
+
+// httpClient returns an OAuth2-authenticated client for userID, refreshing
+// and persisting its access token if it has expired. connected is false
+// if userID has no Microsoft Graph calendar connection.
+func (s *Store) httpClient(ctx context.Context, userID int64) (client *http.Client, connected bool, err error) {
+	var accessToken, refreshToken string
+	var expiry time.Time
+	row := s.db.QueryRowContext(ctx,
+		`SELECT access_token, refresh_token, token_expiry FROM calendar_connections WHERE user_id = $1 AND provider = $2`,
+		userID, provider,
+	)
+	switch err := row.Scan(&accessToken, &refreshToken, &expiry); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, fmt.Errorf("msgraphcalendar: load connection: %w", err)
+	}
+
+	stored := &oauth2.Token{AccessToken: accessToken, RefreshToken: refreshToken, Expiry: expiry}
+	fresh, err := s.config.TokenSource(ctx, stored).Token()
+	if err != nil {
+		return nil, false, fmt.Errorf("msgraphcalendar: refresh token: %w", err)
+	}
+	if fresh.AccessToken != stored.AccessToken {
+		if _, err := s.db.ExecContext(ctx,
+			`UPDATE calendar_connections SET access_token = $1, refresh_token = $2, token_expiry = $3
+			 WHERE user_id = $4 AND provider = $5`,
+			fresh.AccessToken, fresh.RefreshToken, fresh.Expiry, userID, provider,
+		); err != nil {
+			return nil, false, fmt.Errorf("msgraphcalendar: persist refreshed token: %w", err)
+		}
+	}
+
+	return s.config.Client(ctx, fresh), true, nil
+}
+
+// syncedEvent reports the external event ID previously recorded for
+// userID/eventID, if any.
+func (s *Store) syncedEvent(ctx context.Context, userID, eventID int64) (externalID string, synced bool, err error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT external_event_id FROM calendar_synced_events
+		 WHERE user_id = $1 AND event_id = $2 AND provider = $3`,
+		userID, eventID, provider,
+	)
+	switch err := row.Scan(&externalID); {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", false, nil
+	case err != nil:
+		return "", false, fmt.Errorf("msgraphcalendar: load synced event: %w", err)
+	}
+	return externalID, true, nil
+}
+
+// recordSync upserts the external event ID for userID/eventID.
+func (s *Store) recordSync(ctx context.Context, userID, eventID int64, externalID string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO calendar_synced_events (user_id, event_id, provider, external_event_id, synced_at)
+		 VALUES ($1, $2, $3, $4, now())
+		 ON CONFLICT (user_id, event_id, provider) DO UPDATE SET
+		   external_event_id = EXCLUDED.external_event_id,
+		   synced_at = now()`,
+		userID, eventID, provider, externalID,
+	); err != nil {
+		return fmt.Errorf("msgraphcalendar: record sync: %w", err)
+	}
+	return nil
+}
+
+// endTime returns e's end time, defaulting to one hour after it starts
+// when the event has none set.
+func endTime(e *events.Event) time.Time {
+	if e.EndsAt != nil {
+		return *e.EndsAt
+	}
+	return e.StartsAt.Add(time.Hour)
+}
+
+// doGraphEventRequest sends a Microsoft Graph events API request,
+// marshaling body (if non-nil) as the request JSON and unmarshaling the
+// response into out (if non-nil).
+func doGraphEventRequest(ctx context.Context, client *http.Client, method, url string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, respBody)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
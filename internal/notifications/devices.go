@@ -0,0 +1,148 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Device platforms stored on device_tokens.platform.
+const (
+	PlatformFCM = "fcm"
+	PlatformWeb = "web"
+)
+
+// DeviceToken is a single device or browser registered to receive push
+// notifications for a user.
+type DeviceToken struct {
+	ID     int64
+	UserID int64
+	// Platform is PlatformFCM or PlatformWeb.
+	Platform string
+	// Token is the FCM registration token; empty for PlatformWeb.
+	Token string
+	// Endpoint, P256dh, and Auth make up a W3C Push API subscription;
+	// empty for PlatformFCM.
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// RegisterDeviceInput carries the fields needed to register a device for
+// push notifications; see Store.RegisterDevice.
+type RegisterDeviceInput struct {
+	Platform string
+	Token    string
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// ErrInvalidPlatform is returned by RegisterDevice when Platform isn't
+// PlatformFCM or PlatformWeb.
+var ErrInvalidPlatform = errors.New("notifications: invalid device platform")
+
+// RegisterDevice records a device token (PlatformFCM) or Web Push
+// subscription (PlatformWeb) for userID, so future Notify calls also push
+// to it. Registering the same token again is a no-op rather than a
+// duplicate row.
+func (s *Store) RegisterDevice(ctx context.Context, userID int64, in RegisterDeviceInput) error {
+	if in.Platform != PlatformFCM && in.Platform != PlatformWeb {
+		return ErrInvalidPlatform
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO device_tokens (user_id, platform, token, endpoint, p256dh, auth)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (platform, token, endpoint) DO NOTHING`,
+		userID, in.Platform, in.Token, in.Endpoint, in.P256dh, in.Auth,
+	)
+	if err != nil {
+		return fmt.Errorf("notifications: register device: %w", err)
+	}
+	return nil
+}
+
+// devicesForUser returns userID's registered devices.
+func (s *Store) devicesForUser(ctx context.Context, userID int64) ([]DeviceToken, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, platform, token, endpoint, p256dh, auth FROM device_tokens WHERE user_id = $1`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("notifications: devices for user: %w", err)
+	}
+	defer rows.Close()
+
+	var out []DeviceToken
+	for rows.Next() {
+		var d DeviceToken
+		if err := rows.Scan(&d.ID, &d.UserID, &d.Platform, &d.Token, &d.Endpoint, &d.P256dh, &d.Auth); err != nil {
+			return nil, fmt.Errorf("notifications: devices for user scan: %w", err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// pushToUser sends title/body to every device userID has registered,
+// through s.pusher. Failures are logged and otherwise swallowed: a push
+// delivery problem shouldn't fail the in-app notification that triggered
+// it, the same tradeoff events.Store.geocodeAsync makes for geocoding.
+func (s *Store) pushToUser(ctx context.Context, userID int64, title, body string) {
+	devices, err := s.devicesForUser(ctx, userID)
+	if err != nil {
+		log.Printf("notifications: push to user %d: %v", userID, err)
+		return
+	}
+	for _, d := range devices {
+		if err := s.pusher.Send(ctx, d, title, body); err != nil {
+			log.Printf("notifications: push to device %d: %v", d.ID, err)
+		}
+	}
+}
+
+// Pusher delivers a push notification to a single device. It is an
+// interface, the same as Mailer in the invitations package, so tests and
+// local development can swap in a no-op implementation without a real
+// push provider.
+type Pusher interface {
+	Send(ctx context.Context, device DeviceToken, title, body string) error
+}
+
+// NoopPusher discards every push. It is the default Pusher until a real
+// provider is wired in.
+type NoopPusher struct{}
+
+// Send always succeeds without sending anything.
+func (NoopPusher) Send(ctx context.Context, device DeviceToken, title, body string) error {
+	return nil
+}
+
+// MultiPusher dispatches a push to FCM for PlatformFCM devices and to Web
+// Push for PlatformWeb devices. Either field may be left nil, in which
+// case devices of that platform are silently skipped, the same as if no
+// Pusher were configured for them.
+type MultiPusher struct {
+	FCM     *FCMSender
+	WebPush *WebPushSender
+}
+
+// Send dispatches device to whichever sender matches its Platform.
+func (p MultiPusher) Send(ctx context.Context, device DeviceToken, title, body string) error {
+	switch device.Platform {
+	case PlatformFCM:
+		if p.FCM == nil {
+			return nil
+		}
+		return p.FCM.Send(ctx, device, title, body)
+	case PlatformWeb:
+		if p.WebPush == nil {
+			return nil
+		}
+		return p.WebPush.Send(ctx, device, title, body)
+	default:
+		return fmt.Errorf("notifications: push: unknown device platform %q", device.Platform)
+	}
+}
@@ -0,0 +1,65 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const fcmLegacySendURL = "https://fcm.googleapis.com/fcm/send"
+
+// FCMSender pushes to PlatformFCM devices through Firebase Cloud
+// Messaging's legacy HTTP API, authenticated with a server key rather than
+// a service-account OAuth token, the same tradeoff tickets.StripeProvider
+// makes by talking to Stripe's REST API directly instead of pulling in a
+// full SDK.
+type FCMSender struct {
+	serverKey string
+	client    *http.Client
+}
+
+// NewFCMSender returns an FCMSender authenticated with serverKey, the
+// value found under Project Settings > Cloud Messaging > Server key.
+func NewFCMSender(serverKey string) *FCMSender {
+	return &FCMSender{serverKey: serverKey, client: http.DefaultClient}
+}
+
+type fcmMessage struct {
+	To           string                 `json:"to"`
+	Notification fcmNotificationPayload `json:"notification"`
+}
+
+type fcmNotificationPayload struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Send pushes title/body to device.Token through FCM.
+func (f *FCMSender) Send(ctx context.Context, device DeviceToken, title, body string) error {
+	payload, err := json.Marshal(fcmMessage{
+		To:           device.Token,
+		Notification: fcmNotificationPayload{Title: title, Body: body},
+	})
+	if err != nil {
+		return fmt.Errorf("notifications: marshal fcm message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fcmLegacySendURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("notifications: build fcm request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+f.serverKey)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notifications: send fcm push: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notifications: fcm push: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
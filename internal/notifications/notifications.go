@@ -0,0 +1,125 @@
+// Package notifications stores in-app notifications and exposes them for
+// an inbox-style UI. Notifications are created by the httpapi layer after a
+// domain action succeeds (an invite, an event update, a cancellation, an
+// RSVP change), rather than by the events or invitations packages
+// themselves, the same tradeoff events.Store.CancelEvent already makes by
+// returning attendee IDs instead of depending on a mailer.
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is returned when a notification lookup finds no matching row.
+var ErrNotFound = errors.New("notifications: not found")
+
+// ErrForbidden is returned by MarkRead when the notification belongs to a
+// different user.
+var ErrForbidden = errors.New("notifications: not permitted")
+
+// Notification is a single in-app notification delivered to a user.
+type Notification struct {
+	ID     int64
+	UserID int64
+	Kind   string
+	Title  string
+	Body   string
+	// EventID is the event the notification is about, or nil if it isn't
+	// tied to one.
+	EventID   *int64
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Store persists notifications in Postgres.
+type Store struct {
+	db     *sql.DB
+	pusher Pusher
+}
+
+// NewStore returns a Store backed by db, pushing to registered devices
+// through pusher.
+func NewStore(db *sql.DB, pusher Pusher) *Store {
+	return &Store{db: db, pusher: pusher}
+}
+
+// Notify creates a notification for userID and pushes it to any devices
+// userID has registered via RegisterDevice. kind is a free-form tag (e.g.
+// "invitation", "event_updated") chosen by the caller; this package
+// doesn't interpret it.
+func (s *Store) Notify(ctx context.Context, userID int64, kind, title, body string, eventID *int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO notifications (user_id, kind, title, body, event_id) VALUES ($1, $2, $3, $4, $5)`,
+		userID, kind, title, body, eventID,
+	)
+	if err != nil {
+		return fmt.Errorf("notifications: notify: %w", err)
+	}
+	s.pushToUser(ctx, userID, title, body)
+	return nil
+}
+
+// ListForUser returns userID's notifications, most recent first.
+func (s *Store) ListForUser(ctx context.Context, userID int64, limit, offset int) ([]*Notification, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, kind, title, body, event_id, read_at, created_at
+		 FROM notifications WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`,
+		userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("notifications: list for user: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Notification
+	for rows.Next() {
+		n := &Notification{}
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Title, &n.Body, &n.EventID, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("notifications: list for user scan: %w", err)
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+// MarkRead marks a single notification as read. userID must own it.
+func (s *Store) MarkRead(ctx context.Context, notificationID, userID int64) error {
+	var ownerID int64
+	row := s.db.QueryRowContext(ctx, `SELECT user_id FROM notifications WHERE id = $1`, notificationID)
+	if err := row.Scan(&ownerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("notifications: mark read: %w", err)
+	}
+	if ownerID != userID {
+		return ErrForbidden
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE notifications SET read_at = now() WHERE id = $1 AND read_at IS NULL`, notificationID,
+	); err != nil {
+		return fmt.Errorf("notifications: mark read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification belonging to userID as read,
+// and returns how many were updated.
+func (s *Store) MarkAllRead(ctx context.Context, userID int64) (int, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL`, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("notifications: mark all read: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("notifications: mark all read: %w", err)
+	}
+	return int(n), nil
+}
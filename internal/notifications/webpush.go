@@ -0,0 +1,116 @@
+package notifications
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// webPushTTLSeconds is both the VAPID JWT's lifetime and the value sent in
+// the Web Push TTL header, the maximum time the push service should hold
+// an undelivered notification.
+const webPushTTLSeconds = 12 * 60 * 60
+
+// WebPushSender pushes to PlatformWeb devices (browser push subscriptions)
+// using the VAPID protocol: a JWT signed with an ES256 key identifies the
+// application server to the push service. It sends no encrypted payload
+// (the aes128gcm Web Push content encryption scheme is not implemented
+// here); the browser receives a contentless push, and the installed
+// service worker is expected to fetch notification details from
+// GET /notifications, the same "silent push, fetch on wake" pattern many
+// installable web apps use instead of embedding the message in the push
+// itself.
+type WebPushSender struct {
+	privateKey *ecdsa.PrivateKey
+	publicKey  string
+	subject    string
+	client     *http.Client
+}
+
+// NewWebPushSender returns a WebPushSender that signs VAPID JWTs with
+// privateKey, advertises publicKey (the base64url-encoded uncompressed EC
+// point handed to browsers when they subscribe), and identifies the
+// application server as subject, a "mailto:" or "https:" contact URI as
+// required by the VAPID spec.
+func NewWebPushSender(privateKey *ecdsa.PrivateKey, publicKey, subject string) *WebPushSender {
+	return &WebPushSender{privateKey: privateKey, publicKey: publicKey, subject: subject, client: http.DefaultClient}
+}
+
+// ParseVAPIDPrivateKeyPEM parses an EC private key in SEC1 or PKCS#8 PEM
+// encoding, as produced by "openssl ecparam -genkey" or "openssl genpkey",
+// for use as a WebPushSender's signing key.
+func ParseVAPIDPrivateKeyPEM(pemBytes []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("notifications: no PEM block found in VAPID private key")
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("notifications: parse VAPID private key: %w", err)
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("notifications: PEM key is not an EC private key")
+	}
+	return ecKey, nil
+}
+
+// Send pushes a contentless notification to device's subscription
+// endpoint; see WebPushSender's doc comment for why no payload is sent.
+func (w *WebPushSender) Send(ctx context.Context, device DeviceToken, title, body string) error {
+	aud, err := webPushAudience(device.Endpoint)
+	if err != nil {
+		return fmt.Errorf("notifications: web push audience: %w", err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": aud,
+		"exp": time.Now().Add(webPushTTLSeconds * time.Second).Unix(),
+		"sub": w.subject,
+	})
+	signed, err := token.SignedString(w.privateKey)
+	if err != nil {
+		return fmt.Errorf("notifications: sign vapid jwt: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, device.Endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("notifications: build web push request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", signed, w.publicKey))
+	req.Header.Set("TTL", strconv.Itoa(webPushTTLSeconds))
+	req.Header.Set("Content-Length", "0")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notifications: send web push: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notifications: web push: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webPushAudience returns the scheme and host of a push subscription
+// endpoint, the "aud" claim VAPID JWTs must carry.
+func webPushAudience(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("notifications: parse push endpoint: %w", err)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
@@ -0,0 +1,168 @@
+// Package openapi builds the OpenAPI 3 document describing the HTTP API,
+// from the route table in routes.go rather than per-handler annotations,
+// so httpapi.handleOpenAPISpec has one document to serve at /openapi.json
+// and the Swagger UI at /docs has something to render.
+package openapi
+
+import (
+	"strings"
+)
+
+// Title and Version are reported in the generated document's info object.
+const (
+	Title   = "tools-3 API"
+	Version = "1.0"
+)
+
+// Document is the root of an OpenAPI 3.0 document. Only the fields this
+// package populates are included; it's not a general-purpose OpenAPI
+// model.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem groups the operations defined for one path, keyed by lowercase
+// HTTP method.
+type PathItem map[string]Operation
+
+type Operation struct {
+	Tags       []string            `json:"tags,omitempty"`
+	Summary    string              `json:"summary,omitempty"`
+	Parameters []Parameter         `json:"parameters,omitempty"`
+	Responses  map[string]Response `json:"responses"`
+	Security   []map[string][]any  `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+type Schema struct {
+	Type string `json:"type"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema Ref `json:"schema"`
+}
+
+// Ref points at a schema defined under Components.Schemas.
+type Ref struct {
+	Ref string `json:"$ref"`
+}
+
+type Components struct {
+	Schemas         map[string]ComponentSchema `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme  `json:"securitySchemes"`
+}
+
+type ComponentSchema struct {
+	Type       string                     `json:"type"`
+	Properties map[string]ComponentSchema `json:"properties,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+var errorSchema = ComponentSchema{
+	Type: "object",
+	Properties: map[string]ComponentSchema{
+		"error": {
+			Type: "object",
+			Properties: map[string]ComponentSchema{
+				"code":    {Type: "string"},
+				"message": {Type: "string"},
+				"details": {Type: "object"},
+			},
+		},
+	},
+}
+
+var bearerAuth = []map[string][]any{{"bearerAuth": {}}}
+var apiKeyAuth = []map[string][]any{{"apiKeyAuth": {}}}
+
+// Build generates the OpenAPI document for the routes registered in
+// httpapi.Router, from the route table in routes.go.
+func Build() *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: Title, Version: Version},
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: map[string]ComponentSchema{"Error": errorSchema},
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				"apiKeyAuth": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+
+	for _, rt := range routes {
+		item, ok := doc.Paths[rt.Path]
+		if !ok {
+			item = PathItem{}
+		}
+		item[strings.ToLower(rt.Method)] = buildOperation(rt)
+		doc.Paths[rt.Path] = item
+	}
+
+	return doc
+}
+
+func buildOperation(rt route) Operation {
+	op := Operation{
+		Tags:       []string{rt.Tag},
+		Summary:    rt.Summary,
+		Parameters: pathParameters(rt.Path),
+		Responses: map[string]Response{
+			"200": {Description: "Successful response"},
+			"default": {
+				Description: "Error response",
+				Content: map[string]MediaType{
+					"application/json": {Schema: Ref{Ref: "#/components/schemas/Error"}},
+				},
+			},
+		},
+	}
+
+	switch rt.Auth {
+	case authRequired, authOptional:
+		op.Security = bearerAuth
+	case authAPIKey:
+		op.Security = apiKeyAuth
+	}
+
+	return op
+}
+
+// pathParameters returns a Parameter for every {name} path segment in
+// path, e.g. "/events/{id}/attendees/{user_id}" yields parameters named
+// "id" and "user_id".
+func pathParameters(path string) []Parameter {
+	var params []Parameter
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			name := strings.TrimSuffix(strings.TrimPrefix(seg, "{"), "}")
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: Schema{Type: "string"}})
+		}
+	}
+	return params
+}
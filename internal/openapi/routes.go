@@ -0,0 +1,141 @@
+package openapi
+
+// auth describes how a route authenticates callers, so Build can attach
+// the matching OpenAPI security requirement.
+type auth int
+
+const (
+	// authNone is a public route: no credentials required.
+	authNone auth = iota
+	// authRequired is a route wrapped in Server.requireAuth: a bearer
+	// access token is required.
+	authRequired
+	// authOptional is a route wrapped in Server.optionalAuth: a bearer
+	// access token is read if present but not required.
+	authOptional
+	// authAPIKey is a route wrapped in Server.requireAPIKey: an API key
+	// bearer token is required instead of a user access token.
+	authAPIKey
+)
+
+// route describes one entry registered on the mux in httpapi.Router. It's
+// kept here, rather than derived by inspecting the mux at runtime, so the
+// generated spec can carry a human-written summary and tag per route; see
+// Build.
+type route struct {
+	Method  string
+	Path    string
+	Tag     string
+	Summary string
+	Auth    auth
+}
+
+// routes mirrors the route table in httpapi.Router, in the same order.
+// Keep the two in sync: a route added to the mux belongs here too.
+var routes = []route{
+	{Method: "POST", Path: "/register", Tag: "auth", Summary: "Register a new user account", Auth: authNone},
+	{Method: "POST", Path: "/login", Tag: "auth", Summary: "Log in with email and password", Auth: authNone},
+	{Method: "POST", Path: "/auth/refresh", Tag: "auth", Summary: "Exchange a refresh token for a new access token", Auth: authNone},
+	{Method: "POST", Path: "/auth/logout", Tag: "auth", Summary: "Revoke the current session's refresh token", Auth: authRequired},
+	{Method: "POST", Path: "/auth/forgot-password", Tag: "auth", Summary: "Request a password reset email", Auth: authNone},
+	{Method: "POST", Path: "/auth/reset-password", Tag: "auth", Summary: "Reset a password using a reset token", Auth: authNone},
+	{Method: "GET", Path: "/auth/verify", Tag: "auth", Summary: "Verify an account using an email verification token", Auth: authNone},
+	{Method: "GET", Path: "/auth/oauth/{provider}/start", Tag: "auth", Summary: "Start an OAuth login flow with the given provider", Auth: authNone},
+	{Method: "GET", Path: "/auth/oauth/{provider}/callback", Tag: "auth", Summary: "Complete an OAuth login flow with the given provider", Auth: authNone},
+	{Method: "GET", Path: "/.well-known/jwks.json", Tag: "auth", Summary: "Fetch the JSON Web Key Set used to verify access tokens", Auth: authNone},
+	{Method: "GET", Path: "/healthz", Tag: "health", Summary: "Liveness check: is the process up", Auth: authNone},
+	{Method: "GET", Path: "/readyz", Tag: "health", Summary: "Readiness check: can the process serve traffic", Auth: authNone},
+	{Method: "POST", Path: "/auth/2fa/enable", Tag: "auth", Summary: "Enable TOTP two-factor authentication", Auth: authRequired},
+	{Method: "POST", Path: "/auth/2fa/verify", Tag: "auth", Summary: "Verify a TOTP code and complete login", Auth: authNone},
+	{Method: "DELETE", Path: "/users/me", Tag: "users", Summary: "Delete the current user's account", Auth: authRequired},
+	{Method: "PATCH", Path: "/users/me", Tag: "users", Summary: "Update the current user's profile", Auth: authRequired},
+	{Method: "PUT", Path: "/users/me/avatar", Tag: "users", Summary: "Upload the current user's avatar", Auth: authRequired},
+	{Method: "PUT", Path: "/users/me/phone", Tag: "users", Summary: "Set the current user's phone number", Auth: authRequired},
+	{Method: "POST", Path: "/users/me/phone/verify", Tag: "users", Summary: "Verify the current user's phone number", Auth: authRequired},
+	{Method: "PUT", Path: "/users/me/reminder-lead-times", Tag: "users", Summary: "Set the current user's reminder lead times", Auth: authRequired},
+	{Method: "POST", Path: "/users/me/digest-subscription", Tag: "users", Summary: "Subscribe to the email digest", Auth: authRequired},
+	{Method: "DELETE", Path: "/users/me/digest-subscription", Tag: "users", Summary: "Unsubscribe from the email digest", Auth: authRequired},
+	{Method: "GET", Path: "/auth/sessions", Tag: "auth", Summary: "List the current user's active sessions", Auth: authRequired},
+	{Method: "DELETE", Path: "/auth/sessions/{id}", Tag: "auth", Summary: "Revoke a session", Auth: authRequired},
+	{Method: "POST", Path: "/auth/api-keys", Tag: "auth", Summary: "Create an API key", Auth: authRequired},
+	{Method: "GET", Path: "/api/events", Tag: "events", Summary: "List events, authenticated with an API key", Auth: authAPIKey},
+	{Method: "POST", Path: "/auth/magic-link", Tag: "auth", Summary: "Request a magic login link", Auth: authNone},
+	{Method: "GET", Path: "/auth/magic-link/verify", Tag: "auth", Summary: "Log in using a magic link token", Auth: authNone},
+	{Method: "POST", Path: "/availability", Tag: "events", Summary: "Find a common free time slot across attendees", Auth: authRequired},
+	{Method: "POST", Path: "/events", Tag: "events", Summary: "Create an event", Auth: authRequired},
+	{Method: "POST", Path: "/events/bulk", Tag: "events", Summary: "Create multiple events in one request", Auth: authRequired},
+	{Method: "GET", Path: "/events", Tag: "events", Summary: "List events", Auth: authNone},
+	{Method: "GET", Path: "/events.geojson", Tag: "events", Summary: "List events as a GeoJSON feature collection", Auth: authNone},
+	{Method: "GET", Path: "/events/nearby", Tag: "events", Summary: "List events near a location", Auth: authNone},
+	{Method: "GET", Path: "/events/trending", Tag: "events", Summary: "List trending events", Auth: authNone},
+	{Method: "GET", Path: "/events/search", Tag: "events", Summary: "Search events", Auth: authNone},
+	{Method: "GET", Path: "/events/{id}", Tag: "events", Summary: "Get an event", Auth: authOptional},
+	{Method: "GET", Path: "/events/{id}/stream", Tag: "events", Summary: "Stream live updates for an event", Auth: authNone},
+	{Method: "GET", Path: "/events/{id}/export.ics", Tag: "events", Summary: "Export an event as iCalendar", Auth: authOptional},
+	{Method: "PATCH", Path: "/events/{id}", Tag: "events", Summary: "Update an event", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/cancel", Tag: "events", Summary: "Cancel an event", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/duplicate", Tag: "events", Summary: "Duplicate an event", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/transfer", Tag: "events", Summary: "Transfer an event to a new organizer", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/join", Tag: "events", Summary: "Join an event", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/leave", Tag: "events", Summary: "Leave an event", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/attendees", Tag: "events", Summary: "List an event's attendees", Auth: authRequired},
+	{Method: "DELETE", Path: "/events/{id}/attendees/{user_id}", Tag: "events", Summary: "Remove an attendee from an event", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/bans", Tag: "events", Summary: "Ban an attendee from an event", Auth: authRequired},
+	{Method: "GET", Path: "/me/frequent-coattendees", Tag: "users", Summary: "List the current user's frequent co-attendees", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/checkin", Tag: "events", Summary: "Check in an attendee", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/checkin-log", Tag: "events", Summary: "List an event's check-in log", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/attendees/{user_id}/qr", Tag: "events", Summary: "Get an attendee's check-in QR code", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/attendees/export", Tag: "events", Summary: "Export an event's attendee list as CSV", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/stats", Tag: "events", Summary: "Get an event's organizer stats", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/referrals", Tag: "events", Summary: "Get an event's referral stats", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/share-link", Tag: "events", Summary: "Generate a share link for an event", Auth: authRequired},
+	{Method: "DELETE", Path: "/events/{id}/share-link", Tag: "events", Summary: "Disable an event's share link", Auth: authRequired},
+	{Method: "GET", Path: "/share/{code}", Tag: "share", Summary: "Get an event by its share code", Auth: authNone},
+	{Method: "POST", Path: "/share/{code}/join", Tag: "share", Summary: "Join an event using a share code", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/bookmark", Tag: "events", Summary: "Bookmark an event", Auth: authRequired},
+	{Method: "DELETE", Path: "/events/{id}/bookmark", Tag: "events", Summary: "Remove an event bookmark", Auth: authRequired},
+	{Method: "GET", Path: "/events/my/bookmarks", Tag: "events", Summary: "List the current user's bookmarked events", Auth: authRequired},
+	{Method: "GET", Path: "/events/my/duration-stats", Tag: "events", Summary: "Get the current user's event duration stats", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/occurrences", Tag: "events", Summary: "List a recurring event's occurrences", Auth: authNone},
+	{Method: "POST", Path: "/events/{id}/occurrences/cancel", Tag: "events", Summary: "Cancel a single occurrence of a recurring event", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/occurrences/reschedule", Tag: "events", Summary: "Reschedule a single occurrence of a recurring event", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/comments", Tag: "events", Summary: "Post a comment on an event", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/comments", Tag: "events", Summary: "List an event's comments", Auth: authOptional},
+	{Method: "DELETE", Path: "/comments/{id}", Tag: "comments", Summary: "Delete a comment", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/invitations", Tag: "events", Summary: "Invite a user to an event", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/invitations", Tag: "events", Summary: "List an event's invitations", Auth: authRequired},
+	{Method: "GET", Path: "/invitations", Tag: "invitations", Summary: "List the current user's invitations", Auth: authRequired},
+	{Method: "GET", Path: "/invitations/my/count", Tag: "invitations", Summary: "Get the current user's pending invitation count", Auth: authRequired},
+	{Method: "GET", Path: "/invitations/accept", Tag: "invitations", Summary: "Accept an invitation using an email link token", Auth: authNone},
+	{Method: "GET", Path: "/invitations/decline", Tag: "invitations", Summary: "Decline an invitation using an email link token", Auth: authNone},
+	{Method: "POST", Path: "/invitations/{id}/accept", Tag: "invitations", Summary: "Accept an invitation", Auth: authRequired},
+	{Method: "POST", Path: "/invitations/{id}/decline", Tag: "invitations", Summary: "Decline an invitation", Auth: authRequired},
+	{Method: "DELETE", Path: "/invitations/{id}", Tag: "invitations", Summary: "Revoke an invitation", Auth: authRequired},
+	{Method: "PUT", Path: "/events/{id}/invitation-template", Tag: "events", Summary: "Set an event's invitation email template", Auth: authRequired},
+	{Method: "PUT", Path: "/events/{id}/slack-webhook", Tag: "events", Summary: "Connect or disconnect an event's Slack webhook", Auth: authRequired},
+	{Method: "GET", Path: "/integrations/google-calendar/connect", Tag: "integrations", Summary: "Start connecting Google Calendar", Auth: authRequired},
+	{Method: "GET", Path: "/integrations/google-calendar/callback", Tag: "integrations", Summary: "Complete connecting Google Calendar", Auth: authNone},
+	{Method: "DELETE", Path: "/integrations/google-calendar", Tag: "integrations", Summary: "Disconnect Google Calendar", Auth: authRequired},
+	{Method: "GET", Path: "/integrations/outlook-calendar/connect", Tag: "integrations", Summary: "Start connecting Outlook Calendar", Auth: authRequired},
+	{Method: "GET", Path: "/integrations/outlook-calendar/callback", Tag: "integrations", Summary: "Complete connecting Outlook Calendar", Auth: authNone},
+	{Method: "DELETE", Path: "/integrations/outlook-calendar", Tag: "integrations", Summary: "Disconnect Outlook Calendar", Auth: authRequired},
+	{Method: "GET", Path: "/notifications", Tag: "notifications", Summary: "List the current user's notifications", Auth: authRequired},
+	{Method: "POST", Path: "/notifications/{id}/read", Tag: "notifications", Summary: "Mark a notification read", Auth: authRequired},
+	{Method: "POST", Path: "/notifications/read-all", Tag: "notifications", Summary: "Mark all notifications read", Auth: authRequired},
+	{Method: "POST", Path: "/notifications/devices", Tag: "notifications", Summary: "Register a push notification device", Auth: authRequired},
+	{Method: "GET", Path: "/ws", Tag: "realtime", Summary: "Open a WebSocket connection for live updates", Auth: authNone},
+	{Method: "POST", Path: "/events/{id}/ticket-types", Tag: "events", Summary: "Create a ticket type for an event", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/ticket-types", Tag: "events", Summary: "List an event's ticket types", Auth: authNone},
+	{Method: "POST", Path: "/events/{id}/tickets", Tag: "events", Summary: "Claim a ticket", Auth: authRequired},
+	{Method: "GET", Path: "/events/{id}/tickets", Tag: "events", Summary: "List an event's tickets", Auth: authRequired},
+	{Method: "POST", Path: "/events/{id}/tickets/validate", Tag: "events", Summary: "Validate a ticket", Auth: authRequired},
+	{Method: "POST", Path: "/webhooks/stripe", Tag: "webhooks", Summary: "Receive a Stripe webhook event", Auth: authNone},
+	{Method: "POST", Path: "/polls", Tag: "polls", Summary: "Create a poll", Auth: authRequired},
+	{Method: "GET", Path: "/polls/{id}", Tag: "polls", Summary: "Get a poll", Auth: authNone},
+	{Method: "POST", Path: "/polls/{id}/vote", Tag: "polls", Summary: "Vote on a poll", Auth: authRequired},
+	{Method: "POST", Path: "/polls/{id}/finalize", Tag: "polls", Summary: "Finalize a poll", Auth: authRequired},
+	{Method: "GET", Path: "/users/search", Tag: "users", Summary: "Search users", Auth: authRequired},
+	{Method: "POST", Path: "/calendar/feed-token", Tag: "calendar", Summary: "Create a calendar feed token", Auth: authRequired},
+	{Method: "DELETE", Path: "/calendar/feed-token", Tag: "calendar", Summary: "Revoke a calendar feed token", Auth: authRequired},
+	{Method: "GET", Path: "/calendar/feed.ics", Tag: "calendar", Summary: "Get the current user's calendar feed", Auth: authNone},
+}
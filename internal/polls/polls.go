@@ -0,0 +1,300 @@
+// Package polls lets an organizer propose candidate date/time slots for an
+// event, collect availability votes from invitees, and finalize the
+// winning slot into the event itself.
+package polls
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// Poll statuses stored on event_polls.status.
+const (
+	StatusOpen      = "open"
+	StatusFinalized = "finalized"
+)
+
+// ErrNotFound is returned when a poll or option lookup finds no matching row.
+var ErrNotFound = errors.New("polls: not found")
+
+// ErrForbidden is returned by Finalize when the caller is not the poll's
+// organizer.
+var ErrForbidden = errors.New("polls: not permitted")
+
+// ErrAlreadyFinalized is returned by Vote and Finalize once a poll has
+// already been finalized.
+var ErrAlreadyFinalized = errors.New("polls: poll already finalized")
+
+// ErrNoOptions is returned by Create when no candidate slots are given.
+var ErrNoOptions = errors.New("polls: at least one candidate slot is required")
+
+// CandidateSlot is a single date/time option an organizer proposes.
+type CandidateSlot struct {
+	StartsAt time.Time
+	EndsAt   *time.Time
+}
+
+// Poll is a set of candidate slots organizers ask invitees to vote on
+// before an event's time is locked in.
+type Poll struct {
+	ID          int64
+	OrganizerID int64
+	// EventID is nil until the poll is finalized for a poll that was
+	// created with no event yet; see Finalize.
+	EventID     *int64
+	Title       string
+	Description string
+	Location    string
+	Status      string
+	// FinalizedOptionID is the winning option once Status is
+	// StatusFinalized, or nil while the poll is still open.
+	FinalizedOptionID *int64
+	CreatedAt         time.Time
+}
+
+// Option is a single candidate slot on a poll.
+type Option struct {
+	ID       int64
+	PollID   int64
+	StartsAt time.Time
+	EndsAt   *time.Time
+}
+
+// OptionResult is an Option annotated with its vote count, returned by
+// Results.
+type OptionResult struct {
+	Option
+	Votes int
+}
+
+// Store persists polls and, on Finalize, creates or updates the
+// corresponding event.
+type Store struct {
+	db     *sql.DB
+	events *events.Store
+}
+
+// NewStore returns a Store backed by db.
+func NewStore(db *sql.DB, eventStore *events.Store) *Store {
+	return &Store{db: db, events: eventStore}
+}
+
+// CreateInput carries the fields needed to propose a poll.
+type CreateInput struct {
+	OrganizerID int64
+	// EventID, if set, ties the poll to an existing event whose start and
+	// end time Finalize will update. Leave nil to have Finalize create the
+	// event from the winning slot instead.
+	EventID     *int64
+	Title       string
+	Description string
+	Location    string
+	Options     []CandidateSlot
+}
+
+// Create proposes a new poll with the given candidate slots, open for
+// voting. At least one option is required.
+func (s *Store) Create(ctx context.Context, in CreateInput) (*Poll, error) {
+	if len(in.Options) == 0 {
+		return nil, ErrNoOptions
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("polls: create: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	p := &Poll{
+		OrganizerID: in.OrganizerID,
+		EventID:     in.EventID,
+		Title:       in.Title,
+		Description: in.Description,
+		Location:    in.Location,
+		Status:      StatusOpen,
+	}
+	row := tx.QueryRowContext(ctx,
+		`INSERT INTO event_polls (event_id, organizer_id, title, description, location, status)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		in.EventID, in.OrganizerID, in.Title, in.Description, in.Location, StatusOpen,
+	)
+	if err := row.Scan(&p.ID, &p.CreatedAt); err != nil {
+		return nil, fmt.Errorf("polls: create: %w", err)
+	}
+
+	for _, slot := range in.Options {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO event_poll_options (poll_id, starts_at, ends_at) VALUES ($1, $2, $3)`,
+			p.ID, slot.StartsAt, slot.EndsAt,
+		); err != nil {
+			return nil, fmt.Errorf("polls: create option: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("polls: create: commit: %w", err)
+	}
+	return p, nil
+}
+
+// Get returns the poll with the given ID.
+func (s *Store) Get(ctx context.Context, id int64) (*Poll, error) {
+	return s.get(ctx, id)
+}
+
+func (s *Store) get(ctx context.Context, id int64) (*Poll, error) {
+	p := &Poll{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, event_id, organizer_id, title, description, location, status, finalized_option_id, created_at
+		 FROM event_polls WHERE id = $1`, id,
+	)
+	if err := row.Scan(&p.ID, &p.EventID, &p.OrganizerID, &p.Title, &p.Description, &p.Location, &p.Status, &p.FinalizedOptionID, &p.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("polls: get: %w", err)
+	}
+	return p, nil
+}
+
+func (s *Store) getOption(ctx context.Context, id int64) (*Option, error) {
+	o := &Option{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, poll_id, starts_at, ends_at FROM event_poll_options WHERE id = $1`, id,
+	)
+	if err := row.Scan(&o.ID, &o.PollID, &o.StartsAt, &o.EndsAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("polls: get option: %w", err)
+	}
+	return o, nil
+}
+
+// Vote records userID's vote for optionID on pollID. Voting for the same
+// option twice is a no-op, and voting for more than one option on the
+// same poll is allowed, matching Doodle-style availability voting. Voting
+// on a finalized poll is rejected with ErrAlreadyFinalized, and voting for
+// an optionID that isn't one of pollID's options is rejected with
+// ErrNotFound.
+func (s *Store) Vote(ctx context.Context, pollID, optionID, userID int64) error {
+	p, err := s.get(ctx, pollID)
+	if err != nil {
+		return err
+	}
+	if p.Status == StatusFinalized {
+		return ErrAlreadyFinalized
+	}
+
+	opt, err := s.getOption(ctx, optionID)
+	if err != nil {
+		return err
+	}
+	if opt.PollID != pollID {
+		return ErrNotFound
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO event_poll_votes (option_id, user_id) VALUES ($1, $2)
+		 ON CONFLICT (option_id, user_id) DO NOTHING`,
+		optionID, userID,
+	); err != nil {
+		return fmt.Errorf("polls: vote: %w", err)
+	}
+	return nil
+}
+
+// Results returns pollID's options along with how many votes each has,
+// highest first.
+func (s *Store) Results(ctx context.Context, pollID int64) ([]OptionResult, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT o.id, o.poll_id, o.starts_at, o.ends_at, count(v.user_id)
+		 FROM event_poll_options o
+		 LEFT JOIN event_poll_votes v ON v.option_id = o.id
+		 WHERE o.poll_id = $1
+		 GROUP BY o.id, o.poll_id, o.starts_at, o.ends_at
+		 ORDER BY count(v.user_id) DESC, o.id ASC`,
+		pollID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("polls: results: %w", err)
+	}
+	defer rows.Close()
+
+	var out []OptionResult
+	for rows.Next() {
+		var r OptionResult
+		if err := rows.Scan(&r.Option.ID, &r.Option.PollID, &r.Option.StartsAt, &r.Option.EndsAt, &r.Votes); err != nil {
+			return nil, fmt.Errorf("polls: results scan: %w", err)
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// Finalize locks in optionID as pollID's winning slot: it creates the
+// poll's event if Create was called with no EventID, or updates the
+// existing event's start and end time otherwise, then marks the poll
+// finalized. Only the poll's organizer may finalize it, and a poll can
+// only be finalized once.
+//
+// The event write and the poll's finalized status aren't committed as a
+// single database transaction: events lives in its own package behind its
+// own Store, so Finalize can only sequence the two calls, not wrap them
+// together. If the process dies in between, the event is created or
+// updated but the poll is left open for a retried Finalize to pick up -
+// the same tradeoff events.CancelEvent takes with attendee notification.
+func (s *Store) Finalize(ctx context.Context, pollID, userID, optionID int64) (*events.Event, error) {
+	p, err := s.get(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if p.OrganizerID != userID {
+		return nil, ErrForbidden
+	}
+	if p.Status == StatusFinalized {
+		return nil, ErrAlreadyFinalized
+	}
+
+	opt, err := s.getOption(ctx, optionID)
+	if err != nil {
+		return nil, err
+	}
+	if opt.PollID != pollID {
+		return nil, ErrNotFound
+	}
+
+	var e *events.Event
+	if p.EventID == nil {
+		e, err = s.events.Create(ctx, events.CreateInput{
+			OrganizerID: p.OrganizerID,
+			Title:       p.Title,
+			Description: p.Description,
+			Location:    p.Location,
+			StartsAt:    opt.StartsAt,
+			EndsAt:      opt.EndsAt,
+		})
+	} else {
+		e, err = s.events.Update(ctx, *p.EventID, events.UpdateInput{
+			StartsAt: &opt.StartsAt,
+			EndsAt:   &opt.EndsAt,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE event_polls SET status = $1, event_id = $2, finalized_option_id = $3 WHERE id = $4`,
+		StatusFinalized, e.ID, optionID, pollID,
+	); err != nil {
+		return nil, fmt.Errorf("polls: finalize: %w", err)
+	}
+	return e, nil
+}
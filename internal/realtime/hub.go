@@ -0,0 +1,75 @@
+// Package realtime pushes live updates to connected clients over
+// WebSocket connections, fanned out through an in-process pub/sub hub
+// keyed by user ID.
+package realtime
+
+import "sync"
+
+// Event is what's sent down a Conn: a named update with an
+// event-type-specific payload.
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Hub tracks which connections belong to which user and publishes events
+// to them.
+type Hub struct {
+	mu    sync.Mutex
+	conns map[int64]map[*Conn]struct{}
+}
+
+// NewHub returns an empty Hub.
+func NewHub() *Hub {
+	return &Hub{conns: make(map[int64]map[*Conn]struct{})}
+}
+
+// Serve registers conn as belonging to userID and blocks until the client
+// disconnects, then unregisters and closes it. Call it from the request
+// goroutine right after a successful Upgrade.
+func (h *Hub) Serve(userID int64, conn *Conn) {
+	h.register(userID, conn)
+	conn.readLoop()
+	h.unregister(userID, conn)
+	conn.Close()
+}
+
+// Publish sends an event of the given type to every connection userID
+// currently has open. It is a no-op if userID has none. Connections that
+// fail to accept the write are dropped.
+func (h *Hub) Publish(userID int64, eventType string, data interface{}) {
+	h.mu.Lock()
+	conns := make([]*Conn, 0, len(h.conns[userID]))
+	for c := range h.conns[userID] {
+		conns = append(conns, c)
+	}
+	h.mu.Unlock()
+
+	event := Event{Type: eventType, Data: data}
+	for _, c := range conns {
+		if err := c.WriteJSON(event); err != nil {
+			c.Close()
+			h.unregister(userID, c)
+		}
+	}
+}
+
+func (h *Hub) register(userID int64, conn *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.conns[userID] == nil {
+		h.conns[userID] = make(map[*Conn]struct{})
+	}
+	h.conns[userID][conn] = struct{}{}
+}
+
+func (h *Hub) unregister(userID int64, conn *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.conns[userID], conn)
+	if len(h.conns[userID]) == 0 {
+		delete(h.conns, userID)
+	}
+}
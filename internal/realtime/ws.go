@@ -0,0 +1,233 @@
+package realtime
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is the magic string RFC 6455 has servers append to the
+// client's Sec-WebSocket-Key before hashing, to prove the handshake was
+// understood as a WebSocket upgrade rather than replayed from elsewhere.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opText  byte = 0x1
+	opClose byte = 0x8
+	opPing  byte = 0x9
+	opPong  byte = 0xA
+)
+
+// maxReadFramePayload bounds how large a single incoming frame's payload
+// may be. This connection only ever reads control traffic from the client
+// (pings, close frames), so there's no legitimate reason for it to exceed a
+// few bytes; the limit is generous to stay clear of any real ping/close
+// payload while still refusing to allocate arbitrary amounts of memory for
+// a client-supplied length field.
+const maxReadFramePayload = 4096
+
+var (
+	// ErrNotWebsocket is returned by Upgrade when the request doesn't carry
+	// the headers a WebSocket handshake requires.
+	ErrNotWebsocket = errors.New("realtime: not a websocket upgrade request")
+	// ErrHijackUnsupported is returned by Upgrade when the ResponseWriter
+	// can't be hijacked to take over the raw connection.
+	ErrHijackUnsupported = errors.New("realtime: response writer does not support hijacking")
+	// errFrameTooLarge is returned by readFrame when a client-sent frame
+	// declares a payload longer than maxReadFramePayload; the caller treats
+	// it like any other read error and drops the connection.
+	errFrameTooLarge = errors.New("realtime: frame payload too large")
+)
+
+// Conn is a single upgraded WebSocket connection. It only ever sends text
+// frames carrying JSON-encoded Events; the read side exists solely to
+// notice the client going away and to answer pings, since this is a
+// server-push endpoint.
+type Conn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+
+	writeMu sync.Mutex
+}
+
+// Upgrade performs the RFC 6455 handshake over r's underlying connection
+// and returns a Conn ready to have events published to it. There is no
+// third-party WebSocket library in go.mod, so this hand-rolls the
+// handshake and minimal framing needed for one-way server push using only
+// net/http's Hijacker.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || !strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade") {
+		return nil, ErrNotWebsocket
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, ErrNotWebsocket
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, ErrHijackUnsupported
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := rw.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Conn{conn: conn, rw: rw}, nil
+}
+
+// acceptKey derives the Sec-WebSocket-Accept header value from the
+// client's Sec-WebSocket-Key, per RFC 6455 section 1.3.
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// WriteJSON sends v to the client as a single text frame.
+func (c *Conn) WriteJSON(v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := writeFrame(c.rw.Writer, opText, payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// Close closes the underlying connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// readLoop blocks reading frames until the client disconnects or sends a
+// close frame, answering pings with pongs along the way. It returns once
+// the connection is no longer usable.
+func (c *Conn) readLoop() {
+	for {
+		opcode, payload, err := readFrame(c.rw.Reader)
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opClose:
+			c.writeMu.Lock()
+			_ = writeFrame(c.rw.Writer, opClose, nil)
+			_ = c.rw.Flush()
+			c.writeMu.Unlock()
+			return
+		case opPing:
+			c.writeMu.Lock()
+			_ = writeFrame(c.rw.Writer, opPong, payload)
+			_ = c.rw.Flush()
+			c.writeMu.Unlock()
+		}
+	}
+}
+
+// writeFrame writes a single, unfragmented, unmasked frame, which is all a
+// server is required to send per RFC 6455.
+func writeFrame(w io.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+
+	n := len(payload)
+	switch {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 65535:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// readFrame reads a single frame sent by the client, unmasking its
+// payload. Frames sent from client to server are always masked per
+// RFC 6455; fragmented messages aren't needed for this endpoint's control
+// traffic and aren't supported. A payload longer than
+// maxReadFramePayload fails with errFrameTooLarge rather than trusting
+// the client-supplied length to size an allocation.
+func readFrame(r io.Reader) (opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	opcode = header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7f)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext))
+	}
+	if length > maxReadFramePayload {
+		return 0, nil, errFrameTooLarge
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
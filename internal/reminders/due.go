@@ -0,0 +1,167 @@
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+	"github.com/dohasheriff/tools-3/internal/icalendar"
+)
+
+// dueReminder is a "going" attendee due a reminder for one lead time,
+// joined with the event, organizer, and attendee fields its reminder
+// needs.
+type dueReminder struct {
+	eventID        int64
+	userID         int64
+	email          string
+	title          string
+	startsAt       time.Time
+	endsAt         *time.Time
+	location       string
+	organizerName  string
+	organizerEmail string
+}
+
+// SendDueReminders emails and notifies every "going" attendee whose event
+// starts within one of their configured reminder lead times (24 hours and 1
+// hour ahead, by default; see SetLeadTimes). Each lead time is sent at most
+// once per attendee per event, tracked in event_reminders_sent. It's meant
+// to run periodically from a scheduled job, the same as
+// invitations.Store.SendDueReminders, and returns the number of reminders
+// sent.
+func (s *Store) SendDueReminders(ctx context.Context) (int, error) {
+	leadTimes, err := s.candidateLeadMinutes(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, leadMinutes := range leadTimes {
+		n, err := s.sendRemindersForLeadTime(ctx, leadMinutes)
+		if err != nil {
+			return sent, err
+		}
+		sent += n
+	}
+	return sent, nil
+}
+
+// candidateLeadMinutes returns DefaultLeadMinutes plus every distinct lead
+// time any user has configured through SetLeadTimes, so SendDueReminders
+// checks every value that could possibly be due.
+func (s *Store) candidateLeadMinutes(ctx context.Context) ([]int, error) {
+	minutes := append([]int{}, DefaultLeadMinutes...)
+	seen := make(map[int]bool, len(minutes))
+	for _, m := range minutes {
+		seen[m] = true
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT lead_minutes FROM event_reminder_lead_times`)
+	if err != nil {
+		return nil, fmt.Errorf("reminders: candidate lead times: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m int
+		if err := rows.Scan(&m); err != nil {
+			return nil, fmt.Errorf("reminders: scan candidate lead time: %w", err)
+		}
+		if !seen[m] {
+			seen[m] = true
+			minutes = append(minutes, m)
+		}
+	}
+	return minutes, rows.Err()
+}
+
+// sendRemindersForLeadTime emails and notifies every attendee due a
+// reminder leadMinutes before their event starts: either because they
+// configured leadMinutes explicitly, or because leadMinutes is one of
+// DefaultLeadMinutes and they haven't configured any lead times at all.
+func (s *Store) sendRemindersForLeadTime(ctx context.Context, leadMinutes int) (int, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT ea.event_id, ea.user_id, u.email, e.title, e.starts_at, e.ends_at, e.location, o.display_name, o.email
+		 FROM event_attendees ea
+		 JOIN events e ON e.id = ea.event_id
+		 JOIN users u ON u.id = ea.user_id
+		 JOIN users o ON o.id = e.organizer_id
+		 WHERE ea.status = $1
+		   AND e.starts_at > now() AND e.starts_at <= now() + make_interval(mins => $2)
+		   AND NOT EXISTS (
+		     SELECT 1 FROM event_reminders_sent s
+		     WHERE s.event_id = ea.event_id AND s.user_id = ea.user_id AND s.lead_minutes = $2
+		   )
+		   AND (
+		     EXISTS (SELECT 1 FROM event_reminder_lead_times lt WHERE lt.user_id = ea.user_id AND lt.lead_minutes = $2)
+		     OR ($3 AND NOT EXISTS (SELECT 1 FROM event_reminder_lead_times lt2 WHERE lt2.user_id = ea.user_id))
+		   )`,
+		events.StatusGoing, leadMinutes, isDefaultLeadMinutes(leadMinutes),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("reminders: due reminders: %w", err)
+	}
+	due, err := scanDueReminders(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	notifiedSlack := make(map[int64]bool)
+	for _, r := range due {
+		subject := fmt.Sprintf("Reminder: %s starts soon", r.title)
+		body := fmt.Sprintf(
+			"<p><strong>%s</strong> starts %s at %s.</p>",
+			r.title, r.startsAt.Format(time.RFC1123), r.location,
+		)
+		ics := icalendar.Build(icalendar.MethodRequest, icalendar.Event{
+			UID:            fmt.Sprintf("event-%d@tools-3", r.eventID),
+			Title:          r.title,
+			Location:       r.location,
+			StartsAt:       r.startsAt,
+			EndsAt:         r.endsAt,
+			OrganizerEmail: r.organizerEmail,
+			OrganizerName:  r.organizerName,
+			AttendeeEmail:  r.email,
+		})
+
+		if err := s.mailer.SendICS(ctx, r.email, subject, body, string(icalendar.MethodRequest), "reminder.ics", ics); err != nil {
+			return sent, fmt.Errorf("reminders: send reminder: %w", err)
+		}
+		if err := s.notifier.Notify(ctx, r.userID, "event_reminder", subject, body, &r.eventID); err != nil {
+			return sent, fmt.Errorf("reminders: notify reminder: %w", err)
+		}
+		if _, err := s.db.ExecContext(ctx,
+			`INSERT INTO event_reminders_sent (event_id, user_id, lead_minutes) VALUES ($1, $2, $3)`,
+			r.eventID, r.userID, leadMinutes,
+		); err != nil {
+			return sent, fmt.Errorf("reminders: stamp reminder: %w", err)
+		}
+		if !notifiedSlack[r.eventID] {
+			notifiedSlack[r.eventID] = true
+			if err := s.slack.Notify(ctx, r.eventID, fmt.Sprintf(":alarm_clock: *%s* starts %s at %s.", r.title, r.startsAt.Format(time.RFC1123), r.location)); err != nil {
+				return sent, fmt.Errorf("reminders: slack notify reminder: %w", err)
+			}
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// scanDueReminders collects rows into dueReminder values and closes rows.
+func scanDueReminders(rows *sql.Rows) ([]*dueReminder, error) {
+	defer rows.Close()
+
+	var due []*dueReminder
+	for rows.Next() {
+		r := &dueReminder{}
+		if err := rows.Scan(&r.eventID, &r.userID, &r.email, &r.title, &r.startsAt, &r.endsAt, &r.location, &r.organizerName, &r.organizerEmail); err != nil {
+			return nil, fmt.Errorf("reminders: scan due reminder: %w", err)
+		}
+		due = append(due, r)
+	}
+	return due, rows.Err()
+}
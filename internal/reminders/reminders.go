@@ -0,0 +1,70 @@
+// Package reminders sends reminder emails, in-app notifications, and
+// Slack notices to attendees ahead of events they're going to, on a
+// schedule each attendee can customize. Like invitations.Store.SendDueReminders,
+// it runs from a scheduled job with no httpapi request to notify through,
+// so it owns its own Mailer, Notifier, and SlackNotifier dependencies
+// rather than leaving dispatch to a caller, unlike events.Store.CancelEvent.
+// It reads event and attendee data by joining those tables directly, the
+// same tradeoff invitations.Store.sendFollowupReminders makes to query
+// events without importing events both ways.
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DefaultLeadMinutes are the reminder lead times, in minutes before an
+// event starts, used for attendees who haven't set their own via
+// SetLeadTimes: 24 hours and 1 hour ahead.
+var DefaultLeadMinutes = []int{24 * 60, 60}
+
+// Store sends event reminders.
+type Store struct {
+	db       *sql.DB
+	mailer   Mailer
+	notifier Notifier
+	slack    SlackNotifier
+}
+
+// NewStore returns a Store that delivers reminders through mailer and
+// notifier, and posts a notice to slack for each event that has a
+// connected Slack channel.
+func NewStore(db *sql.DB, mailer Mailer, notifier Notifier, slack SlackNotifier) *Store {
+	return &Store{db: db, mailer: mailer, notifier: notifier, slack: slack}
+}
+
+// SetLeadTimes replaces userID's reminder lead times (minutes before an
+// event starts that a reminder should fire) with leadMinutes. An empty
+// slice reverts the user to DefaultLeadMinutes.
+func (s *Store) SetLeadTimes(ctx context.Context, userID int64, leadMinutes []int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("reminders: begin set lead times: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_reminder_lead_times WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("reminders: clear lead times: %w", err)
+	}
+	for _, minutes := range leadMinutes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO event_reminder_lead_times (user_id, lead_minutes) VALUES ($1, $2)`,
+			userID, minutes,
+		); err != nil {
+			return fmt.Errorf("reminders: set lead time: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// isDefaultLeadMinutes reports whether minutes is one of DefaultLeadMinutes.
+func isDefaultLeadMinutes(minutes int) bool {
+	for _, d := range DefaultLeadMinutes {
+		if d == minutes {
+			return true
+		}
+	}
+	return false
+}
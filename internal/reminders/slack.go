@@ -0,0 +1,20 @@
+package reminders
+
+import "context"
+
+// SlackNotifier posts a reminder notice to an event's connected Slack
+// channel, if it has one. It's the same pattern as Mailer and Notifier:
+// a single-method interface reminders declares for itself rather than
+// importing slack.Store's concrete type, since slack.Store already
+// satisfies it structurally.
+type SlackNotifier interface {
+	Notify(ctx context.Context, eventID int64, text string) error
+}
+
+// NoopSlackNotifier discards every notification; it's the default when no
+// Slack integration is configured.
+type NoopSlackNotifier struct{}
+
+func (NoopSlackNotifier) Notify(ctx context.Context, eventID int64, text string) error {
+	return nil
+}
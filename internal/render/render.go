@@ -0,0 +1,147 @@
+// Package render provides the JSON response envelope used by every
+// httpapi handler, so success and error bodies have a consistent shape
+// instead of each handler building its own map or struct.
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+)
+
+// Error is the body of an error response, nested under the "error" key.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+}
+
+type errorEnvelope struct {
+	Error Error `json:"error"`
+}
+
+// JSON writes v as a JSON response body with the given status code.
+func JSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// StreamWriter writes a single JSON object to an http.ResponseWriter one
+// field at a time, so a list endpoint's array field can be encoded element
+// by element instead of marshaling the whole slice into one buffer first.
+// Use NewStreamWriter, then call Field and ArrayField in the order the
+// object's keys should appear, then Close.
+type StreamWriter struct {
+	w        http.ResponseWriter
+	enc      *json.Encoder
+	wroteAny bool
+}
+
+// NewStreamWriter writes the response headers and opening brace, and
+// returns a StreamWriter ready for Field/ArrayField calls.
+func NewStreamWriter(w http.ResponseWriter, status int) *StreamWriter {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	io.WriteString(w, "{")
+	return &StreamWriter{w: w, enc: json.NewEncoder(w)}
+}
+
+func (sw *StreamWriter) separator() {
+	if sw.wroteAny {
+		io.WriteString(sw.w, ",")
+	}
+	sw.wroteAny = true
+}
+
+// Field writes a single "key":value pair, encoding v in one shot. Use for
+// scalars and small values; use ArrayField for the list itself.
+func (sw *StreamWriter) Field(key string, v any) {
+	sw.separator()
+	fmt.Fprintf(sw.w, "%q:", key)
+	_ = sw.enc.Encode(v)
+}
+
+// ArrayField writes a single "key":[...] pair, encoding each of the n
+// elements returned by at individually and flushing them to the
+// underlying connection as it goes, rather than building the full slice's
+// JSON in memory before writing any of it.
+func (sw *StreamWriter) ArrayField(key string, n int, at func(i int) any) {
+	sw.separator()
+	fmt.Fprintf(sw.w, "%q:[", key)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			io.WriteString(sw.w, ",")
+		}
+		_ = sw.enc.Encode(at(i))
+	}
+	io.WriteString(sw.w, "]")
+}
+
+// Close writes the closing brace.
+func (sw *StreamWriter) Close() {
+	io.WriteString(sw.w, "}")
+}
+
+// Err writes a {"error": {"code", "message"}} response, deriving code from
+// status (e.g. http.StatusNotFound -> "not_found").
+func Err(w http.ResponseWriter, status int, message string) {
+	ErrDetails(w, status, message, nil)
+}
+
+// ErrDetails behaves like Err but also attaches details, e.g. a list of
+// field validation failures, under the error's "details" key.
+func ErrDetails(w http.ResponseWriter, status int, message string, details any) {
+	JSON(w, status, errorEnvelope{Error: Error{
+		Code:    codeForStatus(status),
+		Message: message,
+		Details: details,
+	}})
+}
+
+// StatusForError maps a domain error to an HTTP status code by checking
+// it against the apperr categories, rather than matching on err.Error()
+// text. Errors that aren't wrapped with an apperr category map to 500.
+func StatusForError(err error) int {
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperr.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperr.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperr.ErrConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// codeForStatus maps an HTTP status code to a short machine-readable code,
+// falling back to the snake_cased status text for codes without an
+// explicit mapping.
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusInternalServerError:
+		return "internal"
+	default:
+		return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+	}
+}
@@ -0,0 +1,102 @@
+// Package slack posts event notifications to a Slack channel through an
+// organizer-configured incoming webhook. A per-event incoming webhook
+// needs no app review or stored OAuth tokens, unlike a workspace-wide
+// Slack app, so it's the integration this package implements.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dohasheriff/tools-3/internal/apperr"
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// ErrForbidden is returned by SetWebhook when the caller is not the
+// event's organizer.
+var ErrForbidden = apperr.Wrap(apperr.ErrForbidden, "slack: not permitted to manage this event's slack integration")
+
+// Store manages per-event Slack incoming webhooks and posts messages
+// through them.
+type Store struct {
+	db     *sql.DB
+	events *events.Store
+	client *http.Client
+}
+
+// NewStore returns a Store backed by db, using events to check that
+// callers managing a webhook are the event's organizer.
+func NewStore(db *sql.DB, eventStore *events.Store) *Store {
+	return &Store{db: db, events: eventStore, client: &http.Client{}}
+}
+
+// SetWebhook sets eventID's Slack incoming webhook URL, or disconnects
+// Slack notifications for eventID if webhookURL is empty. The caller must
+// be the event's organizer.
+func (s *Store) SetWebhook(ctx context.Context, eventID, actorID int64, webhookURL string) error {
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if e.OrganizerID != actorID {
+		return ErrForbidden
+	}
+
+	if webhookURL == "" {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM event_slack_webhooks WHERE event_id = $1`, eventID); err != nil {
+			return fmt.Errorf("slack: clear webhook: %w", err)
+		}
+		return nil
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO event_slack_webhooks (event_id, webhook_url) VALUES ($1, $2)
+		 ON CONFLICT (event_id) DO UPDATE SET webhook_url = $2`,
+		eventID, webhookURL,
+	)
+	if err != nil {
+		return fmt.Errorf("slack: set webhook: %w", err)
+	}
+	return nil
+}
+
+// Notify posts text to eventID's connected Slack channel. It's a no-op
+// for an event with no webhook configured, the same "quietly skip when
+// there's nothing to do" tradeoff auth.Service.NotifySMS makes for a user
+// with no verified phone number.
+func (s *Store) Notify(ctx context.Context, eventID int64, text string) error {
+	var webhookURL string
+	row := s.db.QueryRowContext(ctx, `SELECT webhook_url FROM event_slack_webhooks WHERE event_id = $1`, eventID)
+	if err := row.Scan(&webhookURL); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("slack: get webhook: %w", err)
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("slack: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: post message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack: post message: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
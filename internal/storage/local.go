@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalDisk saves objects under a directory on the local filesystem and
+// serves them back from baseURL, such as a static file route on the same
+// server. It is the default Storage for development and single-node
+// deployments.
+type LocalDisk struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalDisk returns a LocalDisk that writes objects under dir and serves
+// them from baseURL (with no trailing slash).
+func NewLocalDisk(dir, baseURL string) *LocalDisk {
+	return &LocalDisk{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Save writes r to dir/key, creating any missing parent directories, and
+// returns baseURL/key.
+func (l *LocalDisk) Save(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	path := filepath.Join(l.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("storage: create directory: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("storage: create file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return "", fmt.Errorf("storage: write file: %w", err)
+	}
+
+	return l.baseURL + "/" + key, nil
+}
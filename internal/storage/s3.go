@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3 saves objects to an S3-compatible bucket and serves them back from
+// baseURL, typically the bucket's public endpoint or a CDN in front of it.
+type S3 struct {
+	client  *s3.Client
+	bucket  string
+	baseURL string
+}
+
+// NewS3 returns an S3 Storage that uploads to bucket via client and serves
+// objects from baseURL (with no trailing slash).
+func NewS3(client *s3.Client, bucket, baseURL string) *S3 {
+	return &S3{client: client, bucket: bucket, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Save uploads r to key in the configured bucket and returns baseURL/key.
+func (s *S3) Save(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        r,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("storage: upload object: %w", err)
+	}
+
+	return s.baseURL + "/" + key, nil
+}
@@ -0,0 +1,17 @@
+// Package storage abstracts where uploaded files, such as user avatars, are
+// persisted so the application can move between local disk and a cloud
+// object store without changing callers.
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage saves a single named object and reports the public URL it can
+// later be fetched from.
+type Storage interface {
+	// Save writes the contents of r under key and returns the URL the
+	// object is publicly reachable at.
+	Save(ctx context.Context, key string, r io.Reader, contentType string) (url string, err error)
+}
@@ -0,0 +1,46 @@
+package tickets
+
+import "context"
+
+// PaymentProvider creates hosted checkout sessions for paid tickets,
+// verifies payment webhooks, and issues refunds. It is an interface so
+// tests and local development can swap in a no-op implementation without a
+// real payment provider.
+type PaymentProvider interface {
+	// CreateCheckoutSession starts a hosted checkout for a ticket priced at
+	// amountCents, redirecting to successURL or cancelURL once the buyer is
+	// done. It returns the URL to send the buyer to and an opaque session
+	// ID used later to reconcile the webhook and issue refunds.
+	CreateCheckoutSession(ctx context.Context, amountCents int, successURL, cancelURL string) (checkoutURL, sessionID string, err error)
+
+	// ParseWebhookEvent validates payload against signature and reports the
+	// session ID it concerns and whether it represents a completed,
+	// successful payment.
+	ParseWebhookEvent(payload []byte, signature string) (sessionID string, succeeded bool, err error)
+
+	// Refund returns the payment behind sessionID to the buyer.
+	Refund(ctx context.Context, sessionID string) error
+}
+
+// NoopPaymentProvider treats every checkout as immediately successful,
+// skipping a real payment provider entirely. It is the default
+// PaymentProvider until a real one is wired in.
+type NoopPaymentProvider struct{}
+
+// CreateCheckoutSession returns successURL as the checkout URL with a
+// synthetic session ID, so the caller-side flow works end to end without a
+// real payment provider.
+func (NoopPaymentProvider) CreateCheckoutSession(ctx context.Context, amountCents int, successURL, cancelURL string) (string, string, error) {
+	return successURL, "noop-session", nil
+}
+
+// ParseWebhookEvent always fails: NoopPaymentProvider never sends webhooks,
+// so nothing should call this.
+func (NoopPaymentProvider) ParseWebhookEvent(payload []byte, signature string) (string, bool, error) {
+	return "", false, errNoopProvider
+}
+
+// Refund is a no-op that always succeeds.
+func (NoopPaymentProvider) Refund(ctx context.Context, sessionID string) error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package tickets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/checkout/session"
+	"github.com/stripe/stripe-go/v76/refund"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+// StripeProvider is a PaymentProvider backed by Stripe Checkout.
+type StripeProvider struct {
+	webhookSecret string
+}
+
+// NewStripeProvider returns a StripeProvider that authenticates with
+// secretKey and verifies webhooks against webhookSecret.
+func NewStripeProvider(secretKey, webhookSecret string) *StripeProvider {
+	stripe.Key = secretKey
+	return &StripeProvider{webhookSecret: webhookSecret}
+}
+
+// CreateCheckoutSession starts a Stripe Checkout session for a single
+// ticket priced at amountCents (USD).
+func (p *StripeProvider) CreateCheckoutSession(ctx context.Context, amountCents int, successURL, cancelURL string) (string, string, error) {
+	params := &stripe.CheckoutSessionParams{
+		Mode:       stripe.String(string(stripe.CheckoutSessionModePayment)),
+		SuccessURL: stripe.String(successURL),
+		CancelURL:  stripe.String(cancelURL),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				Quantity: stripe.Int64(1),
+				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+					Currency:   stripe.String(string(stripe.CurrencyUSD)),
+					UnitAmount: stripe.Int64(int64(amountCents)),
+					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+						Name: stripe.String("Event ticket"),
+					},
+				},
+			},
+		},
+	}
+
+	sess, err := session.New(params)
+	if err != nil {
+		return "", "", fmt.Errorf("tickets: create stripe checkout session: %w", err)
+	}
+	return sess.URL, sess.ID, nil
+}
+
+// ParseWebhookEvent verifies payload against signature and reports whether
+// it is a completed checkout session, along with that session's ID.
+func (p *StripeProvider) ParseWebhookEvent(payload []byte, signature string) (string, bool, error) {
+	event, err := webhook.ConstructEvent(payload, signature, p.webhookSecret)
+	if err != nil {
+		return "", false, fmt.Errorf("tickets: verify stripe webhook: %w", err)
+	}
+	if event.Type != "checkout.session.completed" {
+		return "", false, nil
+	}
+
+	var sess stripe.CheckoutSession
+	if err := json.Unmarshal(event.Data.Raw, &sess); err != nil {
+		return "", false, fmt.Errorf("tickets: parse stripe webhook payload: %w", err)
+	}
+	return sess.ID, true, nil
+}
+
+// Refund refunds the payment intent behind a Stripe Checkout session.
+func (p *StripeProvider) Refund(ctx context.Context, sessionID string) error {
+	sess, err := session.Get(sessionID, nil)
+	if err != nil {
+		return fmt.Errorf("tickets: fetch stripe session: %w", err)
+	}
+	if sess.PaymentIntent == nil {
+		return fmt.Errorf("tickets: stripe session %s has no payment to refund", sessionID)
+	}
+
+	if _, err := refund.New(&stripe.RefundParams{PaymentIntent: stripe.String(sess.PaymentIntent.ID)}); err != nil {
+		return fmt.Errorf("tickets: refund stripe payment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,409 @@
+// Package tickets manages ticket types and issued tickets for events.
+package tickets
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dohasheriff/tools-3/internal/events"
+)
+
+// Ticket statuses. Free tickets (price zero) go straight to StatusIssued.
+// Paid tickets start StatusPendingPayment and move to StatusIssued once
+// ConfirmPayment processes a successful checkout webhook. StatusIssued
+// tickets become StatusValidated once an organizer validates them at the
+// door, or StatusRefunded if the event is cancelled and the payment behind
+// them is refunded.
+const (
+	StatusPendingPayment = "pending_payment"
+	StatusIssued         = "issued"
+	StatusValidated      = "validated"
+	StatusRefunded       = "refunded"
+)
+
+// ErrNotFound is returned when a ticket type or ticket lookup finds no
+// matching row.
+var ErrNotFound = errors.New("tickets: not found")
+
+// ErrForbidden is returned when the caller is not the event's organizer.
+var ErrForbidden = errors.New("tickets: not permitted")
+
+// ErrSoldOut is returned by Claim when a ticket type has no tickets left.
+var ErrSoldOut = errors.New("tickets: sold out")
+
+// ErrAlreadyValidated is returned by Validate when the ticket has already
+// been validated.
+var ErrAlreadyValidated = errors.New("tickets: ticket already validated")
+
+// ErrPaymentPending is returned by Validate when a ticket's payment has not
+// yet been confirmed.
+var ErrPaymentPending = errors.New("tickets: ticket payment not yet confirmed")
+
+// errNoopProvider is returned by NoopPaymentProvider.ParseWebhookEvent,
+// which should never actually be called.
+var errNoopProvider = errors.New("tickets: noop payment provider received a webhook")
+
+// TicketType is a category of ticket an organizer offers for an event, with
+// a fixed quantity and price.
+type TicketType struct {
+	ID         int64
+	EventID    int64
+	Name       string
+	Quantity   int
+	PriceCents int
+	CreatedAt  time.Time
+}
+
+// Ticket is a single ticket claimed by an attendee, paid for or not.
+type Ticket struct {
+	ID           int64
+	EventID      int64
+	TicketTypeID int64
+	UserID       int64
+	Code         string
+	Status       string
+	AmountCents  int
+	// PaymentRef is the payment provider's checkout session ID, set once a
+	// paid ticket's checkout session is created. It is nil for free tickets.
+	PaymentRef *string
+	CreatedAt  time.Time
+}
+
+// CheckoutSession is returned by Claim for a paid ticket: the ticket record
+// (pending payment) and the URL to send the buyer to complete checkout.
+type CheckoutSession struct {
+	Ticket      *Ticket
+	CheckoutURL string
+}
+
+// Store persists ticket types and tickets, deferring to events for
+// organizer checks and attendance, and to payments for checkout and
+// refunds.
+type Store struct {
+	db       *sql.DB
+	events   *events.Store
+	payments PaymentProvider
+}
+
+// NewStore returns a Store backed by db, using payments to process paid
+// tickets.
+func NewStore(db *sql.DB, eventStore *events.Store, payments PaymentProvider) *Store {
+	return &Store{db: db, events: eventStore, payments: payments}
+}
+
+// CreateTicketType adds a ticket type to eventID, which only the event's
+// organizer may do.
+func (s *Store) CreateTicketType(ctx context.Context, eventID, organizerID int64, name string, quantity, priceCents int) (*TicketType, error) {
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.OrganizerID != organizerID {
+		return nil, ErrForbidden
+	}
+
+	tt := &TicketType{EventID: eventID, Name: name, Quantity: quantity, PriceCents: priceCents}
+	row := s.db.QueryRowContext(ctx,
+		`INSERT INTO ticket_types (event_id, name, quantity, price_cents)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`,
+		eventID, name, quantity, priceCents,
+	)
+	if err := row.Scan(&tt.ID, &tt.CreatedAt); err != nil {
+		return nil, fmt.Errorf("tickets: create ticket type: %w", err)
+	}
+	return tt, nil
+}
+
+// ListTicketTypes returns eventID's ticket types, oldest first.
+func (s *Store) ListTicketTypes(ctx context.Context, eventID int64) ([]*TicketType, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, event_id, name, quantity, price_cents, created_at
+		 FROM ticket_types WHERE event_id = $1 ORDER BY created_at ASC, id ASC`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tickets: list ticket types: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*TicketType
+	for rows.Next() {
+		tt := &TicketType{}
+		if err := rows.Scan(&tt.ID, &tt.EventID, &tt.Name, &tt.Quantity, &tt.PriceCents, &tt.CreatedAt); err != nil {
+			return nil, fmt.Errorf("tickets: list ticket types scan: %w", err)
+		}
+		out = append(out, tt)
+	}
+	return out, rows.Err()
+}
+
+// Claim reserves userID a ticket of ticketTypeID for eventID, failing with
+// ErrSoldOut once the type's quantity has all been claimed. Free ticket
+// types issue the ticket immediately and add userID as a "going" attendee.
+// Paid ticket types return a checkout URL instead; the ticket stays
+// StatusPendingPayment and attendance is granted by ConfirmPayment once the
+// checkout webhook confirms payment.
+func (s *Store) Claim(ctx context.Context, eventID, ticketTypeID, userID int64, successURL, cancelURL string) (*CheckoutSession, error) {
+	t, priceCents, err := s.reserveTicket(ctx, eventID, ticketTypeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if priceCents == 0 {
+		if _, err := s.events.JoinEvent(ctx, eventID, userID, events.StatusGoing); err != nil {
+			return nil, fmt.Errorf("tickets: claim: join event: %w", err)
+		}
+		return &CheckoutSession{Ticket: t}, nil
+	}
+
+	checkoutURL, sessionID, err := s.payments.CreateCheckoutSession(ctx, priceCents, successURL, cancelURL)
+	if err != nil {
+		return nil, fmt.Errorf("tickets: claim: create checkout session: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE tickets SET payment_ref = $1 WHERE id = $2`, sessionID, t.ID,
+	); err != nil {
+		return nil, fmt.Errorf("tickets: claim: record payment ref: %w", err)
+	}
+	t.PaymentRef = &sessionID
+
+	return &CheckoutSession{Ticket: t, CheckoutURL: checkoutURL}, nil
+}
+
+// reserveTicket locks ticketTypeID, checks remaining quantity, and inserts
+// a ticket row for userID: StatusIssued if priceCents is zero, otherwise
+// StatusPendingPayment. It returns the inserted ticket and the type's price.
+func (s *Store) reserveTicket(ctx context.Context, eventID, ticketTypeID, userID int64) (*Ticket, int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("tickets: claim: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var quantity, priceCents int
+	row := tx.QueryRowContext(ctx,
+		`SELECT quantity, price_cents FROM ticket_types WHERE id = $1 AND event_id = $2 FOR UPDATE`,
+		ticketTypeID, eventID,
+	)
+	switch err := row.Scan(&quantity, &priceCents); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, 0, ErrNotFound
+	case err != nil:
+		return nil, 0, fmt.Errorf("tickets: claim: lock ticket type: %w", err)
+	}
+
+	var issued int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT count(*) FROM tickets WHERE ticket_type_id = $1`, ticketTypeID,
+	).Scan(&issued); err != nil {
+		return nil, 0, fmt.Errorf("tickets: claim: count issued: %w", err)
+	}
+	if issued >= quantity {
+		return nil, 0, ErrSoldOut
+	}
+
+	code, err := generateTicketCode()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	status := StatusIssued
+	if priceCents > 0 {
+		status = StatusPendingPayment
+	}
+
+	t := &Ticket{EventID: eventID, TicketTypeID: ticketTypeID, UserID: userID, Code: code, Status: status, AmountCents: priceCents}
+	row = tx.QueryRowContext(ctx,
+		`INSERT INTO tickets (ticket_type_id, event_id, user_id, code, status, amount_cents)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		ticketTypeID, eventID, userID, code, status, priceCents,
+	)
+	if err := row.Scan(&t.ID, &t.CreatedAt); err != nil {
+		return nil, 0, fmt.Errorf("tickets: claim: insert ticket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("tickets: claim: commit: %w", err)
+	}
+	return t, priceCents, nil
+}
+
+// ConfirmPayment finalizes the ticket behind sessionID once its checkout
+// payment succeeds: marking it StatusIssued and adding its buyer as a
+// "going" attendee. It is a no-op if the session is unknown or the ticket
+// is not awaiting payment, so a retried or duplicate webhook delivery is
+// harmless.
+func (s *Store) ConfirmPayment(ctx context.Context, sessionID string) error {
+	var id, eventID, ticketUserID int64
+	var status string
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, event_id, user_id, status FROM tickets WHERE payment_ref = $1`, sessionID,
+	)
+	switch err := row.Scan(&id, &eventID, &ticketUserID, &status); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("tickets: confirm payment: %w", err)
+	}
+	if status != StatusPendingPayment {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE tickets SET status = $1 WHERE id = $2`, StatusIssued, id,
+	); err != nil {
+		return fmt.Errorf("tickets: confirm payment: update: %w", err)
+	}
+
+	if _, err := s.events.JoinEvent(ctx, eventID, ticketUserID, events.StatusGoing); err != nil {
+		return fmt.Errorf("tickets: confirm payment: join event: %w", err)
+	}
+	return nil
+}
+
+// HandleWebhook verifies payload against signature and, if it reports a
+// completed checkout, confirms the corresponding ticket's payment.
+func (s *Store) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	sessionID, succeeded, err := s.payments.ParseWebhookEvent(payload, signature)
+	if err != nil {
+		return fmt.Errorf("tickets: handle webhook: %w", err)
+	}
+	if !succeeded {
+		return nil
+	}
+	return s.ConfirmPayment(ctx, sessionID)
+}
+
+// RefundTicketsForEvent refunds every paid, issued ticket for eventID and
+// marks them StatusRefunded, for use when an event is cancelled. It
+// attempts every refund even if one fails, returning all errors joined.
+func (s *Store) RefundTicketsForEvent(ctx context.Context, eventID int64) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, payment_ref FROM tickets
+		 WHERE event_id = $1 AND status = $2 AND payment_ref IS NOT NULL`,
+		eventID, StatusIssued,
+	)
+	if err != nil {
+		return fmt.Errorf("tickets: refund for event: %w", err)
+	}
+	defer rows.Close()
+
+	type refundable struct {
+		id         int64
+		paymentRef string
+	}
+	var toRefund []refundable
+	for rows.Next() {
+		var r refundable
+		if err := rows.Scan(&r.id, &r.paymentRef); err != nil {
+			return fmt.Errorf("tickets: refund for event scan: %w", err)
+		}
+		toRefund = append(toRefund, r)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, r := range toRefund {
+		if err := s.payments.Refund(ctx, r.paymentRef); err != nil {
+			errs = append(errs, fmt.Errorf("tickets: refund ticket %d: %w", r.id, err))
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx,
+			`UPDATE tickets SET status = $1 WHERE id = $2`, StatusRefunded, r.id,
+		); err != nil {
+			errs = append(errs, fmt.Errorf("tickets: mark ticket %d refunded: %w", r.id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ListTickets returns eventID's issued tickets, oldest first, which only
+// the event's organizer may view.
+func (s *Store) ListTickets(ctx context.Context, eventID, organizerID int64) ([]*Ticket, error) {
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.OrganizerID != organizerID {
+		return nil, ErrForbidden
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, event_id, ticket_type_id, user_id, code, status, amount_cents, payment_ref, created_at
+		 FROM tickets WHERE event_id = $1 ORDER BY created_at ASC, id ASC`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tickets: list tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Ticket
+	for rows.Next() {
+		t := &Ticket{}
+		if err := rows.Scan(&t.ID, &t.EventID, &t.TicketTypeID, &t.UserID, &t.Code, &t.Status, &t.AmountCents, &t.PaymentRef, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("tickets: list tickets scan: %w", err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// Validate marks code used for eventID validated, which only the event's
+// organizer may do. It fails with ErrAlreadyValidated if the ticket was
+// already validated, or ErrPaymentPending if it is still awaiting payment.
+func (s *Store) Validate(ctx context.Context, eventID, organizerID int64, code string) (*Ticket, error) {
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if e.OrganizerID != organizerID {
+		return nil, ErrForbidden
+	}
+
+	t := &Ticket{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, event_id, ticket_type_id, user_id, code, status, amount_cents, payment_ref, created_at
+		 FROM tickets WHERE event_id = $1 AND code = $2`,
+		eventID, code,
+	)
+	if err := row.Scan(&t.ID, &t.EventID, &t.TicketTypeID, &t.UserID, &t.Code, &t.Status, &t.AmountCents, &t.PaymentRef, &t.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("tickets: validate: %w", err)
+	}
+	switch t.Status {
+	case StatusValidated:
+		return nil, ErrAlreadyValidated
+	case StatusPendingPayment:
+		return nil, ErrPaymentPending
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE tickets SET status = $1 WHERE id = $2`, StatusValidated, t.ID,
+	); err != nil {
+		return nil, fmt.Errorf("tickets: validate: update: %w", err)
+	}
+	t.Status = StatusValidated
+	return t, nil
+}
+
+// generateTicketCode returns a short random hex code used as a ticket's
+// scannable identifier.
+func generateTicketCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("tickets: generate ticket code: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
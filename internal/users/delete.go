@@ -0,0 +1,66 @@
+package users
+
+import (
+	"context"
+	"fmt"
+)
+
+// eventStatusCancelled and invitationStatusPending mirror the status values
+// owned by the events and invitations packages. Account deletion touches
+// those tables directly so the whole cleanup can run in one transaction;
+// users does not import either package to avoid a dependency cycle risk as
+// this codebase grows.
+const (
+	eventStatusCancelled    = "cancelled"
+	invitationStatusPending = "pending"
+)
+
+// Delete anonymizes userID's account and cleans up the data that would
+// otherwise reference it: their organized events are cancelled, their
+// attendee rows are removed, and their pending invitations (sent or
+// received) are removed. The account row itself is kept, scrubbed of
+// personal information, so historical references (past attendance records,
+// check-in logs, audit trails) left in other tables remain valid. All of
+// this happens inside a single transaction.
+func (s *Store) Delete(ctx context.Context, userID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("users: delete: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	anonymizedEmail := fmt.Sprintf("deleted-user-%d@deleted.invalid", userID)
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE users
+		 SET email = $1, password_hash = '', role = $2, totp_secret = NULL, totp_enabled = false, username = NULL, display_name = '', avatar_url = ''
+		 WHERE id = $3`,
+		anonymizedEmail, RoleMember, userID,
+	); err != nil {
+		return fmt.Errorf("users: delete: anonymize account: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE events SET status = $1 WHERE organizer_id = $2 AND status != $1`,
+		eventStatusCancelled, userID,
+	); err != nil {
+		return fmt.Errorf("users: delete: cancel organized events: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM event_attendees WHERE user_id = $1`, userID,
+	); err != nil {
+		return fmt.Errorf("users: delete: remove attendee rows: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM invitations WHERE status = $1 AND (inviter_id = $2 OR invitee_user_id = $2)`,
+		invitationStatusPending, userID,
+	); err != nil {
+		return fmt.Errorf("users: delete: remove pending invitations: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("users: delete: commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const searchResultLimit = 10
+
+// SearchResult is the public-safe projection of a User returned by Search.
+type SearchResult struct {
+	ID          int64
+	Email       string
+	Username    sql.NullString
+	DisplayName string
+	AvatarURL   string
+}
+
+// Search returns up to searchResultLimit accounts whose email, username, or
+// display name starts with prefix, ordered by email.
+func (s *Store) Search(ctx context.Context, prefix string) ([]SearchResult, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, email, username, display_name, avatar_url FROM users
+		 WHERE email LIKE $1 || '%' OR username LIKE $1 || '%' OR display_name LIKE $1 || '%'
+		 ORDER BY email
+		 LIMIT $2`,
+		prefix, searchResultLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("users: search: %w", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		if err := rows.Scan(&r.ID, &r.Email, &r.Username, &r.DisplayName, &r.AvatarURL); err != nil {
+			return nil, fmt.Errorf("users: scan search result: %w", err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("users: search: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,275 @@
+// Package users manages user accounts.
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Role identifies the permission level of a user account.
+type Role string
+
+const (
+	// RoleMember is the default role held by every registered account.
+	RoleMember Role = "member"
+	// RoleAdmin grants access to admin-only endpoints.
+	RoleAdmin Role = "admin"
+)
+
+// User is a registered account.
+type User struct {
+	ID                int64
+	Email             string
+	PasswordHash      string
+	Role              Role
+	AttendanceVisible bool
+	EmailVerified     bool
+	TOTPSecret        sql.NullString
+	TOTPEnabled       bool
+	FailedLoginCount  int
+	LockedUntil       sql.NullTime
+	Username          sql.NullString
+	DisplayName       string
+	AvatarURL         string
+	PhoneNumber       sql.NullString
+	PhoneVerified     bool
+	CreatedAt         time.Time
+}
+
+// ErrUsernameTaken is returned when a username is already in use.
+var ErrUsernameTaken = errors.New("users: username already taken")
+
+// ErrNotFound is returned when a user lookup finds no matching row.
+var ErrNotFound = errors.New("users: not found")
+
+// Store persists users in Postgres.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore returns a Store backed by db.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create inserts a new user and returns it with its assigned ID. username
+// and displayName may be empty; an empty username is stored as unset rather
+// than claiming the empty string.
+func (s *Store) Create(ctx context.Context, email, passwordHash, username, displayName string) (*User, error) {
+	u := &User{Email: email, PasswordHash: passwordHash, Role: RoleMember, AttendanceVisible: true, DisplayName: displayName}
+	row := s.db.QueryRowContext(ctx,
+		`INSERT INTO users (email, password_hash, username, display_name) VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		email, passwordHash, nullableString(username), displayName,
+	)
+	if err := row.Scan(&u.ID, &u.CreatedAt); err != nil {
+		return nil, fmt.Errorf("users: create: %w", err)
+	}
+	if username != "" {
+		u.Username = sql.NullString{String: username, Valid: true}
+	}
+	return u, nil
+}
+
+// UpdateProfile changes userID's username and display name. An empty
+// username clears it. ErrUsernameTaken is returned if username is already
+// claimed by a different account.
+func (s *Store) UpdateProfile(ctx context.Context, userID int64, username, displayName string) error {
+	if username != "" {
+		var existingID int64
+		row := s.db.QueryRowContext(ctx, `SELECT id FROM users WHERE username = $1`, username)
+		switch err := row.Scan(&existingID); {
+		case err == nil && existingID != userID:
+			return ErrUsernameTaken
+		case err != nil && !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("users: check username: %w", err)
+		}
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET username = $1, display_name = $2 WHERE id = $3`,
+		nullableString(username), displayName, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: update profile: %w", err)
+	}
+	return nil
+}
+
+// UpdateAvatarURL sets userID's avatar URL, as returned by an upload through
+// the configured storage backend.
+func (s *Store) UpdateAvatarURL(ctx context.Context, userID int64, avatarURL string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET avatar_url = $1 WHERE id = $2`, avatarURL, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: update avatar url: %w", err)
+	}
+	return nil
+}
+
+// nullableString returns s as a valid sql.NullString, or an invalid one if s
+// is empty, so optional text columns store NULL instead of claiming the
+// empty string.
+func nullableString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
+// SetRole changes userID's role, such as promoting an account to admin.
+func (s *Store) SetRole(ctx context.Context, userID int64, role Role) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET role = $1 WHERE id = $2`, role, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: set role: %w", err)
+	}
+	return nil
+}
+
+// UpdatePasswordHash replaces userID's stored password hash.
+func (s *Store) UpdatePasswordHash(ctx context.Context, userID int64, passwordHash string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET password_hash = $1 WHERE id = $2`, passwordHash, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: update password hash: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailVerified flags userID's account as having a confirmed email
+// address.
+func (s *Store) MarkEmailVerified(ctx context.Context, userID int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET email_verified = true WHERE id = $1`, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: mark email verified: %w", err)
+	}
+	return nil
+}
+
+// SetPhoneNumber changes userID's phone number and clears phone_verified,
+// since a new number hasn't been confirmed yet.
+func (s *Store) SetPhoneNumber(ctx context.Context, userID int64, phoneNumber string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET phone_number = $1, phone_verified = false WHERE id = $2`,
+		nullableString(phoneNumber), userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: set phone number: %w", err)
+	}
+	return nil
+}
+
+// MarkPhoneVerified flags userID's account as having a confirmed phone
+// number.
+func (s *Store) MarkPhoneVerified(ctx context.Context, userID int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET phone_verified = true WHERE id = $1`, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: mark phone verified: %w", err)
+	}
+	return nil
+}
+
+// EnableTOTP stores secret as userID's TOTP secret and marks two-factor
+// authentication as enabled.
+func (s *Store) EnableTOTP(ctx context.Context, userID int64, secret string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET totp_secret = $1, totp_enabled = true WHERE id = $2`, secret, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: enable totp: %w", err)
+	}
+	return nil
+}
+
+// GetByEmail returns the user with the given email.
+func (s *Store) GetByEmail(ctx context.Context, email string) (*User, error) {
+	u := &User{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, email, password_hash, role, attendance_visible, email_verified, totp_secret, totp_enabled, failed_login_attempts, locked_until, username, display_name, avatar_url, phone_number, phone_verified, created_at FROM users WHERE email = $1`, email,
+	)
+	if err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.AttendanceVisible, &u.EmailVerified, &u.TOTPSecret, &u.TOTPEnabled, &u.FailedLoginCount, &u.LockedUntil, &u.Username, &u.DisplayName, &u.AvatarURL, &u.PhoneNumber, &u.PhoneVerified, &u.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("users: get by email: %w", err)
+	}
+	return u, nil
+}
+
+// GetByUsername returns the user with the given username.
+func (s *Store) GetByUsername(ctx context.Context, username string) (*User, error) {
+	u := &User{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, email, password_hash, role, attendance_visible, email_verified, totp_secret, totp_enabled, failed_login_attempts, locked_until, username, display_name, avatar_url, phone_number, phone_verified, created_at FROM users WHERE username = $1`, username,
+	)
+	if err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.AttendanceVisible, &u.EmailVerified, &u.TOTPSecret, &u.TOTPEnabled, &u.FailedLoginCount, &u.LockedUntil, &u.Username, &u.DisplayName, &u.AvatarURL, &u.PhoneNumber, &u.PhoneVerified, &u.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("users: get by username: %w", err)
+	}
+	return u, nil
+}
+
+// GetByID returns the user with the given ID.
+func (s *Store) GetByID(ctx context.Context, id int64) (*User, error) {
+	u := &User{}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, email, password_hash, role, attendance_visible, email_verified, totp_secret, totp_enabled, failed_login_attempts, locked_until, username, display_name, avatar_url, phone_number, phone_verified, created_at FROM users WHERE id = $1`, id,
+	)
+	if err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.AttendanceVisible, &u.EmailVerified, &u.TOTPSecret, &u.TOTPEnabled, &u.FailedLoginCount, &u.LockedUntil, &u.Username, &u.DisplayName, &u.AvatarURL, &u.PhoneNumber, &u.PhoneVerified, &u.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("users: get by id: %w", err)
+	}
+	return u, nil
+}
+
+// RecordFailedLogin increments userID's failed login counter and returns
+// the updated count.
+func (s *Store) RecordFailedLogin(ctx context.Context, userID int64) (int, error) {
+	var attempts int
+	row := s.db.QueryRowContext(ctx,
+		`UPDATE users SET failed_login_attempts = failed_login_attempts + 1 WHERE id = $1 RETURNING failed_login_attempts`,
+		userID,
+	)
+	if err := row.Scan(&attempts); err != nil {
+		return 0, fmt.Errorf("users: record failed login: %w", err)
+	}
+	return attempts, nil
+}
+
+// LockUntil locks userID's account against further login attempts until
+// until.
+func (s *Store) LockUntil(ctx context.Context, userID int64, until time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET locked_until = $1 WHERE id = $2`, until, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: lock account: %w", err)
+	}
+	return nil
+}
+
+// ResetFailedLogins clears userID's failed login counter and any lockout,
+// called after a successful login.
+func (s *Store) ResetFailedLogins(ctx context.Context, userID int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE users SET failed_login_attempts = 0, locked_until = NULL WHERE id = $1`, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("users: reset failed logins: %w", err)
+	}
+	return nil
+}